@@ -0,0 +1,100 @@
+// Package airports holds the airport reference data shipped with the binary,
+// used to validate where an aircraft is parked and to search ads by distance.
+package airports
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mean earth radius used for great-circle distances
+const earthRadiusKM = 6371.0
+
+//go:embed airports.csv
+var dataset string
+
+type Airport struct {
+	Code    string
+	Name    string
+	Country string
+	Lat     float64
+	Lon     float64
+}
+
+var byCode = load()
+
+func load() map[string]Airport {
+	records, err := csv.NewReader(strings.NewReader(dataset)).ReadAll()
+	if err != nil {
+		panic("airports: invalid dataset: " + err.Error())
+	}
+
+	airports := make(map[string]Airport, len(records))
+	for _, r := range records[1:] {
+		lat, err := strconv.ParseFloat(r[3], 64)
+		if err != nil {
+			panic("airports: invalid latitude for " + r[0])
+		}
+		lon, err := strconv.ParseFloat(r[4], 64)
+		if err != nil {
+			panic("airports: invalid longitude for " + r[0])
+		}
+		airports[r[0]] = Airport{Code: r[0], Name: r[1], Country: r[2], Lat: lat, Lon: lon}
+	}
+	return airports
+}
+
+// Normalize upper-cases and trims an ICAO code.
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func Lookup(code string) (Airport, bool) {
+	a, ok := byCode[Normalize(code)]
+	return a, ok
+}
+
+// InCountry returns the codes of the airports in an ISO 3166-1 alpha-2 country.
+func InCountry(country string) []string {
+	country = strings.ToUpper(strings.TrimSpace(country))
+	return codes(func(a Airport) bool { return a.Country == country })
+}
+
+// Within returns the codes of the airports at most km kilometers away from
+// the given airport, including itself. It returns nil for an unknown code.
+func Within(code string, km float64) []string {
+	origin, ok := Lookup(code)
+	if !ok {
+		return nil
+	}
+	return codes(func(a Airport) bool { return Distance(origin, a) <= km })
+}
+
+// Distance is the great-circle distance between two airports in kilometers.
+func Distance(a, b Airport) float64 {
+	lat1, lat2 := radians(a.Lat), radians(b.Lat)
+	dLat := lat2 - lat1
+	dLon := radians(b.Lon - a.Lon)
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKM * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func codes(match func(Airport) bool) []string {
+	result := []string{}
+	for code, a := range byCode {
+		if match(a) {
+			result = append(result, code)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,88 @@
+// Package apperror holds the typed errors of the domain. Datastores and
+// handlers return them and the HTTP layer picks the status from their kind.
+package apperror
+
+import "Airplane-Divar/models"
+
+type Kind uint8
+
+const (
+	KindInternal Kind = iota
+	KindBadRequest
+	KindUnauthorized
+	KindForbidden
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindUpstream
+)
+
+// Generic codes, domain errors carry their own more specific ones.
+const (
+	CodeInternal         = "internal_error"
+	CodeInvalidParameter = "invalid_parameter"
+	CodeInvalidBody      = "invalid_body"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeValidation       = "validation_failed"
+	CodeUpstream         = "upstream_failed"
+)
+
+// Error is a domain error with a machine readable code.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Details []models.FieldError
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func New(kind Kind, code string, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func BadRequest(code string, message string) *Error {
+	return New(KindBadRequest, code, message)
+}
+
+func Unauthorized(code string, message string) *Error {
+	return New(KindUnauthorized, code, message)
+}
+
+func Forbidden(code string, message string) *Error {
+	return New(KindForbidden, code, message)
+}
+
+func NotFound(code string, message string) *Error {
+	return New(KindNotFound, code, message)
+}
+
+func Conflict(code string, message string) *Error {
+	return New(KindConflict, code, message)
+}
+
+func Upstream(message string) *Error {
+	return New(KindUpstream, CodeUpstream, message)
+}
+
+func Internal(message string) *Error {
+	return New(KindInternal, CodeInternal, message)
+}
+
+// InvalidBody is returned for a request body that can't be used.
+func InvalidBody(message string) *Error {
+	return BadRequest(CodeInvalidBody, message)
+}
+
+// InvalidParameter is returned for a malformed path or query parameter.
+func InvalidParameter(name string) *Error {
+	return BadRequest(CodeInvalidParameter, "invalid parameter "+name)
+}
+
+// Validation is returned for a request body with problems in its fields.
+func Validation(details []models.FieldError) *Error {
+	return &Error{Kind: KindValidation, Code: CodeValidation, Message: "Invalid request", Details: details}
+}
@@ -13,7 +13,9 @@ type (
 		App  `yaml:"app"`
 		HTTP `yaml:"http"`
 		PG
-		VCode `yaml:"vcode"`
+		VCode   `yaml:"vcode"`
+		Storage `yaml:"storage"`
+		Signing
 	}
 
 	App struct {
@@ -42,6 +44,16 @@ type (
 		ADMIN_CODE  string `env:"ADMIN_CODE"`
 		EXPERT_CODE string `env:"EXPERT_CODE"`
 	}
+
+	// Storage -.
+	Storage struct {
+		DIR string `yaml:"dir" env:"STORAGE_DIR" env-default:"./media/attachments"`
+	}
+
+	// Signing -.
+	Signing struct {
+		EXPERT_KEY string `env:"EXPERT_SIGNING_KEY"`
+	}
 )
 
 // NewConfig returns app config.
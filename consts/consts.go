@@ -17,6 +17,56 @@ type AdStatus string
 const (
 	INACTIVE AdStatus = "Inactive"
 	ACTIVE   AdStatus = "Active"
+	RESERVED AdStatus = "Reserved"
+	SOLD     AdStatus = "Sold"
+	HIDDEN   AdStatus = "Hidden"
+)
+
+// Abuse report reasons
+const (
+	REPORT_FRAUD          = "fraud"
+	REPORT_DUPLICATE      = "duplicate"
+	REPORT_WRONG_CATEGORY = "wrong_category"
+	REPORT_OFFENSIVE      = "offensive"
+)
+
+type ReportStatus string
+
+const (
+	REPORT_OPEN      ReportStatus = "Open"
+	REPORT_DISMISSED ReportStatus = "Dismissed"
+	REPORT_UPHELD    ReportStatus = "Upheld"
+)
+
+type AppointmentStatus string
+
+const (
+	APPOINTMENT_SCHEDULED AppointmentStatus = "Scheduled"
+	APPOINTMENT_CANCELLED AppointmentStatus = "Cancelled"
+)
+
+// Listing modes of an ad
+const (
+	LISTING_FIXED   = "fixed"
+	LISTING_AUCTION = "auction"
+)
+
+type AuctionStatus string
+
+const (
+	AUCTION_OPEN   AuctionStatus = "Open"
+	AUCTION_CLOSED AuctionStatus = "Closed"
+)
+
+type OfferStatus string
+
+const (
+	OFFER_PENDING   OfferStatus = "Pending"
+	OFFER_COUNTERED OfferStatus = "Countered"
+	OFFER_ACCEPTED  OfferStatus = "Accepted"
+	OFFER_REJECTED  OfferStatus = "Rejected"
+	OFFER_EXPIRED   OfferStatus = "Expired"
+	OFFER_COMPLETED OfferStatus = "Completed"
 )
 
 func (ct *Status) Scan(value interface{}) error {
@@ -28,9 +78,108 @@ func (ct Status) Value() (driver.Value, error) {
 	return string(ct), nil
 }
 
+// Category attribute types
+const (
+	ATTRIBUTE_STRING  = "string"
+	ATTRIBUTE_NUMBER  = "number"
+	ATTRIBUTE_BOOLEAN = "boolean"
+)
+
+// Validation error codes
+const (
+	VALIDATION_INVALID_JSON = "invalid_json"
+	VALIDATION_REQUIRED     = "required"
+	VALIDATION_TYPE         = "invalid_type"
+	VALIDATION_INVALID      = "invalid"
+	VALIDATION_OUT_OF_RANGE = "out_of_range"
+	VALIDATION_NOT_ALLOWED  = "not_allowed"
+	VALIDATION_UNKNOWN      = "unknown"
+	VALIDATION_TAKEN        = "taken"
+)
+
+// Notification kinds
+const (
+	NOTIFICATION_AUCTION_WON    = "auction_won"
+	NOTIFICATION_AUCTION_CLOSED = "auction_closed"
+
+	NOTIFICATION_BOOKMARK_PRICE         = "bookmark_price_changed"
+	NOTIFICATION_BOOKMARK_SOLD          = "bookmark_sold"
+	NOTIFICATION_BOOKMARK_RESERVED      = "bookmark_reserved"
+	NOTIFICATION_BOOKMARK_DEACTIVATED   = "bookmark_deactivated"
+	NOTIFICATION_BOOKMARK_EXPERT_REPORT = "bookmark_expert_report"
+
+	NOTIFICATION_SLA_ESCALATED = "sla_escalated"
+)
+
+// Inspection checklist
+const (
+	INSPECTION_AIRFRAME     = "airframe"
+	INSPECTION_ENGINES      = "engines"
+	INSPECTION_LANDING_GEAR = "landing_gear"
+	INSPECTION_AVIONICS     = "avionics"
+	INSPECTION_CABIN        = "cabin"
+	INSPECTION_RECORDS      = "records"
+
+	SEVERITY_NONE     = "none"
+	SEVERITY_MINOR    = "minor"
+	SEVERITY_MAJOR    = "major"
+	SEVERITY_CRITICAL = "critical"
+
+	INSPECTION_MIN_RATING = 1
+	INSPECTION_MAX_RATING = 5
+)
+
+// Inspection slots
+const (
+	SLOT_MIN_MINUTES      = 30
+	SLOT_MAX_MINUTES      = 12 * 60
+	SLOT_MAX_DAYS_AHEAD   = 180
+	SLOT_LOCATION_MAX_LEN = 255
+
+	// CALENDAR_PAST_DAYS is how far back the calendar feeds go.
+	CALENDAR_PAST_DAYS = 90
+)
+
+// Expert reviews
+const (
+	REVIEW_MIN_RATING         = 1
+	REVIEW_MAX_RATING         = 5
+	REVIEW_MAX_COMMENT_LENGTH = 2000
+
+	// REVIEW_PRIOR_RATING and REVIEW_PRIOR_WEIGHT smooth the rating the
+	// assignment uses, an expert with a few reviews is pulled towards the
+	// prior so one review doesn't decide who gets requests.
+	REVIEW_PRIOR_RATING = 3.0
+	REVIEW_PRIOR_WEIGHT = 5
+)
+
+// Expert report attachments
+const ATTACHMENT_MAX_SIZE int64 = 10 << 20
+
+// ATTACHMENT_TYPES are the content types experts can attach, photos and
+// scanned documents.
+var ATTACHMENT_TYPES = []string{"image/jpeg", "image/png", "application/pdf"}
+
+// INSPECTION_SECTIONS are the sections of a checklist in report order.
+var INSPECTION_SECTIONS = []string{
+	INSPECTION_AIRFRAME, INSPECTION_ENGINES, INSPECTION_LANDING_GEAR,
+	INSPECTION_AVIONICS, INSPECTION_CABIN, INSPECTION_RECORDS,
+}
+
 // paginator
 const PAGE_SIZE int = 10
 
+// Bookmark listing
+const (
+	BOOKMARK_SORT_DATE  = "bookmarked_at"
+	BOOKMARK_SORT_PRICE = "price"
+
+	BOOKMARK_AVAILABLE   = "available"
+	BOOKMARK_UNAVAILABLE = "unavailable"
+	BOOKMARK_SOLD        = "sold"
+	BOOKMARK_REMOVED     = "removed"
+)
+
 // User roles
 const (
 	ROLE_MATIN   = "Matin"
@@ -41,17 +190,84 @@ const (
 
 // Logs
 const (
-	LOG_CREATE_AD       string = "create_ads"
-	LOG_ADMIN_WAIT      string = "send_to_admin"
-	LOG_ADMIN_APPROVE   string = "admin_approved"
-	LOG_ADMIN_REJECT    string = "admin_reject"
-	LOG_REPAIR_REQUEST  string = "repair_request"
-	LOG_REPAIR_RESULT   string = "repair_result"
-	LOG_EXPERT_REQUEST  string = "expert_request"
-	LOG_EXPERT_RESULT   string = "expert_result"
-	LOG_PAYMENT         string = "payment"
-	LOG_PAYMENT_SUCCESS string = "payment_success"
-	LOG_PAYMENT_FAILED  string = "payment_failed"
-	LOG_BOOKMARK        string = "bookmark"
-	LOG_BOOKMARK_REMOVE string = "bookmark_remove"
+	LOG_CREATE_AD                string = "create_ads"
+	LOG_ADMIN_WAIT               string = "send_to_admin"
+	LOG_ADMIN_APPROVE            string = "admin_approved"
+	LOG_ADMIN_REJECT             string = "admin_reject"
+	LOG_REPAIR_REQUEST           string = "repair_request"
+	LOG_REPAIR_RESULT            string = "repair_result"
+	LOG_EXPERT_REQUEST           string = "expert_request"
+	LOG_EXPERT_RESULT            string = "expert_result"
+	LOG_PAYMENT                  string = "payment"
+	LOG_PAYMENT_SUCCESS          string = "payment_success"
+	LOG_PAYMENT_FAILED           string = "payment_failed"
+	LOG_BOOKMARK                 string = "bookmark"
+	LOG_BOOKMARK_REMOVE          string = "bookmark_remove"
+	LOG_FEATURED                 string = "featured_request"
+	LOG_FEATURED_ACTIVE          string = "featured_activated"
+	LOG_OFFER_CREATE             string = "offer_created"
+	LOG_OFFER_COUNTER            string = "offer_countered"
+	LOG_OFFER_ACCEPT             string = "offer_accepted"
+	LOG_OFFER_REJECT             string = "offer_rejected"
+	LOG_AD_SOLD                  string = "ad_sold"
+	LOG_AUCTION_CREATE           string = "auction_created"
+	LOG_AUCTION_BID              string = "auction_bid"
+	LOG_AUCTION_CLOSED           string = "auction_closed"
+	LOG_AD_REPORTED              string = "ad_reported"
+	LOG_AD_AUTO_HIDDEN           string = "ad_auto_hidden"
+	LOG_REPORT_DISMISSED         string = "report_dismissed"
+	LOG_REPORT_UPHELD            string = "report_upheld"
+	LOG_BOOKMARK_SHARE_CREATE    string = "bookmark_share_created"
+	LOG_BOOKMARK_SHARE_REVOKE    string = "bookmark_share_revoked"
+	LOG_BOOKMARK_SHARE_VIEW      string = "bookmark_share_viewed"
+	LOG_EXPERT_ASSIGNED          string = "expert_assigned"
+	LOG_EXPERT_ATTACHMENT_ADD    string = "expert_attachment_added"
+	LOG_EXPERT_ATTACHMENT_DELETE string = "expert_attachment_deleted"
+	LOG_SLA_ESCALATED            string = "sla_escalated"
+	LOG_EXPERT_REVIEWED          string = "expert_reviewed"
+	LOG_EXPERT_REVIEW_HIDDEN     string = "expert_review_hidden"
+	LOG_APPOINTMENT_SCHEDULED    string = "appointment_scheduled"
+	LOG_APPOINTMENT_RESCHEDULED  string = "appointment_rescheduled"
+	LOG_APPOINTMENT_CANCELLED    string = "appointment_cancelled"
+)
+
+// Configurations
+const (
+	CONFIG_FEATURED_DURATION string = "featured_ads_duration"
+	CONFIG_REPORT_THRESHOLD  string = "report_hide_threshold"
+	CONFIG_EXPERT_ASSIGNMENT string = "expert_assignment_strategy"
+
+	CONFIG_SLA_EXPERT_PENDING     string = "sla_expert_pending_hours"
+	CONFIG_SLA_EXPERT_IN_PROGRESS string = "sla_expert_in_progress_hours"
+	CONFIG_SLA_REPAIR_PENDING     string = "sla_repair_pending_hours"
+	CONFIG_SLA_REPAIR_IN_PROGRESS string = "sla_repair_in_progress_hours"
+
+	CONFIG_APPOINTMENT_NOTICE string = "appointment_notice_hours"
+
+	DEFAULT_FEATURED_DURATION_DAYS = 7
+	DEFAULT_OFFER_EXPIRY_HOURS     = 72
+	DEFAULT_AUCTION_EXTENSION_SECS = 300
+	DEFAULT_REPORT_THRESHOLD       = 3
+	DEFAULT_EXPERT_ASSIGNMENT      = ASSIGN_ROUND_ROBIN
+
+	DEFAULT_SLA_EXPERT_PENDING_HOURS     = 24
+	DEFAULT_SLA_EXPERT_IN_PROGRESS_HOURS = 72
+	DEFAULT_SLA_REPAIR_PENDING_HOURS     = 48
+	DEFAULT_SLA_REPAIR_IN_PROGRESS_HOURS = 168
+
+	DEFAULT_APPOINTMENT_NOTICE_HOURS = 24
+)
+
+// SLA request kinds
+const (
+	SLA_EXPERT string = "expert"
+	SLA_REPAIR string = "repair"
+)
+
+// Expert assignment strategies, the values of CONFIG_EXPERT_ASSIGNMENT
+const (
+	ASSIGN_MANUAL       = 0
+	ASSIGN_ROUND_ROBIN  = 1
+	ASSIGN_LEAST_LOADED = 2
+	ASSIGN_TOP_RATED    = 3
 )
@@ -35,7 +35,42 @@ VALUES
 (10, 'payment_success'),
 (11, 'payment_failed'),
 (12, 'bookmark'),
-(13, 'remove_bookmark');
+(13, 'remove_bookmark'),
+(14, 'featured_request'),
+(15, 'featured_activated'),
+(16, 'offer_created'),
+(17, 'offer_countered'),
+(18, 'offer_accepted'),
+(19, 'offer_rejected'),
+(20, 'ad_sold'),
+(21, 'auction_created'),
+(22, 'auction_bid'),
+(23, 'auction_closed'),
+(24, 'ad_reported'),
+(25, 'ad_auto_hidden'),
+(26, 'report_dismissed'),
+(27, 'report_upheld'),
+(28, 'bookmark_share_created'),
+(29, 'bookmark_share_revoked'),
+(30, 'bookmark_share_viewed'),
+(31, 'expert_assigned'),
+(32, 'expert_attachment_added'),
+(33, 'expert_attachment_deleted'),
+(34, 'sla_escalated'),
+(35, 'expert_reviewed'),
+(36, 'expert_review_hidden'),
+(37, 'appointment_scheduled'),
+(38, 'appointment_rescheduled'),
+(39, 'appointment_cancelled');
 
 INSERT INTO public.configuration (id, name, value) VALUES (1, 'repair_request', 100000);
-INSERT INTO public.configuration (id, name, value) VALUES (2, 'expert_ads', 50000);
\ No newline at end of file
+INSERT INTO public.configuration (id, name, value) VALUES (2, 'expert_ads', 50000);
+INSERT INTO public.configuration (id, name, value) VALUES (3, 'featured_ads', 30000);
+INSERT INTO public.configuration (id, name, value) VALUES (4, 'featured_ads_duration', 7);
+INSERT INTO public.configuration (id, name, value) VALUES (5, 'report_hide_threshold', 3);
+INSERT INTO public.configuration (id, name, value) VALUES (6, 'expert_assignment_strategy', 1);
+INSERT INTO public.configuration (id, name, value) VALUES (7, 'sla_expert_pending_hours', 24);
+INSERT INTO public.configuration (id, name, value) VALUES (8, 'sla_expert_in_progress_hours', 72);
+INSERT INTO public.configuration (id, name, value) VALUES (9, 'sla_repair_pending_hours', 48);
+INSERT INTO public.configuration (id, name, value) VALUES (10, 'sla_repair_in_progress_hours', 168);
+INSERT INTO public.configuration (id, name, value) VALUES (11, 'appointment_notice_hours', 24);
\ No newline at end of file
@@ -0,0 +1 @@
+DROP TABLE featured_ads;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS featured_ads (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    ads_id INT NOT NULL,
+    status STATUS_TYPE,
+    starts_at TIMESTAMP,
+    ends_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (ads_id) REFERENCES ads(id)
+);
+
+CREATE INDEX IF NOT EXISTS featured_ads_ads_id_ends_at_idx ON featured_ads (ads_id, ends_at);
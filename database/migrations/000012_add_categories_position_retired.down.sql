@@ -0,0 +1,3 @@
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS position,
+    DROP COLUMN IF EXISTS retired;
@@ -0,0 +1,3 @@
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS position INT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS retired BOOLEAN NOT NULL DEFAULT false;
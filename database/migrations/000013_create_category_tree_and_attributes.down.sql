@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS ad_attributes;
+DROP TABLE IF EXISTS category_attributes;
+
+ALTER TABLE categories
+    DROP COLUMN IF EXISTS parent_id;
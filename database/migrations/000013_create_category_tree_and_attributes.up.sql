@@ -0,0 +1,22 @@
+ALTER TABLE categories
+    ADD COLUMN IF NOT EXISTS parent_id INT REFERENCES categories(id);
+
+CREATE TABLE IF NOT EXISTS category_attributes (
+    id SERIAL PRIMARY KEY,
+    category_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,
+    unit VARCHAR(20),
+    required BOOLEAN NOT NULL DEFAULT false,
+    UNIQUE (category_id, name),
+    FOREIGN KEY (category_id) REFERENCES categories(id)
+);
+
+CREATE TABLE IF NOT EXISTS ad_attributes (
+    ads_id INT NOT NULL,
+    attribute_id INT NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    PRIMARY KEY (ads_id, attribute_id),
+    FOREIGN KEY (ads_id) REFERENCES ads(id),
+    FOREIGN KEY (attribute_id) REFERENCES category_attributes(id)
+);
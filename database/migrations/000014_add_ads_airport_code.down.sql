@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_ads_airport_code;
+
+ALTER TABLE ads DROP COLUMN IF EXISTS airport_code;
@@ -0,0 +1,3 @@
+ALTER TABLE ads ADD COLUMN airport_code VARCHAR(4);
+
+CREATE INDEX idx_ads_airport_code ON ads (airport_code);
@@ -0,0 +1,21 @@
+CREATE TABLE IF NOT EXISTS offers (
+    id SERIAL PRIMARY KEY,
+    ads_id INT NOT NULL,
+    buyer_id INT NOT NULL,
+    seller_id INT NOT NULL,
+    proposed_by INT NOT NULL,
+    parent_id INT,
+    price BIGINT NOT NULL,
+    message TEXT,
+    status VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    FOREIGN KEY (ads_id) REFERENCES ads(id),
+    FOREIGN KEY (buyer_id) REFERENCES users(id),
+    FOREIGN KEY (seller_id) REFERENCES users(id),
+    FOREIGN KEY (proposed_by) REFERENCES users(id),
+    FOREIGN KEY (parent_id) REFERENCES offers(id)
+);
+
+CREATE INDEX IF NOT EXISTS offers_ads_id_buyer_id_idx ON offers (ads_id, buyer_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS notifications;
+
+DROP TABLE IF EXISTS bids;
+
+DROP TABLE IF EXISTS auctions;
+
+ALTER TABLE ads DROP COLUMN IF EXISTS listing_mode;
@@ -0,0 +1,51 @@
+ALTER TABLE ads ADD COLUMN listing_mode VARCHAR(20) NOT NULL DEFAULT 'fixed';
+
+CREATE TABLE IF NOT EXISTS auctions (
+    id SERIAL PRIMARY KEY,
+    ads_id INT NOT NULL UNIQUE,
+    seller_id INT NOT NULL,
+    reserve_price BIGINT NOT NULL,
+    min_increment BIGINT NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    extension_seconds INT NOT NULL,
+    highest_bid BIGINT NOT NULL DEFAULT 0,
+    highest_bidder_id INT,
+    bid_count INT NOT NULL DEFAULT 0,
+    version INT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL,
+    winner_id INT,
+    closed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    FOREIGN KEY (ads_id) REFERENCES ads(id),
+    FOREIGN KEY (seller_id) REFERENCES users(id),
+    FOREIGN KEY (highest_bidder_id) REFERENCES users(id),
+    FOREIGN KEY (winner_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS auctions_status_ends_at_idx ON auctions (status, ends_at);
+
+CREATE TABLE IF NOT EXISTS bids (
+    id SERIAL PRIMARY KEY,
+    auction_id INT NOT NULL,
+    user_id INT NOT NULL,
+    amount BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    FOREIGN KEY (auction_id) REFERENCES auctions(id),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS bids_auction_id_idx ON bids (auction_id);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    ads_id INT,
+    kind VARCHAR(50) NOT NULL,
+    message TEXT,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS notifications_user_id_idx ON notifications (user_id);
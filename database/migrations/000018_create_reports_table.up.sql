@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS reports (
+    id SERIAL PRIMARY KEY,
+    ads_id INT NOT NULL,
+    user_id INT NOT NULL,
+    reason VARCHAR(30) NOT NULL,
+    note TEXT,
+    status VARCHAR(20) NOT NULL,
+    resolved_by INT,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    FOREIGN KEY (ads_id) REFERENCES ads(id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (resolved_by) REFERENCES users(id),
+    CONSTRAINT idx_reports_ads_user UNIQUE (ads_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS reports_status_idx ON reports (status);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS ad_drafts (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    payload TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS ad_drafts_user_id_idx ON ad_drafts (user_id);
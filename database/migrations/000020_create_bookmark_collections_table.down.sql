@@ -0,0 +1,14 @@
+DROP TABLE IF EXISTS bookmark_collection_items;
+DROP TABLE IF EXISTS bookmark_collections;
+
+ALTER TABLE bookmarks DROP COLUMN IF EXISTS tags;
+ALTER TABLE bookmarks DROP COLUMN IF EXISTS note;
+
+CREATE TABLE IF NOT EXISTS user_bookmark (
+  user_id SERIAL,
+  bookmark_user_id SERIAL,
+  bookmark_ads_id SERIAL,
+  PRIMARY KEY (user_id, bookmark_user_id, bookmark_ads_id),
+  FOREIGN KEY (user_id) REFERENCES users (id),
+  FOREIGN KEY (bookmark_user_id, bookmark_ads_id) REFERENCES bookmarks (user_id, ads_id)
+);
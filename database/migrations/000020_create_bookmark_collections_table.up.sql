@@ -0,0 +1,24 @@
+-- the many-to-many table of 000005 was never used, collections replace it
+DROP TABLE IF EXISTS user_bookmark;
+
+ALTER TABLE bookmarks ADD COLUMN IF NOT EXISTS note TEXT NOT NULL DEFAULT '';
+ALTER TABLE bookmarks ADD COLUMN IF NOT EXISTS tags TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS bookmark_collections (
+    id SERIAL PRIMARY KEY,
+    user_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    CONSTRAINT idx_bookmark_collections_user_name UNIQUE (user_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS bookmark_collection_items (
+    collection_id INT NOT NULL,
+    user_id INT NOT NULL,
+    ads_id INT NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (collection_id, ads_id),
+    FOREIGN KEY (collection_id) REFERENCES bookmark_collections(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id, ads_id) REFERENCES bookmarks(user_id, ads_id) ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS bookmarks_ads_id_idx;
+
+ALTER TABLE bookmarks DROP COLUMN IF EXISTS muted;
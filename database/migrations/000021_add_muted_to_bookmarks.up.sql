@@ -0,0 +1,3 @@
+ALTER TABLE bookmarks ADD COLUMN IF NOT EXISTS muted BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS bookmarks_ads_id_idx ON bookmarks (ads_id);
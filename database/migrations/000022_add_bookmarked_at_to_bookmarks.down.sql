@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS bookmarks_user_id_bookmarked_at_idx;
+
+ALTER TABLE bookmarks DROP COLUMN IF EXISTS bookmarked_at;
@@ -0,0 +1,3 @@
+ALTER TABLE bookmarks ADD COLUMN IF NOT EXISTS bookmarked_at TIMESTAMP NOT NULL DEFAULT NOW();
+
+CREATE INDEX IF NOT EXISTS bookmarks_user_id_bookmarked_at_idx ON bookmarks (user_id, bookmarked_at);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS bookmark_shares (
+    id SERIAL PRIMARY KEY,
+    collection_id INT NOT NULL,
+    user_id INT NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMP NOT NULL,
+    revoked_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    FOREIGN KEY (collection_id) REFERENCES bookmark_collections(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS bookmark_shares_collection_id_idx ON bookmark_shares (collection_id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS expert_ads_expert_id_idx;
+
+DROP TABLE IF EXISTS expert_specialties;
+DROP TABLE IF EXISTS expert_profiles;
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS expert_profiles (
+    user_id INT PRIMARY KEY,
+    available BOOLEAN NOT NULL DEFAULT TRUE,
+    last_assigned_at TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+CREATE TABLE IF NOT EXISTS expert_specialties (
+    expert_id INT NOT NULL,
+    category_id INT NOT NULL,
+    PRIMARY KEY (expert_id, category_id),
+    FOREIGN KEY (expert_id) REFERENCES users(id),
+    FOREIGN KEY (category_id) REFERENCES categories(id)
+);
+
+CREATE INDEX IF NOT EXISTS expert_ads_expert_id_idx ON expert_ads (expert_id);
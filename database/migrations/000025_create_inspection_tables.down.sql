@@ -0,0 +1,6 @@
+ALTER TABLE expert_ads DROP COLUMN IF EXISTS score;
+ALTER TABLE expert_ads DROP COLUMN IF EXISTS grade;
+
+DROP TABLE IF EXISTS inspection_items;
+DROP TABLE IF EXISTS inspection_template_items;
+DROP TABLE IF EXISTS inspection_templates;
@@ -0,0 +1,34 @@
+CREATE TABLE IF NOT EXISTS inspection_templates (
+    id SERIAL PRIMARY KEY,
+    category_id INT NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    FOREIGN KEY (category_id) REFERENCES categories(id)
+);
+
+CREATE TABLE IF NOT EXISTS inspection_template_items (
+    id SERIAL PRIMARY KEY,
+    template_id INT NOT NULL,
+    section VARCHAR(20) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (template_id) REFERENCES inspection_templates(id) ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS inspection_template_items_template_id_idx ON inspection_template_items (template_id);
+
+CREATE TABLE IF NOT EXISTS inspection_items (
+    expert_ads_id INT NOT NULL,
+    template_item_id INT NOT NULL,
+    section VARCHAR(20) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    position INT NOT NULL DEFAULT 0,
+    rating INT NOT NULL,
+    finding TEXT,
+    severity VARCHAR(20) NOT NULL,
+    PRIMARY KEY (expert_ads_id, template_item_id),
+    FOREIGN KEY (expert_ads_id) REFERENCES expert_ads(id) ON DELETE CASCADE
+);
+
+ALTER TABLE expert_ads ADD COLUMN IF NOT EXISTS grade VARCHAR(1);
+ALTER TABLE expert_ads ADD COLUMN IF NOT EXISTS score DOUBLE PRECISION NOT NULL DEFAULT 0;
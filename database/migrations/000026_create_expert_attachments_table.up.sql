@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS expert_attachments (
+    id SERIAL PRIMARY KEY,
+    expert_ads_id INT NOT NULL,
+    template_item_id BIGINT,
+    uploader_id INT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size BIGINT NOT NULL,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    FOREIGN KEY (expert_ads_id) REFERENCES expert_ads(id) ON DELETE CASCADE,
+    FOREIGN KEY (uploader_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS expert_attachments_expert_ads_id_idx ON expert_attachments (expert_ads_id);
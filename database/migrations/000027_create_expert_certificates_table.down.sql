@@ -0,0 +1 @@
+DROP TABLE IF EXISTS expert_certificates;
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS expert_certificates (
+    id SERIAL PRIMARY KEY,
+    expert_ads_id INT NOT NULL,
+    revision INT NOT NULL,
+    payload TEXT NOT NULL,
+    signature VARCHAR(100) NOT NULL,
+    key_id VARCHAR(16) NOT NULL,
+    issued_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (expert_ads_id) REFERENCES expert_ads(id) ON DELETE CASCADE,
+    CONSTRAINT idx_expert_certificate_revision UNIQUE (expert_ads_id, revision)
+);
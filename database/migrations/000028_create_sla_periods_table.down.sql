@@ -0,0 +1,3 @@
+ALTER TABLE repair_request DROP COLUMN IF EXISTS matin_id;
+
+DROP TABLE IF EXISTS sla_periods;
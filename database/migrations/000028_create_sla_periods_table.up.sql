@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS sla_periods (
+    id SERIAL PRIMARY KEY,
+    kind VARCHAR(10) NOT NULL,
+    request_id INT NOT NULL,
+    ads_id INT NOT NULL,
+    status VARCHAR(50) NOT NULL,
+    assignee_id BIGINT,
+    started_at TIMESTAMP NOT NULL,
+    due_at TIMESTAMP,
+    ended_at TIMESTAMP,
+    escalated_at TIMESTAMP,
+    FOREIGN KEY (assignee_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sla_period_request ON sla_periods (kind, request_id);
+CREATE INDEX IF NOT EXISTS sla_periods_open_idx ON sla_periods (due_at) WHERE ended_at IS NULL;
+
+ALTER TABLE repair_request ADD COLUMN IF NOT EXISTS matin_id BIGINT REFERENCES users(id);
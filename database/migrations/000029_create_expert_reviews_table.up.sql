@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS expert_reviews (
+    id SERIAL PRIMARY KEY,
+    expert_ads_id INT NOT NULL UNIQUE,
+    expert_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    rating INT NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    comment TEXT,
+    hidden BOOLEAN NOT NULL DEFAULT FALSE,
+    hidden_by BIGINT,
+    hidden_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (expert_ads_id) REFERENCES expert_ads(id) ON DELETE CASCADE,
+    FOREIGN KEY (expert_id) REFERENCES users(id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (hidden_by) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_expert_reviews_expert_id ON expert_reviews (expert_id) WHERE NOT hidden;
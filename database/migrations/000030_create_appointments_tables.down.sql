@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS calendar_feeds;
+DROP TABLE IF EXISTS appointments;
+DROP TABLE IF EXISTS expert_slots;
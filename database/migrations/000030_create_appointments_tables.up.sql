@@ -0,0 +1,49 @@
+CREATE TABLE IF NOT EXISTS expert_slots (
+    id SERIAL PRIMARY KEY,
+    expert_id BIGINT NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    location VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (expert_id) REFERENCES users(id),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX IF NOT EXISTS idx_expert_slots_expert_id ON expert_slots (expert_id, starts_at);
+
+CREATE TABLE IF NOT EXISTS appointments (
+    id SERIAL PRIMARY KEY,
+    expert_ads_id INT NOT NULL,
+    slot_id INT,
+    ads_id INT NOT NULL,
+    expert_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    location VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    sequence INT NOT NULL DEFAULT 0,
+    cancelled_by BIGINT,
+    cancelled_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (expert_ads_id) REFERENCES expert_ads(id) ON DELETE CASCADE,
+    FOREIGN KEY (slot_id) REFERENCES expert_slots(id) ON DELETE SET NULL,
+    FOREIGN KEY (ads_id) REFERENCES ads(id),
+    FOREIGN KEY (expert_id) REFERENCES users(id),
+    FOREIGN KEY (user_id) REFERENCES users(id),
+    FOREIGN KEY (cancelled_by) REFERENCES users(id)
+);
+
+-- a request and a slot have at most one scheduled appointment
+CREATE UNIQUE INDEX IF NOT EXISTS idx_appointment_request ON appointments (expert_ads_id) WHERE status = 'Scheduled';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_appointment_slot ON appointments (slot_id) WHERE status = 'Scheduled';
+CREATE INDEX IF NOT EXISTS idx_appointments_expert_id ON appointments (expert_id);
+CREATE INDEX IF NOT EXISTS idx_appointments_user_id ON appointments (user_id);
+
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id BIGINT PRIMARY KEY,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
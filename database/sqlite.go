@@ -13,8 +13,24 @@ func CreateTestDatabase() (*gorm.DB, error) {
 		return nil, err
 	}
 
+	// every connection to :memory: opens its own empty database, so
+	// concurrent tests must share a single one
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	sqlDB.SetMaxOpenConns(1)
+
 	err = db.AutoMigrate(&models.User{}, &models.Category{}, &models.Ad{}, &models.ExpertAds{},
-		&models.Bookmarks{}, &models.Transaction{})
+		&models.Bookmarks{}, &models.Transaction{}, &models.FeaturedAd{}, &models.Configuration{},
+		&models.CategoryAttribute{}, &models.AdAttribute{}, &models.Offer{},
+		&models.Auction{}, &models.Bid{}, &models.Notification{},
+		&models.Conversation{}, &models.Message{}, &models.Report{}, &models.AdDraft{},
+		&models.BookmarkCollection{}, &models.BookmarkCollectionItem{}, &models.BookmarkShare{},
+		&models.ExpertProfile{}, &models.ExpertSpecialty{}, &models.RepairRequest{},
+		&models.InspectionTemplate{}, &models.InspectionTemplateItem{}, &models.InspectionItem{},
+		&models.ExpertAttachment{}, &models.ExpertCertificate{}, &models.SLAPeriod{}, &models.ExpertReview{},
+		&models.ExpertSlot{}, &models.Appointment{}, &models.CalendarFeed{})
 	if err != nil {
 		return nil, err
 	}
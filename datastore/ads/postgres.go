@@ -1,13 +1,21 @@
 package ads
 
 import (
+	"Airplane-Divar/airports"
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore/category"
 	"Airplane-Divar/filter"
 	"Airplane-Divar/models"
+	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type AdDatastorer struct {
@@ -21,7 +29,7 @@ func New(db *gorm.DB) AdDatastorer {
 func (a AdDatastorer) Get(id int, userRole string) ([]models.Ad, error) {
 	var ads []models.Ad
 	var result *gorm.DB
-	result = a.db.Select([]string{"id", "user_id", "image", "description", "subject", "price", "category_id", "status", "fly_time", "airplane_model", "repair_check", "expert_check", "plane_age"})
+	result = a.db.Select([]string{"id", "user_id", "image", "description", "subject", "price", "category_id", "status", "fly_time", "airplane_model", "repair_check", "expert_check", "plane_age", "airport_code", "listing_mode"})
 	if id != 0 {
 		result.Where("id = ?", id)
 	}
@@ -38,15 +46,102 @@ func (a AdDatastorer) Get(id int, userRole string) ([]models.Ad, error) {
 }
 
 func (a AdDatastorer) ListFilterByColumn(f *filter.AdsFilter) (ads []models.Ad, err error) {
-	builder := a.db.Select([]string{"id", "user_id", "image", "description", "subject", "price", "category_id", "status", "fly_time", "airplane_model", "repair_check", "expert_check", "plane_age"}).
+	builder := a.db.Select([]string{"id", "user_id", "image", "description", "subject", "price", "category_id", "status", "fly_time", "airplane_model", "repair_check", "expert_check", "plane_age", "airport_code", "listing_mode"}).
 		Offset(f.Base.Offset).
-		Limit(f.Base.Limit).
-		Order("id")
+		Limit(f.Base.Limit)
+	builder = orderPinned(builder, "id")
 
 	builder, err = checkUserRole(f.Base.UserRole, builder)
 	if err != nil {
 		return nil, err
 	}
+	builder, err = filterByColumn(f, builder)
+	if err != nil {
+		return nil, err
+	}
+
+	if builder.Find(&ads).Error != nil {
+		return nil, err
+	}
+
+	return ads, nil
+}
+
+// Export streams every ad matching the filter, ignoring paging, to fn one row at a time.
+func (a AdDatastorer) Export(ctx context.Context, f *filter.AdsFilter, fn func(models.AdExportRow) error) error {
+	builder := a.db.WithContext(ctx).Table("ads").
+		Select([]string{"ads.id", "ads.user_id", "ads.image", "ads.description", "ads.subject", "ads.price",
+			"categories.name AS category_name", "ads.status", "ads.fly_time", "ads.airplane_model",
+			"ads.repair_check", "ads.expert_check", "ads.plane_age"}).
+		Joins("LEFT JOIN categories ON categories.id = ads.category_id")
+
+	builder, err := checkUserRole(f.Base.UserRole, builder)
+	if err != nil {
+		return err
+	}
+	builder, err = filterByColumn(f, builder)
+	if err != nil {
+		return err
+	}
+
+	orderClause, err := sortClause(f.Base.Sort, "ads.")
+	if err != nil {
+		return err
+	}
+	if len(orderClause) != 0 {
+		builder = builder.Order(strings.Join(orderClause, ", "))
+	} else {
+		builder = builder.Order("ads.id")
+	}
+
+	rows, err := builder.Rows()
+	if err != nil {
+		return fmt.Errorf("database error: export ads from database")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.AdExportRow
+		if err := a.db.ScanRows(rows, &row); err != nil {
+			return fmt.Errorf("database error: scan exported ad")
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// sortableColumns are the ad columns a listing can be sorted by.
+var sortableColumns = map[string]bool{
+	"id": true, "price": true, "fly_time": true, "plane_age": true,
+	"subject": true, "airplane_model": true, "status": true, "category_id": true,
+}
+
+// sortClause turns the sort query into ORDER BY terms in a stable key order,
+// rejecting anything that is not a sortable column or a direction.
+func sortClause(sortKeys map[string]string, prefix string) ([]string, error) {
+	cols := make([]string, 0, len(sortKeys))
+	for col := range sortKeys {
+		cols = append(cols, col)
+	}
+	sort.Strings(cols)
+
+	clause := make([]string, 0, len(cols))
+	for _, col := range cols {
+		order := strings.ToUpper(sortKeys[col])
+		if !sortableColumns[col] || (order != filter.SqlAsc && order != filter.SqlDesc) {
+			return nil, apperror.InvalidParameter("sort")
+		}
+		clause = append(clause, prefix+col+" "+order)
+	}
+	return clause, nil
+}
+
+// filterByColumn narrows the query to the filter. Range filters only match
+// number attributes, a bound that is not a number is rejected.
+func filterByColumn(f *filter.AdsFilter, builder *gorm.DB) (*gorm.DB, error) {
 	// if user is admin, can filter by status to check ads is active or not
 	if f.Status != "" && f.Base.UserRole == "Admin" {
 		builder = builder.Where("status = ?", f.Status)
@@ -62,27 +157,52 @@ func (a AdDatastorer) ListFilterByColumn(f *filter.AdsFilter) (ads []models.Ad,
 		builder = builder.Where("fly_time = ?", f.FlyTime)
 	}
 	if f.CategoryID != 0 {
-		builder = builder.Where("category_id = ?", f.CategoryID)
+		// a parent category includes all of its descendants
+		builder = builder.Where("category_id IN ("+category.DescendantsSQL+")", f.CategoryID)
 	}
 	if f.AirplaneModel != "" {
 		builder = builder.Where("airplane_model = ?", f.AirplaneModel)
 	}
+	if f.Airport != "" {
+		builder = builder.Where("airport_code = ?", airports.Normalize(f.Airport))
+	}
+	if f.Country != "" {
+		builder = builder.Where("airport_code IN ?", airports.InCountry(f.Country))
+	}
+	if f.Near != "" {
+		builder = builder.Where("airport_code IN ?", airports.Within(f.Near, f.RadiusKM))
+	}
+	for _, attr := range f.Attributes {
+		if attr.Op == "=" {
+			builder = builder.Where("EXISTS (SELECT 1 FROM ad_attributes "+
+				"JOIN category_attributes ON category_attributes.id = ad_attributes.attribute_id "+
+				"WHERE ad_attributes.ads_id = ads.id AND category_attributes.name = ? AND ad_attributes.value = ?)",
+				attr.Name, attr.Value)
+			continue
+		}
 
-	if builder.Find(&ads).Error != nil {
-		return nil, err
+		number, err := strconv.ParseFloat(attr.Value, 64)
+		if err != nil {
+			return nil, apperror.InvalidParameter(attr.Param())
+		}
+		// attributes of the same name in other branches may hold text
+		builder = builder.Where("EXISTS (SELECT 1 FROM ad_attributes "+
+			"JOIN category_attributes ON category_attributes.id = ad_attributes.attribute_id "+
+			"WHERE ad_attributes.ads_id = ads.id AND category_attributes.name = ? AND category_attributes.type = ? "+
+			"AND CAST(ad_attributes.value AS NUMERIC) "+attr.Op+" ?)",
+			attr.Name, consts.ATTRIBUTE_NUMBER, number)
 	}
 
-	return ads, nil
+	return builder, nil
 }
 
 func (a AdDatastorer) ListFilterSort(f *filter.Filter) (ads []models.Ad, err error) {
-	var orderClause []string
-	for col, order := range f.Sort {
-		orderClause = append(orderClause, fmt.Sprintf("%s %s", col, order))
+	orderClause, err := sortClause(f.Sort, "")
+	if err != nil {
+		return nil, err
 	}
 
-	builder := a.db.Limit(f.Limit).
-		Order(strings.Join(orderClause, ","))
+	builder := orderPinned(a.db.Limit(f.Limit), strings.Join(orderClause, ","))
 
 	builder, err = checkUserRole(f.UserRole, builder)
 	if err != nil {
@@ -96,6 +216,25 @@ func (a AdDatastorer) ListFilterSort(f *filter.Filter) (ads []models.Ad, err err
 	return ads, nil
 }
 
+// orderPinned orders ads with a running paid featured window before the
+// others, then by order. Both go in one expression because gorm drops an
+// ORDER BY expression once plain columns are merged into it, so callers
+// must not chain another Order.
+func orderPinned(builder *gorm.DB, order string) *gorm.DB {
+	now := time.Now()
+	sql := "CASE WHEN EXISTS (SELECT 1 FROM featured_ads WHERE featured_ads.ads_id = ads.id " +
+		"AND featured_ads.status = ? AND featured_ads.starts_at <= ? AND featured_ads.ends_at > ?) THEN 0 ELSE 1 END"
+	if order != "" {
+		sql += ", " + order
+	}
+
+	return builder.Clauses(clause.OrderBy{Expression: clause.Expr{
+		SQL:                sql,
+		Vars:               []interface{}{consts.DONE_STATUS, now, now},
+		WithoutParentheses: true,
+	}})
+}
+
 func checkUserRole(role string, builder *gorm.DB) (*gorm.DB, error) {
 	switch role {
 	case "Airline": // Airline
@@ -113,7 +252,7 @@ func checkUserRole(role string, builder *gorm.DB) (*gorm.DB, error) {
 
 func (a AdDatastorer) GetCategoryByName(name string) (models.Category, error) {
 	var categoryObj models.Category
-	a.db.Where("name = ?", name).First(&categoryObj)
+	a.db.Where("name = ? AND retired = ?", name, false).First(&categoryObj)
 	if categoryObj.ID == 0 {
 		msg := "Undefined Category Name !"
 		return models.Category{}, fmt.Errorf(msg)
@@ -121,6 +260,18 @@ func (a AdDatastorer) GetCategoryByName(name string) (models.Category, error) {
 	return categoryObj, nil
 }
 
+// GetCategoryAttributes returns the attributes declared on a category and its ancestors.
+func (a AdDatastorer) GetCategoryAttributes(categoryID uint) ([]models.CategoryAttribute, error) {
+	var attributes []models.CategoryAttribute
+	result := a.db.Where("category_id IN ("+category.AncestorsSQL+")", categoryID).
+		Order("id").
+		Find(&attributes)
+	if result.Error != nil {
+		return nil, fmt.Errorf("database error: get category attributes from database")
+	}
+	return attributes, nil
+}
+
 func (a AdDatastorer) CreateAd(ad *models.Ad) (models.Ad, error) {
 	var tmp_ad *models.Ad
 	tmp_ad = ad
@@ -1,13 +1,16 @@
 package ads
 
 import (
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/consts"
 	database "Airplane-Divar/database"
 	"Airplane-Divar/filter"
 	"Airplane-Divar/models"
+	"context"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -33,8 +36,12 @@ func TestDatastore(t *testing.T) {
 	testAdStorer_ListFilterByColumn(t, a)
 	testAdStorer_ListFilterSort(t, a)
 	testAdStorer_GetCategoryByName(t, a)
+	testAdStorer_Export(t, a)
 	testAdStorer_CreateAd(t, a)
 	testAdStorer_UpdateStatus(t, a)
+	testAdStorer_PinFeatured(t, a)
+	testAdStorer_FilterAttributes(t, a)
+	testAdStorer_FilterLocation(t, a)
 }
 
 func testAdStorer_Get(t *testing.T, db AdDatastorer) {
@@ -44,11 +51,11 @@ func testAdStorer_Get(t *testing.T, db AdDatastorer) {
 		resp     []models.Ad
 	}{
 		{0, "Airline", []models.Ad{
-			{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, models.Category{}},
-			{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3, models.Category{}},
-			// {3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7,  models.Category{}},
+			{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, "", consts.LISTING_FIXED, models.Category{}, nil, nil},
+			{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3, "", consts.LISTING_FIXED, models.Category{}, nil, nil},
+			// {3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7,  models.Category{}, nil},
 		}},
-		{1, "Airline", []models.Ad{{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, models.Category{}}}},
+		{1, "Airline", []models.Ad{{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, "", consts.LISTING_FIXED, models.Category{}, nil, nil}}},
 	}
 
 	for i, v := range testcases {
@@ -77,7 +84,7 @@ func testAdStorer_ListFilterByColumn(t *testing.T, db AdDatastorer) {
 				},
 				PlaneAge: 7,
 			},
-			[]models.Ad{{3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7, models.Category{}}},
+			[]models.Ad{{3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7, "", consts.LISTING_FIXED, models.Category{}, nil, nil}},
 		},
 		{
 			filter.AdsFilter{
@@ -89,9 +96,9 @@ func testAdStorer_ListFilterByColumn(t *testing.T, db AdDatastorer) {
 				CategoryID: 1,
 			},
 			[]models.Ad{
-				{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, models.Category{}},
-				//{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3,  models.Category{}},
-				// {3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7,  models.Category{}},
+				{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, "", consts.LISTING_FIXED, models.Category{}, nil, nil},
+				//{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3,  models.Category{}, nil},
+				// {3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7,  models.Category{}, nil},
 			},
 		},
 		{
@@ -105,9 +112,9 @@ func testAdStorer_ListFilterByColumn(t *testing.T, db AdDatastorer) {
 				Price:      1000,
 			},
 			[]models.Ad{
-				{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, models.Category{}},
-				//{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3,  models.Category{}},
-				// {3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7,  models.Category{}},
+				{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, "", consts.LISTING_FIXED, models.Category{}, nil, nil},
+				//{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3,  models.Category{}, nil},
+				// {3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7,  models.Category{}, nil},
 			},
 		},
 		{
@@ -121,9 +128,9 @@ func testAdStorer_ListFilterByColumn(t *testing.T, db AdDatastorer) {
 				FlyTime:    1000,
 			},
 			[]models.Ad{
-				{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, models.Category{}},
-				//{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3,  models.Category{}},
-				// {3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7,  models.Category{}},
+				{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, "", consts.LISTING_FIXED, models.Category{}, nil, nil},
+				//{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3,  models.Category{}, nil},
+				// {3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7,  models.Category{}, nil},
 			},
 		},
 		{
@@ -135,9 +142,9 @@ func testAdStorer_ListFilterByColumn(t *testing.T, db AdDatastorer) {
 				},
 			},
 			[]models.Ad{
-				// {1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5,  models.Category{}},
-				{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3, models.Category{}},
-				// {3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7,  models.Category{}},
+				// {1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5,  models.Category{}, nil},
+				{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3, "", consts.LISTING_FIXED, models.Category{}, nil, nil},
+				// {3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7,  models.Category{}, nil},
 			},
 		},
 	}
@@ -168,9 +175,9 @@ func testAdStorer_ListFilterSort(t *testing.T, db AdDatastorer) {
 				},
 			},
 			[]models.Ad{
-				{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, models.Category{}},
-				{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3, models.Category{}},
-				// {3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7,  models.Category{}},
+				{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, "", consts.LISTING_FIXED, models.Category{}, nil, nil},
+				{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3, "", consts.LISTING_FIXED, models.Category{}, nil, nil},
+				// {3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7,  models.Category{}, nil},
 			},
 		},
 		{
@@ -183,9 +190,9 @@ func testAdStorer_ListFilterSort(t *testing.T, db AdDatastorer) {
 				},
 			},
 			[]models.Ad{
-				{3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7, models.Category{}},
-				{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3, models.Category{}},
-				{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, models.Category{}},
+				{3, 1, "example3.jpg", "This is example ad 3.", "Example Ad 3", 3000, 1, "Inactive", 1000, "DEF789", false, false, 7, "", consts.LISTING_FIXED, models.Category{}, nil, nil},
+				{2, 1, "example2.jpg", "This is example ad 2.", "Example Ad 2", 2000, 2, "Active", 1000, "ABC456", true, true, 3, "", consts.LISTING_FIXED, models.Category{}, nil, nil},
+				{1, 1, "example1.jpg", "This is example ad 1.", "Example Ad 1", 1000, 1, "Active", 1000, "XYZ123", true, false, 5, "", consts.LISTING_FIXED, models.Category{}, nil, nil},
 			},
 		},
 		{
@@ -249,6 +256,85 @@ func testAdStorer_GetCategoryByName(t *testing.T, db AdDatastorer) {
 	}
 }
 
+func testAdStorer_Export(t *testing.T, db AdDatastorer) {
+	row1 := models.AdExportRow{
+		ID: 1, UserID: 1, Image: "example1.jpg", Description: "This is example ad 1.", Subject: "Example Ad 1",
+		Price: 1000, CategoryName: "small-passenger", Status: "Active", FlyTime: 1000, AirplaneModel: "XYZ123",
+		RepairCheck: true, ExpertCheck: false, PlaneAge: 5,
+	}
+	row2 := models.AdExportRow{
+		ID: 2, UserID: 1, Image: "example2.jpg", Description: "This is example ad 2.", Subject: "Example Ad 2",
+		Price: 2000, CategoryName: "big-passenger", Status: "Active", FlyTime: 1000, AirplaneModel: "ABC456",
+		RepairCheck: true, ExpertCheck: true, PlaneAge: 3,
+	}
+	row3 := models.AdExportRow{
+		ID: 3, UserID: 1, Image: "example3.jpg", Description: "This is example ad 3.", Subject: "Example Ad 3",
+		Price: 3000, CategoryName: "small-passenger", Status: "Inactive", FlyTime: 1000, AirplaneModel: "DEF789",
+		RepairCheck: false, ExpertCheck: false, PlaneAge: 7,
+	}
+
+	testcases := []struct {
+		filter filter.AdsFilter
+		resp   []models.AdExportRow
+	}{
+		{
+			filter.AdsFilter{Base: filter.Filter{UserRole: "Airline"}},
+			[]models.AdExportRow{
+				row1,
+				row2,
+			},
+		},
+		{
+			filter.AdsFilter{Base: filter.Filter{UserRole: "Admin", Limit: 1}, CategoryID: 1},
+			[]models.AdExportRow{
+				row1,
+				row3,
+			},
+		},
+		{
+			filter.AdsFilter{Base: filter.Filter{UserRole: "Admin", Sort: map[string]string{"price": "DESC"}}, CategoryID: 1},
+			[]models.AdExportRow{
+				row3,
+				row1,
+			},
+		},
+		{
+			filter.AdsFilter{Base: filter.Filter{UserRole: "Admin", Sort: map[string]string{"fly_time": "ASC", "plane_age": "DESC"}}},
+			[]models.AdExportRow{
+				row3,
+				row1,
+				row2,
+			},
+		},
+		{
+			filter.AdsFilter{Base: filter.Filter{UserRole: "Admin", Sort: map[string]string{"price; DROP TABLE ads": "ASC"}}},
+			nil,
+		},
+		{
+			filter.AdsFilter{Base: filter.Filter{UserRole: "Admin", Sort: map[string]string{"price": "ASC, (SELECT 1)"}}},
+			nil,
+		},
+		{
+			filter.AdsFilter{Base: filter.Filter{UserRole: "Unknown"}},
+			nil,
+		},
+	}
+
+	for i, v := range testcases {
+		var resp []models.AdExportRow
+		_ = db.Export(context.Background(), &v.filter, func(row models.AdExportRow) error {
+			resp = append(resp, row)
+			return nil
+		})
+
+		if !reflect.DeepEqual(resp, v.resp) {
+			t.Errorf("[Export() TEST%d]Failed. Got %v\tExpected %v\n", i+1, resp, v.resp)
+		} else {
+			fmt.Println("[Export() TEST", i+1, "]Pass.")
+		}
+	}
+}
+
 func testAdStorer_CreateAd(t *testing.T, db AdDatastorer) {
 	testcases := []struct {
 		ad  models.Ad
@@ -282,6 +368,7 @@ func testAdStorer_CreateAd(t *testing.T, db AdDatastorer) {
 				RepairCheck:   true,
 				ExpertCheck:   false,
 				PlaneAge:      3,
+				ListingMode:   consts.LISTING_FIXED,
 			},
 		},
 	}
@@ -317,6 +404,7 @@ func testAdStorer_UpdateStatus(t *testing.T, db AdDatastorer) {
 			RepairCheck:   false,
 			ExpertCheck:   false,
 			PlaneAge:      7,
+			ListingMode:   consts.LISTING_FIXED,
 		}},
 		{1, consts.INACTIVE, models.Ad{
 			ID:            1,
@@ -332,6 +420,7 @@ func testAdStorer_UpdateStatus(t *testing.T, db AdDatastorer) {
 			RepairCheck:   true,
 			ExpertCheck:   false,
 			PlaneAge:      5,
+			ListingMode:   consts.LISTING_FIXED,
 		}},
 	}
 
@@ -346,6 +435,133 @@ func testAdStorer_UpdateStatus(t *testing.T, db AdDatastorer) {
 	}
 }
 
+func testAdStorer_PinFeatured(t *testing.T, db AdDatastorer) {
+	now := time.Now()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	expired := now.Add(-time.Minute)
+	featured := []models.FeaturedAd{
+		{AdsID: 3, UserID: 1, Status: consts.DONE_STATUS, StartsAt: &past, EndsAt: &future},
+		{AdsID: 2, UserID: 1, Status: consts.DONE_STATUS, StartsAt: &past, EndsAt: &expired},
+		{AdsID: 2, UserID: 1, Status: consts.WAIT_FOR_PAYMENT_STATUS},
+	}
+	if err := db.db.Create(&featured).Error; err != nil {
+		t.Fatal(err)
+	}
+	defer db.db.Exec("DELETE FROM featured_ads")
+
+	byColumn, _ := db.ListFilterByColumn(&filter.AdsFilter{Base: filter.Filter{Limit: 10, UserRole: "Airline"}})
+	sorted, _ := db.ListFilterSort(&filter.Filter{Limit: 10, UserRole: "Airline", Sort: map[string]string{"price": "ASC"}})
+
+	for i, resp := range [][]models.Ad{byColumn, sorted} {
+		var ids []uint
+		for _, ad := range resp {
+			ids = append(ids, ad.ID)
+		}
+		if !reflect.DeepEqual(ids, []uint{3, 2}) {
+			t.Errorf("[PinFeatured() TEST%d]Failed. Got %v\tExpected %v\n", i+1, ids, []uint{3, 2})
+		} else {
+			fmt.Println("[PinFeatured() TEST", i+1, "]Pass.")
+		}
+	}
+}
+
+func testAdStorer_FilterAttributes(t *testing.T, db AdDatastorer) {
+	parent := uint(1)
+	if err := db.db.Model(&models.Category{}).Where("id = ?", 2).Update("parent_id", &parent).Error; err != nil {
+		t.Fatal(err)
+	}
+	attributes := []models.CategoryAttribute{
+		{ID: 1, CategoryID: 1, Name: "seats", Type: consts.ATTRIBUTE_NUMBER},
+		{ID: 2, CategoryID: 2, Name: "galley", Type: consts.ATTRIBUTE_BOOLEAN},
+		// same name in another branch, its values are not numbers
+		{ID: 3, CategoryID: 3, Name: "seats", Type: consts.ATTRIBUTE_STRING},
+	}
+	adAttributes := []models.AdAttribute{
+		{AdsID: 3, AttributeID: 1, Value: "9"},
+		{AdsID: 2, AttributeID: 1, Value: "180"},
+		{AdsID: 2, AttributeID: 2, Value: "true"},
+		{AdsID: 2, AttributeID: 3, Value: "none"},
+	}
+	if err := db.db.Create(&attributes).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.db.Create(&adAttributes).Error; err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		db.db.Exec("DELETE FROM ad_attributes")
+		db.db.Exec("DELETE FROM category_attributes")
+		db.db.Exec("UPDATE categories SET parent_id = NULL")
+	}()
+
+	testcases := []struct {
+		filter filter.AdsFilter
+		ids    []uint
+	}{
+		// the sub category's ads are listed under the parent
+		{filter.AdsFilter{CategoryID: 1}, []uint{2, 3}},
+		{filter.AdsFilter{CategoryID: 2}, []uint{2}},
+		{filter.AdsFilter{Attributes: []filter.AttributeFilter{{Name: "seats", Op: ">=", Value: "20"}}}, []uint{2}},
+		{filter.AdsFilter{Attributes: []filter.AttributeFilter{{Name: "seats", Op: "<=", Value: "20"}}}, []uint{3}},
+		{filter.AdsFilter{Attributes: []filter.AttributeFilter{{Name: "galley", Op: "=", Value: "true"}}}, []uint{2}},
+	}
+
+	for i, v := range testcases {
+		v.filter.Base = filter.Filter{Limit: 10, UserRole: "Airline"}
+		resp, err := db.ListFilterByColumn(&v.filter)
+		var ids []uint
+		for _, ad := range resp {
+			ids = append(ids, ad.ID)
+		}
+		if err != nil || !reflect.DeepEqual(ids, v.ids) {
+			t.Errorf("[FilterAttributes() TEST%d]Failed. Got %v (%v)\tExpected %v\n", i+1, ids, err, v.ids)
+		} else {
+			fmt.Println("[FilterAttributes() TEST", i+1, "]Pass.")
+		}
+	}
+
+	f := filter.AdsFilter{
+		Base:       filter.Filter{Limit: 10, UserRole: "Airline"},
+		Attributes: []filter.AttributeFilter{{Name: "seats", Op: ">=", Value: "many"}},
+	}
+	if _, err := db.ListFilterByColumn(&f); !reflect.DeepEqual(err, apperror.InvalidParameter("attr.seats.min")) {
+		t.Errorf("[FilterAttributes() TEST%d]Failed. Got %v\tExpected invalid parameter\n", len(testcases)+1, err)
+	}
+}
+
+func testAdStorer_FilterLocation(t *testing.T, db AdDatastorer) {
+	db.db.Exec("UPDATE ads SET airport_code = 'OIII' WHERE id = 2")
+	db.db.Exec("UPDATE ads SET airport_code = 'OMDB' WHERE id = 3")
+	defer db.db.Exec("UPDATE ads SET airport_code = NULL")
+
+	testcases := []struct {
+		filter filter.AdsFilter
+		ids    []uint
+	}{
+		{filter.AdsFilter{Airport: "oiii"}, []uint{2}},
+		{filter.AdsFilter{Country: "AE"}, []uint{3}},
+		{filter.AdsFilter{Country: "US"}, nil},
+		// Mehrabad is about 30km from Imam Khomeini, Dubai about 1200km
+		{filter.AdsFilter{Near: "OIIE", RadiusKM: 100}, []uint{2}},
+		{filter.AdsFilter{Near: "OIIE", RadiusKM: 1500}, []uint{2, 3}},
+		{filter.AdsFilter{Near: "XXXX", RadiusKM: 1500}, nil},
+	}
+
+	for i, v := range testcases {
+		v.filter.Base = filter.Filter{Limit: 10, UserRole: "Airline"}
+		resp, err := db.ListFilterByColumn(&v.filter)
+		var ids []uint
+		for _, ad := range resp {
+			ids = append(ids, ad.ID)
+		}
+		if err != nil || !reflect.DeepEqual(ids, v.ids) {
+			t.Errorf("[FilterLocation() TEST%d]Failed. Got %v (%v)\tExpected %v\n", i+1, ids, err, v.ids)
+		} else {
+			fmt.Println("[FilterLocation() TEST", i+1, "]Pass.")
+		}
+	}
+}
+
 func createUser(t *testing.T, db *gorm.DB) func() {
 	user := models.User{
 		ID:       1,
@@ -0,0 +1,237 @@
+package auction
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// how many times a bid is re-validated after losing a race with another bid
+const bidRetries = 5
+
+var (
+	ErrAdNotFound       = apperror.NotFound("ad_not_found", "ad not found")
+	ErrNotOwner         = apperror.Forbidden("not_owner", "you can only auction your own ads")
+	ErrAlreadyAuctioned = apperror.Conflict("already_auctioned", "ad is already auctioned")
+	ErrAdNotAvailable   = apperror.Conflict("ad_not_available", "ad is not available for auction")
+	ErrAuctionNotFound  = apperror.NotFound("auction_not_found", "auction not found")
+	ErrAuctionNotOpen   = apperror.Conflict("auction_not_open", "auction is not open for bids")
+	ErrOwnAuction       = apperror.Forbidden("own_auction", "you can not bid on your own auction")
+	ErrBidTooLow        = apperror.BadRequest("bid_too_low", "bid is lower than the minimum bid")
+	ErrBidConflict      = apperror.Conflict("bid_conflict", "too many concurrent bids, try again")
+)
+
+type AuctionStorer struct {
+	db *gorm.DB
+}
+
+func NewAuctionStorer(db *gorm.DB) AuctionStorer {
+	return AuctionStorer{db: db}
+}
+
+// Create puts an ad of the user up for auction.
+func (a AuctionStorer) Create(ctx context.Context, adID int, user models.User, auction models.Auction) (models.Auction, error) {
+	var ad models.Ad
+	if err := a.db.WithContext(ctx).First(&ad, adID).Error; err == gorm.ErrRecordNotFound {
+		return models.Auction{}, ErrAdNotFound
+	} else if err != nil {
+		return models.Auction{}, err
+	}
+	if ad.UserID != user.ID {
+		return models.Auction{}, ErrNotOwner
+	}
+	if ad.Status != string(consts.ACTIVE) && ad.Status != string(consts.INACTIVE) {
+		return models.Auction{}, ErrAdNotAvailable
+	}
+
+	auction.AdsID = ad.ID
+	auction.SellerID = user.ID
+	auction.Status = consts.AUCTION_OPEN
+
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.Auction{}).Where("ads_id = ?", ad.ID).Count(&count).Error; err != nil {
+			return err
+		} else if count > 0 {
+			return ErrAlreadyAuctioned
+		}
+
+		if err := tx.Create(&auction).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Ad{}).Where("id = ?", ad.ID).
+			Update("listing_mode", consts.LISTING_AUCTION).Error
+	})
+	if err != nil {
+		return models.Auction{}, err
+	}
+	return auction, nil
+}
+
+func (a AuctionStorer) GetByAd(ctx context.Context, adID int) (models.Auction, error) {
+	var auction models.Auction
+	err := a.db.WithContext(ctx).Where("ads_id = ?", adID).First(&auction).Error
+	if err == gorm.ErrRecordNotFound {
+		return models.Auction{}, ErrAuctionNotFound
+	}
+	return auction, err
+}
+
+// Bids lists the bids of an auction, highest first.
+func (a AuctionStorer) Bids(ctx context.Context, auctionID int) ([]models.Bid, error) {
+	bids := []models.Bid{}
+	err := a.db.WithContext(ctx).
+		Where("auction_id = ?", auctionID).
+		Order("amount DESC, id").
+		Find(&bids).Error
+	return bids, err
+}
+
+// PlaceBid bids amount on an open auction. The auction row is only updated
+// if its version is still the one the bid was validated against; when
+// another bid got there first the bid is validated again against the new
+// highest bid.
+func (a AuctionStorer) PlaceBid(ctx context.Context, auctionID int, user models.User, amount uint64) (models.Auction, error) {
+	for i := 0; i < bidRetries; i++ {
+		var auction models.Auction
+		if err := a.db.WithContext(ctx).Preload("Ads").First(&auction, auctionID).Error; err == gorm.ErrRecordNotFound {
+			return models.Auction{}, ErrAuctionNotFound
+		} else if err != nil {
+			return models.Auction{}, err
+		}
+
+		now := time.Now()
+		if auction.Status != consts.AUCTION_OPEN || auction.Ads.Status != string(consts.ACTIVE) ||
+			now.Before(auction.StartsAt) || !now.Before(auction.EndsAt) {
+			return models.Auction{}, ErrAuctionNotOpen
+		}
+		if auction.SellerID == user.ID {
+			return models.Auction{}, ErrOwnAuction
+		}
+		if amount < MinimumBid(auction) {
+			return models.Auction{}, ErrBidTooLow
+		}
+
+		// anti-sniping: a late bid pushes the end back
+		endsAt := auction.EndsAt
+		extension := time.Duration(auction.ExtensionSeconds) * time.Second
+		if endsAt.Sub(now) < extension {
+			endsAt = now.Add(extension)
+		}
+
+		won := false
+		err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			result := tx.Model(&models.Auction{}).
+				Where("id = ? AND version = ? AND status = ?", auction.ID, auction.Version, consts.AUCTION_OPEN).
+				Updates(map[string]interface{}{
+					"highest_bid":       amount,
+					"highest_bidder_id": user.ID,
+					"ends_at":           endsAt,
+					"bid_count":         gorm.Expr("bid_count + 1"),
+					"version":           gorm.Expr("version + 1"),
+				})
+			if result.Error != nil {
+				return result.Error
+			} else if result.RowsAffected == 0 {
+				return nil
+			}
+
+			won = true
+			return tx.Create(&models.Bid{AuctionID: auction.ID, UserID: user.ID, Amount: amount, CreatedAt: now}).Error
+		})
+		if err != nil {
+			return models.Auction{}, err
+		}
+		if won {
+			auction.HighestBid = amount
+			auction.HighestBidderID = &user.ID
+			auction.EndsAt = endsAt
+			auction.BidCount++
+			auction.Version++
+			return auction, nil
+		}
+	}
+	return models.Auction{}, ErrBidConflict
+}
+
+// CloseDue closes the open auctions that ended before now. The highest
+// bidder wins if the reserve price is met and the ad is still active, and
+// the ad is then reserved for the winner. Otherwise it goes back to a fixed
+// price listing.
+func (a AuctionStorer) CloseDue(ctx context.Context, now time.Time) ([]models.Auction, error) {
+	var due []models.Auction
+	err := a.db.WithContext(ctx).
+		Where("status = ? AND ends_at <= ?", consts.AUCTION_OPEN, now).
+		Find(&due).Error
+	if err != nil {
+		return nil, err
+	}
+
+	closed := []models.Auction{}
+	for _, auction := range due {
+		if auction.HighestBidderID != nil && auction.HighestBid >= auction.ReservePrice {
+			auction.WinnerID = auction.HighestBidderID
+		}
+
+		done := false
+		err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			// a bid that raced the close bumped the version and moved ends_at
+			result := tx.Model(&models.Auction{}).
+				Where("id = ? AND version = ? AND status = ?", auction.ID, auction.Version, consts.AUCTION_OPEN).
+				Updates(map[string]interface{}{
+					"status":    consts.AUCTION_CLOSED,
+					"winner_id": auction.WinnerID,
+					"closed_at": now,
+					"version":   gorm.Expr("version + 1"),
+				})
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+
+			done = true
+			if auction.WinnerID != nil {
+				reserved := tx.Model(&models.Ad{}).
+					Where("id = ? AND status = ?", auction.AdsID, consts.ACTIVE).
+					Update("status", consts.RESERVED)
+				if reserved.Error != nil || reserved.RowsAffected > 0 {
+					return reserved.Error
+				}
+
+				// the ad left the market meanwhile, nobody can get it
+				auction.WinnerID = nil
+				err := tx.Model(&models.Auction{}).
+					Where("id = ?", auction.ID).
+					Update("winner_id", nil).Error
+				if err != nil {
+					return err
+				}
+			}
+
+			// unsold, the ad is back to a fixed price listing
+			return tx.Model(&models.Ad{}).
+				Where("id = ?", auction.AdsID).
+				Update("listing_mode", consts.LISTING_FIXED).Error
+		})
+		if err != nil {
+			return closed, err
+		}
+		if done {
+			auction.Status = consts.AUCTION_CLOSED
+			auction.ClosedAt = &now
+			closed = append(closed, auction)
+		}
+	}
+	return closed, nil
+}
+
+// MinimumBid is the lowest amount the next bid on the auction can be.
+func MinimumBid(auction models.Auction) uint64 {
+	if auction.BidCount == 0 {
+		return auction.MinIncrement
+	}
+	return auction.HighestBid + auction.MinIncrement
+}
@@ -0,0 +1,257 @@
+package auction
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var seller = models.User{ID: 1, Username: "lessor", Password: "lessor123", Role: consts.ROLE_AIRLINE}
+
+func TestDatastore(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	cleanup := createData(t, db)
+	defer cleanup()
+
+	a := NewAuctionStorer(db)
+	testAuctionStorer_Create(t, a)
+	testAuctionStorer_ConcurrentEqualBids(t, a)
+	testAuctionStorer_ConcurrentRaisingBids(t, a, db)
+	testAuctionStorer_AntiSniping(t, a, db)
+	testAuctionStorer_CloseDue(t, a, db)
+	testAuctionStorer_CloseDueUnavailable(t, a, db)
+}
+
+func testAuctionStorer_Create(t *testing.T, a AuctionStorer) {
+	ctx := context.Background()
+	auction := models.Auction{
+		ReservePrice:     50000,
+		MinIncrement:     1000,
+		StartsAt:         time.Now().Add(-time.Minute),
+		EndsAt:           time.Now().Add(time.Hour),
+		ExtensionSeconds: 60,
+	}
+
+	_, err := a.Create(ctx, 1, models.User{ID: 2}, auction)
+	assert.ErrorIs(t, err, ErrNotOwner)
+
+	created, err := a.Create(ctx, 1, seller, auction)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.AUCTION_OPEN, created.Status)
+
+	_, err = a.Create(ctx, 1, seller, auction)
+	assert.ErrorIs(t, err, ErrAlreadyAuctioned)
+
+	_, err = a.Create(ctx, 3, seller, auction)
+	assert.ErrorIs(t, err, ErrAdNotAvailable)
+
+	_, err = a.PlaceBid(ctx, int(created.ID), seller, 1000)
+	assert.ErrorIs(t, err, ErrOwnAuction)
+
+	_, err = a.PlaceBid(ctx, int(created.ID), models.User{ID: 2}, 999)
+	assert.ErrorIs(t, err, ErrBidTooLow)
+}
+
+// placeBids bids concurrently, one goroutine per amount, and returns the
+// amounts that were accepted.
+func placeBids(t *testing.T, a AuctionStorer, auctionID int, amounts []uint64) []uint64 {
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		accepted []uint64
+	)
+	start := make(chan struct{})
+	for i, amount := range amounts {
+		wg.Add(1)
+		go func(bidder uint, amount uint64) {
+			defer wg.Done()
+			<-start
+			_, err := a.PlaceBid(context.Background(), auctionID, models.User{ID: bidder}, amount)
+			if err == nil {
+				mu.Lock()
+				accepted = append(accepted, amount)
+				mu.Unlock()
+			} else if err != ErrBidTooLow && err != ErrBidConflict {
+				t.Errorf("unexpected bid error: %v", err)
+			}
+		}(uint(i%3+2), amount)
+	}
+	close(start)
+	wg.Wait()
+	return accepted
+}
+
+func testAuctionStorer_ConcurrentEqualBids(t *testing.T, a AuctionStorer) {
+	amounts := make([]uint64, 20)
+	for i := range amounts {
+		amounts[i] = 10000
+	}
+
+	// only one of the simultaneous equal bids can be the highest one
+	accepted := placeBids(t, a, 1, amounts)
+	assert.Equal(t, 1, len(accepted))
+
+	bids, err := a.Bids(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(bids))
+}
+
+func testAuctionStorer_ConcurrentRaisingBids(t *testing.T, a AuctionStorer, db *gorm.DB) {
+	var amounts []uint64
+	for i := 1; i <= 20; i++ {
+		amounts = append(amounts, 10000+uint64(i)*1000)
+	}
+
+	accepted := placeBids(t, a, 1, amounts)
+	assert.NotEmpty(t, accepted)
+
+	var highest uint64
+	for _, amount := range accepted {
+		if amount > highest {
+			highest = amount
+		}
+	}
+
+	var auction models.Auction
+	db.First(&auction, 1)
+	assert.Equal(t, highest, auction.HighestBid)
+	assert.Equal(t, uint(len(accepted)+1), auction.BidCount)
+
+	bids, err := a.Bids(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, len(accepted)+1, len(bids))
+	assert.Equal(t, highest, bids[0].Amount)
+	fmt.Println("[ConcurrentRaisingBids() TEST ]", len(accepted), "of", len(amounts), "bids accepted")
+}
+
+func testAuctionStorer_AntiSniping(t *testing.T, a AuctionStorer, db *gorm.DB) {
+	endsAt := time.Now().Add(10 * time.Second)
+	db.Model(&models.Auction{}).Where("id = ?", 1).Update("ends_at", endsAt)
+
+	auction, err := a.PlaceBid(context.Background(), 1, models.User{ID: 2}, 100000)
+	assert.NoError(t, err)
+	assert.True(t, auction.EndsAt.After(endsAt.Add(40*time.Second)))
+}
+
+func testAuctionStorer_CloseDue(t *testing.T, a AuctionStorer, db *gorm.DB) {
+	ctx := context.Background()
+
+	// a second auction that will not meet its reserve
+	unmet, err := a.Create(ctx, 2, seller, models.Auction{
+		ReservePrice:     900000,
+		MinIncrement:     1000,
+		StartsAt:         time.Now().Add(-time.Minute),
+		EndsAt:           time.Now().Add(time.Hour),
+		ExtensionSeconds: 60,
+	})
+	assert.NoError(t, err)
+	_, err = a.PlaceBid(ctx, int(unmet.ID), models.User{ID: 3}, 5000)
+	assert.NoError(t, err)
+
+	closed, err := a.CloseDue(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Empty(t, closed)
+
+	closed, err = a.CloseDue(ctx, time.Now().Add(2*time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(closed))
+
+	won, err := a.GetByAd(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.AUCTION_CLOSED, won.Status)
+	if assert.NotNil(t, won.WinnerID) {
+		assert.Equal(t, uint(2), *won.WinnerID)
+	}
+	var ad models.Ad
+	db.First(&ad, 1)
+	assert.Equal(t, string(consts.RESERVED), ad.Status)
+
+	notWon, err := a.GetByAd(ctx, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, notWon.WinnerID)
+	var unsold models.Ad
+	db.First(&unsold, 2)
+	assert.Equal(t, string(consts.ACTIVE), unsold.Status)
+
+	assert.Equal(t, consts.LISTING_FIXED, unsold.ListingMode)
+
+	_, err = a.PlaceBid(ctx, int(unmet.ID), models.User{ID: 3}, 900000)
+	assert.ErrorIs(t, err, ErrAuctionNotOpen)
+}
+
+func testAuctionStorer_CloseDueUnavailable(t *testing.T, a AuctionStorer, db *gorm.DB) {
+	ctx := context.Background()
+
+	auction, err := a.Create(ctx, 4, seller, models.Auction{
+		MinIncrement:     1000,
+		StartsAt:         time.Now().Add(-time.Minute),
+		EndsAt:           time.Now().Add(time.Hour),
+		ExtensionSeconds: 60,
+	})
+	assert.NoError(t, err)
+	_, err = a.PlaceBid(ctx, int(auction.ID), models.User{ID: 3}, 5000)
+	assert.NoError(t, err)
+
+	// the ad was sold some other way before the auction ended
+	db.Model(&models.Ad{}).Where("id = ?", 4).Update("status", consts.SOLD)
+
+	closed, err := a.CloseDue(ctx, time.Now().Add(2*time.Hour))
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(closed)) {
+		assert.Nil(t, closed[0].WinnerID)
+	}
+
+	stored, err := a.GetByAd(ctx, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.AUCTION_CLOSED, stored.Status)
+	assert.Nil(t, stored.WinnerID)
+	var ad models.Ad
+	db.First(&ad, 4)
+	assert.Equal(t, string(consts.SOLD), ad.Status)
+}
+
+func createData(t *testing.T, db *gorm.DB) func() {
+	users := []models.User{
+		seller,
+		{ID: 2, Username: "bidder1", Password: "bidder123", Role: consts.ROLE_AIRLINE},
+		{ID: 3, Username: "bidder2", Password: "bidder123", Role: consts.ROLE_AIRLINE},
+		{ID: 4, Username: "bidder3", Password: "bidder123", Role: consts.ROLE_AIRLINE},
+	}
+	ads := []models.Ad{
+		{ID: 1, UserID: seller.ID, Subject: "Retired A320", Price: 1, CategoryID: 1, Status: string(consts.ACTIVE)},
+		{ID: 2, UserID: seller.ID, Subject: "Retired 737", Price: 1, CategoryID: 1, Status: string(consts.ACTIVE)},
+		{ID: 3, UserID: seller.ID, Subject: "Sold ATR 72", Price: 1, CategoryID: 1, Status: string(consts.SOLD)},
+		{ID: 4, UserID: seller.ID, Subject: "Retired CRJ", Price: 1, CategoryID: 1, Status: string(consts.ACTIVE)},
+	}
+
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Category{ID: 1, Name: "big-passenger"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&ads).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return func() {
+		db.Exec("DELETE FROM bids")
+		db.Exec("DELETE FROM auctions")
+		db.Exec("DELETE FROM ads")
+		db.Exec("DELETE FROM categories")
+		db.Exec("DELETE FROM users")
+	}
+}
@@ -0,0 +1,216 @@
+package bookmarks
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/models"
+	"context"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCollectionNotFound  = apperror.NotFound("collection_not_found", "collection not found")
+	ErrCollectionExists    = apperror.Conflict("collection_exists", "you already have a collection with this name")
+	ErrAlreadyInCollection = apperror.Conflict("already_in_collection", "the bookmark is already in this collection")
+	ErrNotInCollection     = apperror.NotFound("not_in_collection", "the bookmark is not in this collection")
+)
+
+type CollectionStorer struct {
+	db *gorm.DB
+}
+
+func NewCollectionStorer(db *gorm.DB) CollectionStorer {
+	return CollectionStorer{db: db}
+}
+
+func (s CollectionStorer) Create(ctx context.Context, userID uint, name string) (models.BookmarkCollection, error) {
+	collection := models.BookmarkCollection{UserID: userID, Name: name}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := uniqueName(tx, userID, name, 0); err != nil {
+			return err
+		}
+		return tx.Create(&collection).Error
+	})
+	if err != nil {
+		return models.BookmarkCollection{}, err
+	}
+	return collection, nil
+}
+
+// List lists the collections of the user by name, with their sizes.
+func (s CollectionStorer) List(ctx context.Context, userID uint) ([]models.BookmarkCollection, error) {
+	db := s.db.WithContext(ctx)
+
+	collections := []models.BookmarkCollection{}
+	err := db.Where("user_id = ?", userID).Order("name, id").Find(&collections).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var sizes []struct {
+		CollectionID uint
+		Size         int
+	}
+	err = db.Model(&models.BookmarkCollectionItem{}).
+		Select("collection_id, COUNT(*) AS size").
+		Where("user_id = ?", userID).
+		Group("collection_id").
+		Scan(&sizes).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, size := range sizes {
+		for i := range collections {
+			if collections[i].ID == size.CollectionID {
+				collections[i].Size = size.Size
+			}
+		}
+	}
+	return collections, nil
+}
+
+func (s CollectionStorer) Rename(ctx context.Context, id int, userID uint, name string) (models.BookmarkCollection, error) {
+	var collection models.BookmarkCollection
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if collection, err = getCollection(tx, id, userID); err != nil {
+			return err
+		}
+		if err := uniqueName(tx, userID, name, collection.ID); err != nil {
+			return err
+		}
+		collection.Name = name
+		return tx.Model(&collection).Update("name", name).Error
+	})
+	if err != nil {
+		return models.BookmarkCollection{}, err
+	}
+	return collection, nil
+}
+
+// Delete removes the collection and its shares, its bookmarks are kept.
+func (s CollectionStorer) Delete(ctx context.Context, id int, userID uint) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := getCollection(tx, id, userID); err != nil {
+			return err
+		}
+		if err := tx.Where("collection_id = ?", id).Delete(&models.BookmarkCollectionItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("collection_id = ?", id).Delete(&models.BookmarkShare{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.BookmarkCollection{}, id).Error
+	})
+}
+
+// Add puts the bookmark of the ad into the collection, the ad has to be
+// bookmarked first.
+func (s CollectionStorer) Add(ctx context.Context, id int, userID uint, adID int) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := getCollection(tx, id, userID); err != nil {
+			return err
+		}
+		if _, err := getBookmark(tx, userID, adID); err != nil {
+			return err
+		}
+
+		var count int64
+		err := tx.Model(&models.BookmarkCollectionItem{}).
+			Where("collection_id = ? AND ads_id = ?", id, adID).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAlreadyInCollection
+		}
+
+		return tx.Create(&models.BookmarkCollectionItem{
+			CollectionID: uint(id),
+			AdsID:        uint(adID),
+			UserID:       userID,
+		}).Error
+	})
+}
+
+func (s CollectionStorer) Remove(ctx context.Context, id int, userID uint, adID int) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := getCollection(tx, id, userID); err != nil {
+			return err
+		}
+		result := tx.Where("collection_id = ? AND ads_id = ?", id, adID).Delete(&models.BookmarkCollectionItem{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotInCollection
+		}
+		return nil
+	})
+}
+
+// Annotate replaces the private note and tags of a bookmark.
+func (s CollectionStorer) Annotate(ctx context.Context, userID uint, adID int, note string, tags []string) (models.Bookmarks, error) {
+	var bookmark models.Bookmarks
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if bookmark, err = getBookmark(tx, userID, adID); err != nil {
+			return err
+		}
+		bookmark.Note = note
+		bookmark.Tags = strings.Join(tags, ",")
+		return tx.Model(&models.Bookmarks{}).
+			Where("user_id = ? AND ads_id = ?", userID, adID).
+			Updates(map[string]interface{}{"note": bookmark.Note, "tags": bookmark.Tags}).Error
+	})
+	if err != nil {
+		return models.Bookmarks{}, err
+	}
+	return bookmark, nil
+}
+
+// SplitTags turns the stored tags of a bookmark back into a list.
+func SplitTags(tags string) []string {
+	if tags == "" {
+		return []string{}
+	}
+	return strings.Split(tags, ",")
+}
+
+// getCollection loads a collection of the user, collections of others are
+// not found.
+func getCollection(db *gorm.DB, id int, userID uint) (models.BookmarkCollection, error) {
+	var collection models.BookmarkCollection
+	err := db.Where("id = ? AND user_id = ?", id, userID).First(&collection).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.BookmarkCollection{}, ErrCollectionNotFound
+	}
+	return collection, err
+}
+
+func getBookmark(db *gorm.DB, userID uint, adID int) (models.Bookmarks, error) {
+	var bookmark models.Bookmarks
+	err := db.Where("user_id = ? AND ads_id = ?", userID, adID).First(&bookmark).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.Bookmarks{}, ErrNotBookmarked
+	}
+	return bookmark, err
+}
+
+// uniqueName fails if another collection of the user has the name.
+func uniqueName(db *gorm.DB, userID uint, name string, exceptID uint) error {
+	var count int64
+	err := db.Model(&models.BookmarkCollection{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userID, name, exceptID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCollectionExists
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package bookmarks
+
+import (
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectionStorer(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	cleanup1 := createUser(t, db)
+	defer cleanup1()
+
+	cleanup2 := createCategories(t, db)
+	defer cleanup2()
+
+	cleanup3 := createAds(t, db)
+	defer cleanup3()
+
+	cleanup4 := createBookmarks(t, db)
+	defer cleanup4()
+
+	s := NewCollectionStorer(db)
+	testCollectionStorer_CreateRename(t, s)
+	testCollectionStorer_Items(t, s, New(db))
+	testCollectionStorer_Annotate(t, s, New(db))
+	testCollectionStorer_Delete(t, s, New(db))
+}
+
+func testCollectionStorer_CreateRename(t *testing.T, s CollectionStorer) {
+	ctx := context.Background()
+
+	created, err := s.Create(ctx, 2, "A320 replacement")
+	assert.NoError(t, err)
+	_, err = s.Create(ctx, 2, "cargo conversion candidates")
+	assert.NoError(t, err)
+
+	_, err = s.Create(ctx, 2, "A320 replacement")
+	assert.ErrorIs(t, err, ErrCollectionExists)
+
+	// names are only unique per user
+	_, err = s.Create(ctx, 1, "A320 replacement")
+	assert.NoError(t, err)
+
+	_, err = s.Rename(ctx, int(created.ID), 2, "cargo conversion candidates")
+	assert.ErrorIs(t, err, ErrCollectionExists)
+	_, err = s.Rename(ctx, int(created.ID), 1, "fleet")
+	assert.ErrorIs(t, err, ErrCollectionNotFound)
+
+	renamed, err := s.Rename(ctx, int(created.ID), 2, "A321 replacement")
+	assert.NoError(t, err)
+	assert.Equal(t, "A321 replacement", renamed.Name)
+}
+
+func testCollectionStorer_Items(t *testing.T, s CollectionStorer, b BookmarkDatastorer) {
+	ctx := context.Background()
+
+	// user 2 bookmarked ads 1 and 2, collection 1 is theirs
+	assert.NoError(t, s.Add(ctx, 1, 2, 1))
+	assert.NoError(t, s.Add(ctx, 2, 2, 1))
+	assert.ErrorIs(t, s.Add(ctx, 1, 2, 1), ErrAlreadyInCollection)
+	assert.ErrorIs(t, s.Add(ctx, 1, 2, 3), ErrNotBookmarked)
+	assert.ErrorIs(t, s.Add(ctx, 1, 1, 1), ErrCollectionNotFound)
+
+	collections, err := s.List(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(collections))
+	assert.Equal(t, "A321 replacement", collections[0].Name)
+	assert.Equal(t, 1, collections[0].Size)
+
+	ads, err := b.GetAdsByUserID(2, models.BookmarkListQuery{CollectionID: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(ads))
+	assert.Equal(t, uint(1), ads[0].ID)
+	assert.Equal(t, []uint{1, 2}, ads[0].Collections)
+
+	_, err = b.GetAdsByUserID(1, models.BookmarkListQuery{CollectionID: 1})
+	assert.ErrorIs(t, err, ErrCollectionNotFound)
+
+	assert.NoError(t, s.Remove(ctx, 2, 2, 1))
+	assert.ErrorIs(t, s.Remove(ctx, 2, 2, 1), ErrNotInCollection)
+}
+
+func testCollectionStorer_Annotate(t *testing.T, s CollectionStorer, b BookmarkDatastorer) {
+	ctx := context.Background()
+
+	bookmark, err := s.Annotate(ctx, 2, 2, "ask about the engine hours", []string{"cargo", "urgent"})
+	assert.NoError(t, err)
+	assert.Equal(t, "cargo,urgent", bookmark.Tags)
+
+	_, err = s.Annotate(ctx, 2, 3, "", nil)
+	assert.ErrorIs(t, err, ErrNotBookmarked)
+
+	ads, err := b.GetAdsByUserID(2, models.BookmarkListQuery{})
+	assert.NoError(t, err)
+	for _, ad := range ads {
+		if ad.ID == 2 {
+			assert.Equal(t, "ask about the engine hours", ad.Note)
+			assert.Equal(t, []string{"cargo", "urgent"}, ad.Tags)
+		} else {
+			assert.Equal(t, []string{}, ad.Tags)
+		}
+	}
+}
+
+func testCollectionStorer_Delete(t *testing.T, s CollectionStorer, b BookmarkDatastorer) {
+	ctx := context.Background()
+
+	assert.ErrorIs(t, s.Delete(ctx, 1, 1), ErrCollectionNotFound)
+	assert.NoError(t, s.Delete(ctx, 1, 2))
+
+	// the bookmarks outlive their collections
+	ads, err := b.GetAdsByUserID(2, models.BookmarkListQuery{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(ads))
+
+	// and a removed bookmark leaves its collections
+	assert.NoError(t, s.Add(ctx, 2, 2, 2))
+	assert.NoError(t, b.DeleteBookmark(2, 2))
+	collections, err := s.List(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, collections[0].Size)
+}
@@ -0,0 +1,71 @@
+package bookmarks
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBookmarkStorer_List(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	cleanup1 := createUser(t, db)
+	defer cleanup1()
+
+	cleanup2 := createCategories(t, db)
+	defer cleanup2()
+
+	cleanup3 := createAds(t, db)
+	defer cleanup3()
+
+	cleanup4 := createBookmarks(t, db)
+	defer cleanup4()
+
+	// user 2 bookmarked ad 2, then ad 1 and then an ad that was removed
+	start := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	bookmarkedAt := map[uint]time.Time{2: start, 1: start.Add(time.Hour), 9: start.Add(2 * time.Hour)}
+	assert.NoError(t, db.Create(&models.Bookmarks{UserID: 2, AdsID: 9}).Error)
+	for adID, at := range bookmarkedAt {
+		assert.NoError(t, db.Model(&models.Bookmarks{}).
+			Where("user_id = ? AND ads_id = ?", 2, adID).
+			Update("bookmarked_at", at).Error)
+	}
+	assert.NoError(t, db.Model(&models.Ad{}).Where("id = ?", 1).Update("status", consts.SOLD).Error)
+
+	b := New(db)
+
+	ads, err := b.GetAdsByUserID(2, models.BookmarkListQuery{Sort: consts.BOOKMARK_SORT_DATE, Desc: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(ads))
+	assert.Equal(t, []uint{9, 1, 2}, []uint{ads[0].ID, ads[1].ID, ads[2].ID})
+	assert.Equal(t, consts.BOOKMARK_REMOVED, ads[0].Availability)
+	assert.Equal(t, consts.BOOKMARK_SOLD, ads[1].Availability)
+	assert.Equal(t, consts.BOOKMARK_AVAILABLE, ads[2].Availability)
+	assert.True(t, ads[2].BookmarkedAt.Equal(start))
+	assert.Equal(t, uint64(2000), ads[2].Price)
+
+	ads, err = b.GetAdsByUserID(2, models.BookmarkListQuery{Sort: consts.BOOKMARK_SORT_PRICE})
+	assert.NoError(t, err)
+	prices := []uint64{}
+	for _, ad := range ads {
+		if ad.Availability != consts.BOOKMARK_REMOVED {
+			prices = append(prices, ad.Price)
+		}
+	}
+	assert.Equal(t, []uint64{1000, 2000}, prices)
+
+	ads, err = b.GetAdsByUserID(2, models.BookmarkListQuery{Sort: consts.BOOKMARK_SORT_DATE, Page: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(ads))
+
+	_, err = b.GetAdsByUserID(7, models.BookmarkListQuery{})
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
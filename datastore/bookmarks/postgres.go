@@ -1,14 +1,25 @@
 package bookmarks
 
 import (
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/consts"
 	"Airplane-Divar/models"
+	"Airplane-Divar/utils"
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrUserNotFound      = apperror.NotFound("user_not_found", "Invalid UserID")
+	ErrAdNotFound        = apperror.NotFound("ad_not_found", "Invalid AdID")
+	ErrAdNotActive       = apperror.Conflict("ad_not_active", "You can't bookmark this ad")
+	ErrAlreadyBookmarked = apperror.Conflict("already_bookmarked", "You bookmarked this ad before")
+	ErrNotBookmarked     = apperror.NotFound("bookmark_not_found", "You didn't bookmark this ad before!")
+)
+
 type BookmarkDatastorer struct {
 	db *gorm.DB
 }
@@ -17,63 +28,130 @@ func New(db *gorm.DB) BookmarkDatastorer {
 	return BookmarkDatastorer{db: db}
 }
 
-func (b BookmarkDatastorer) GetAdsByUserID(id int) ([]models.AdResponse, error) {
+// bookmarkedAd is a row of the bookmark listing, the ad columns are null
+// when the ad doesn't exist anymore.
+type bookmarkedAd struct {
+	models.Ad
+	BookmarkAdsID uint
+	Note          string
+	Tags          string
+	BookmarkedAt  time.Time
+}
+
+// GetAdsByUserID lists a page of the bookmarked ads of the user, with
+// query.CollectionID only those in that collection of the user. Bookmarks of
+// ads that were removed are kept with only their ad ID.
+func (b BookmarkDatastorer) GetAdsByUserID(id int, query models.BookmarkListQuery) ([]models.BookmarkedAdResponse, error) {
 	var user models.User
 	b.db.Where("id = ?", id).First(&user)
 	if user.ID == 0 {
-		return []models.AdResponse{}, fmt.Errorf("Invalid UserID")
+		return []models.BookmarkedAdResponse{}, ErrUserNotFound
+	}
+
+	rows := b.db.Table("bookmarks").
+		Select("ads.*, bookmarks.ads_id AS bookmark_ads_id, bookmarks.note, bookmarks.tags, bookmarks.bookmarked_at").
+		Joins("LEFT JOIN ads ON ads.id = bookmarks.ads_id").
+		Where("bookmarks.user_id = ?", id)
+	if query.CollectionID != 0 {
+		if _, err := getCollection(b.db, query.CollectionID, uint(id)); err != nil {
+			return []models.BookmarkedAdResponse{}, err
+		}
+		rows = rows.Where("bookmarks.ads_id IN (?)", b.db.Model(&models.BookmarkCollectionItem{}).
+			Select("ads_id").
+			Where("collection_id = ?", query.CollectionID))
 	}
 
-	var bookmarks []models.Bookmarks
+	order := "ASC"
+	if query.Desc {
+		order = "DESC"
+	}
+	column := "bookmarks.bookmarked_at"
+	if query.Sort == consts.BOOKMARK_SORT_PRICE {
+		column = "ads.price"
+	}
 
-	res := b.db.Where("user_id = ?", id).Find(&bookmarks)
+	var bookmarked []bookmarkedAd
+	res := rows.Order(fmt.Sprintf("%s %s, bookmarks.ads_id %s", column, order, order)).
+		Scopes(utils.Paginate(query.Page)).
+		Scan(&bookmarked)
 	if res.Error != nil {
-		return []models.AdResponse{}, fmt.Errorf("Database Failed")
-	}
-
-	var ads []models.AdResponse
-	for _, book := range bookmarks {
-		var ad models.Ad
-		b.db.Where("id = ?", book.AdsID).First(&ad)
-		adRes := models.AdResponse{
-			ID:            ad.ID,
-			UserID:        ad.UserID,
-			Image:         ad.Image,
-			Description:   ad.Description,
-			Subject:       ad.Subject,
-			Price:         ad.Price,
-			CategoryID:    ad.CategoryID,
-			Status:        ad.Status,
-			FlyTime:       ad.FlyTime,
-			AirplaneModel: ad.AirplaneModel,
-			RepairCheck:   ad.RepairCheck,
-			ExpertCheck:   ad.ExpertCheck,
-			PlaneAge:      ad.PlaneAge,
+		return []models.BookmarkedAdResponse{}, fmt.Errorf("Database Failed")
+	}
+
+	adIDs := make([]uint, 0, len(bookmarked))
+	for _, book := range bookmarked {
+		adIDs = append(adIDs, book.BookmarkAdsID)
+	}
+	var items []models.BookmarkCollectionItem
+	res = b.db.Where("user_id = ? AND ads_id IN ?", id, adIDs).Order("collection_id").Find(&items)
+	if res.Error != nil {
+		return []models.BookmarkedAdResponse{}, fmt.Errorf("Database Failed")
+	}
+
+	ads := make([]models.BookmarkedAdResponse, 0, len(bookmarked))
+	for _, book := range bookmarked {
+		ad := book.Ad
+		adRes := models.BookmarkedAdResponse{
+			AdResponse: models.AdResponse{
+				ID:            book.BookmarkAdsID,
+				UserID:        ad.UserID,
+				Image:         ad.Image,
+				Description:   ad.Description,
+				Subject:       ad.Subject,
+				Price:         ad.Price,
+				CategoryID:    ad.CategoryID,
+				Status:        ad.Status,
+				FlyTime:       ad.FlyTime,
+				AirplaneModel: ad.AirplaneModel,
+				RepairCheck:   ad.RepairCheck,
+				ExpertCheck:   ad.ExpertCheck,
+				PlaneAge:      ad.PlaneAge,
+			},
+			Note:         book.Note,
+			Tags:         SplitTags(book.Tags),
+			Collections:  []uint{},
+			BookmarkedAt: book.BookmarkedAt,
+			Availability: availability(ad),
+		}
+		for _, item := range items {
+			if item.AdsID == book.BookmarkAdsID {
+				adRes.Collections = append(adRes.Collections, item.CollectionID)
+			}
 		}
-		// if ad.ID == 0 {
-		// 	b.db.Delete(&book)
-		// } else {
 		ads = append(ads, adRes)
-		//}
 	}
 	return ads, nil
 }
 
+// availability tells whether a bookmarked ad can still be bought.
+func availability(ad models.Ad) string {
+	if ad.ID == 0 {
+		return consts.BOOKMARK_REMOVED
+	}
+	switch consts.AdStatus(ad.Status) {
+	case consts.ACTIVE:
+		return consts.BOOKMARK_AVAILABLE
+	case consts.SOLD:
+		return consts.BOOKMARK_SOLD
+	}
+	return consts.BOOKMARK_UNAVAILABLE
+}
+
 func (b BookmarkDatastorer) AddBookmark(userID, adID int) (models.BookmarksResponse, error) {
 	var user models.User
 	b.db.Where("id = ?", userID).First(&user)
 	if user.ID == 0 {
-		return models.BookmarksResponse{}, fmt.Errorf("Invalid UserID")
+		return models.BookmarksResponse{}, ErrUserNotFound
 	}
 
 	var ad models.Ad
 	b.db.Where("id = ?", adID).First(&ad)
 	if ad.ID == 0 {
-		return models.BookmarksResponse{}, fmt.Errorf("Invalid AdID")
+		return models.BookmarksResponse{}, ErrAdNotFound
 	}
 
 	if ad.Status != string(consts.ACTIVE) {
-		return models.BookmarksResponse{}, fmt.Errorf("You can't bookmark this ad")
+		return models.BookmarksResponse{}, ErrAdNotActive
 	}
 
 	bookmark := models.Bookmarks{
@@ -83,7 +161,7 @@ func (b BookmarkDatastorer) AddBookmark(userID, adID int) (models.BookmarksRespo
 
 	res := b.db.Create(&bookmark)
 	if res.Error != nil {
-		return models.BookmarksResponse{}, fmt.Errorf("You bookmarked this ad before")
+		return models.BookmarksResponse{}, ErrAlreadyBookmarked
 	}
 
 	bookRes := models.BookmarksResponse{
@@ -98,13 +176,13 @@ func (b BookmarkDatastorer) DeleteBookmark(userID, adID int) error {
 	var user models.User
 	b.db.Where("id = ?", userID).First(&user)
 	if user.ID == 0 {
-		return fmt.Errorf("Invalid UserID")
+		return ErrUserNotFound
 	}
 
 	var ad models.Ad
 	b.db.Where("id = ?", adID).First(&ad)
 	if ad.ID == 0 {
-		return fmt.Errorf("Invalid AdID")
+		return ErrAdNotFound
 	}
 
 	var book models.Bookmarks
@@ -112,13 +190,19 @@ func (b BookmarkDatastorer) DeleteBookmark(userID, adID int) error {
 
 	if res.Error != nil {
 		if errors.Is(res.Error, gorm.ErrRecordNotFound) {
-			return fmt.Errorf("You didn't bookmark this ad before!")
+			return ErrNotBookmarked
 		}
 		return fmt.Errorf("Database failed")
 	}
 
-	res = b.db.Delete(&book)
-	if res.Error != nil {
+	err := b.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ? AND ads_id = ?", userID, adID).Delete(&models.BookmarkCollectionItem{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND ads_id = ?", userID, adID).Delete(&models.Bookmarks{}).Error
+	})
+	if err != nil {
 		return fmt.Errorf("Failed to Delete Bookmark")
 	}
 	return nil
@@ -94,7 +94,11 @@ func testBookmarkStorer_GetAdsByUserID(t *testing.T, db BookmarkDatastorer) {
 	}
 
 	for i, v := range testcases {
-		resp, _ := db.GetAdsByUserID(v.user_id)
+		bookmarked, _ := db.GetAdsByUserID(v.user_id, models.BookmarkListQuery{})
+		resp := []models.AdResponse{}
+		for _, ad := range bookmarked {
+			resp = append(resp, ad.AdResponse)
+		}
 
 		if !reflect.DeepEqual(resp, v.res) {
 			t.Errorf("[GetAdsByUserID() TEST%d]Failed. Got %v\tExpected %v\n", i+1, resp, v.res)
@@ -0,0 +1,115 @@
+package bookmarks
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrShareNotFound is also returned for expired and revoked shares, so a
+// token can't be told apart from one that never existed.
+var ErrShareNotFound = apperror.NotFound("share_not_found", "share not found")
+
+type ShareStorer struct {
+	db *gorm.DB
+}
+
+func NewShareStorer(db *gorm.DB) ShareStorer {
+	return ShareStorer{db: db}
+}
+
+// HashShareToken is how share tokens are stored and looked up.
+func HashShareToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Create shares a collection of the user until expiresAt.
+func (s ShareStorer) Create(ctx context.Context, collectionID int, userID uint, token string, expiresAt time.Time) (models.BookmarkShare, error) {
+	share := models.BookmarkShare{
+		CollectionID: uint(collectionID),
+		UserID:       userID,
+		TokenHash:    HashShareToken(token),
+		ExpiresAt:    expiresAt,
+	}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := getCollection(tx, collectionID, userID); err != nil {
+			return err
+		}
+		return tx.Create(&share).Error
+	})
+	if err != nil {
+		return models.BookmarkShare{}, err
+	}
+	return share, nil
+}
+
+// List lists the shares of a collection of the user, newest first.
+func (s ShareStorer) List(ctx context.Context, collectionID int, userID uint) ([]models.BookmarkShare, error) {
+	db := s.db.WithContext(ctx)
+	if _, err := getCollection(db, collectionID, userID); err != nil {
+		return nil, err
+	}
+
+	shares := []models.BookmarkShare{}
+	err := db.Where("collection_id = ?", collectionID).Order("id DESC").Find(&shares).Error
+	return shares, err
+}
+
+// Revoke ends a share of the user, revoking it again keeps the first time.
+func (s ShareStorer) Revoke(ctx context.Context, id int, userID uint, now time.Time) (models.BookmarkShare, error) {
+	var share models.BookmarkShare
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("id = ? AND user_id = ?", id, userID).First(&share).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrShareNotFound
+		} else if err != nil {
+			return err
+		}
+		if share.RevokedAt != nil {
+			return nil
+		}
+		share.RevokedAt = &now
+		return tx.Model(&share).Update("revoked_at", now).Error
+	})
+	if err != nil {
+		return models.BookmarkShare{}, err
+	}
+	return share, nil
+}
+
+// Resolve finds the share of a token that is still usable at now, with the
+// name of its collection.
+func (s ShareStorer) Resolve(ctx context.Context, token string, now time.Time) (models.BookmarkShare, models.BookmarkCollection, error) {
+	db := s.db.WithContext(ctx)
+
+	var share models.BookmarkShare
+	err := db.Where("token_hash = ? AND revoked_at IS NULL AND expires_at > ?", HashShareToken(token), now).
+		First(&share).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.BookmarkShare{}, models.BookmarkCollection{}, ErrShareNotFound
+	} else if err != nil {
+		return models.BookmarkShare{}, models.BookmarkCollection{}, err
+	}
+
+	collection, err := getCollection(db, int(share.CollectionID), share.UserID)
+	if errors.Is(err, ErrCollectionNotFound) {
+		return models.BookmarkShare{}, models.BookmarkCollection{}, ErrShareNotFound
+	}
+	return share, collection, err
+}
+
+// Ads lists a page of the bookmarked ads in the shared collection.
+func (s ShareStorer) Ads(ctx context.Context, share models.BookmarkShare, page int) ([]models.BookmarkedAdResponse, error) {
+	return New(s.db.WithContext(ctx)).GetAdsByUserID(int(share.UserID), models.BookmarkListQuery{
+		CollectionID: int(share.CollectionID),
+		Page:         page,
+		Desc:         true,
+	})
+}
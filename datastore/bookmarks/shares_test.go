@@ -0,0 +1,82 @@
+package bookmarks
+
+import (
+	database "Airplane-Divar/database"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShareStorer(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	cleanup1 := createUser(t, db)
+	defer cleanup1()
+
+	cleanup2 := createCategories(t, db)
+	defer cleanup2()
+
+	cleanup3 := createAds(t, db)
+	defer cleanup3()
+
+	cleanup4 := createBookmarks(t, db)
+	defer cleanup4()
+
+	ctx := context.Background()
+	now := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+
+	collections := NewCollectionStorer(db)
+	collection, err := collections.Create(ctx, 2, "fleet committee")
+	assert.NoError(t, err)
+	assert.NoError(t, collections.Add(ctx, int(collection.ID), 2, 2))
+
+	s := NewShareStorer(db)
+
+	_, err = s.Create(ctx, int(collection.ID), 1, "someone else", now.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrCollectionNotFound)
+
+	share, err := s.Create(ctx, int(collection.ID), 2, "committee-token", now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, HashShareToken("committee-token"), share.TokenHash)
+	assert.NotContains(t, share.TokenHash, "committee-token")
+
+	resolved, named, err := s.Resolve(ctx, "committee-token", now)
+	assert.NoError(t, err)
+	assert.Equal(t, share.ID, resolved.ID)
+	assert.Equal(t, "fleet committee", named.Name)
+
+	ads, err := s.Ads(ctx, resolved, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(ads))
+	assert.Equal(t, uint(2), ads[0].ID)
+
+	_, _, err = s.Resolve(ctx, "committee-token", now.Add(2*time.Hour))
+	assert.ErrorIs(t, err, ErrShareNotFound)
+	_, _, err = s.Resolve(ctx, "guessed-token", now)
+	assert.ErrorIs(t, err, ErrShareNotFound)
+
+	shares, err := s.List(ctx, int(collection.ID), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(shares))
+
+	_, err = s.Revoke(ctx, int(share.ID), 1, now)
+	assert.ErrorIs(t, err, ErrShareNotFound)
+	revoked, err := s.Revoke(ctx, int(share.ID), 2, now)
+	assert.NoError(t, err)
+	assert.NotNil(t, revoked.RevokedAt)
+	_, _, err = s.Resolve(ctx, "committee-token", now)
+	assert.ErrorIs(t, err, ErrShareNotFound)
+
+	// deleting the collection ends its shares
+	_, err = s.Create(ctx, int(collection.ID), 2, "second-token", now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.NoError(t, collections.Delete(ctx, int(collection.ID), 2))
+	_, _, err = s.Resolve(ctx, "second-token", now)
+	assert.ErrorIs(t, err, ErrShareNotFound)
+}
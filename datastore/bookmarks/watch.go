@@ -0,0 +1,39 @@
+package bookmarks
+
+import (
+	"Airplane-Divar/models"
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type WatchStorer struct {
+	db *gorm.DB
+}
+
+func NewWatchStorer(db *gorm.DB) WatchStorer {
+	return WatchStorer{db: db}
+}
+
+// Watchers are the users who bookmarked the ad and didn't mute it.
+func (w WatchStorer) Watchers(ctx context.Context, adID uint) ([]uint, error) {
+	userIDs := []uint{}
+	err := w.db.WithContext(ctx).Model(&models.Bookmarks{}).
+		Where("ads_id = ? AND muted = ?", adID, false).
+		Order("user_id").
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+func (w WatchStorer) SetMuted(ctx context.Context, userID uint, adID int, muted bool) error {
+	result := w.db.WithContext(ctx).Model(&models.Bookmarks{}).
+		Where("user_id = ? AND ads_id = ?", userID, adID).
+		Update("muted", muted)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotBookmarked
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package bookmarks
+
+import (
+	database "Airplane-Divar/database"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchStorer(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	cleanup1 := createUser(t, db)
+	defer cleanup1()
+
+	cleanup2 := createCategories(t, db)
+	defer cleanup2()
+
+	cleanup3 := createAds(t, db)
+	defer cleanup3()
+
+	cleanup4 := createBookmarks(t, db)
+	defer cleanup4()
+
+	ctx := context.Background()
+	w := NewWatchStorer(db)
+
+	watchers, err := w.Watchers(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, watchers)
+
+	assert.NoError(t, w.SetMuted(ctx, 2, 1, true))
+	watchers, err = w.Watchers(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1}, watchers)
+
+	assert.NoError(t, w.SetMuted(ctx, 2, 1, false))
+	watchers, err = w.Watchers(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1, 2}, watchers)
+
+	assert.ErrorIs(t, w.SetMuted(ctx, 1, 2, true), ErrNotBookmarked)
+
+	watchers, err = w.Watchers(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{}, watchers)
+}
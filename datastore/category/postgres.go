@@ -0,0 +1,281 @@
+package category
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCategoryNotFound   = apperror.NotFound("category_not_found", "category not found")
+	ErrCategoryInUse      = apperror.Conflict("category_in_use", "category is used by active ads, reassign them first")
+	ErrCategoryHasChild   = apperror.Conflict("category_has_child", "category has live sub categories, retire or move them first")
+	ErrDuplicateName      = apperror.Conflict("duplicate_name", "category name already exists")
+	ErrCategoryCycle      = apperror.Conflict("category_cycle", "a category can't be moved under itself or one of its sub categories")
+	ErrAttributeNotFound  = apperror.NotFound("attribute_not_found", "category attribute not found")
+	ErrAttributeInUse     = apperror.Conflict("attribute_in_use", "category attribute is set on ads")
+	ErrDuplicateAttribute = apperror.Conflict("duplicate_attribute", "attribute name already exists in this category branch")
+	ErrParentRetired      = apperror.BadRequest("parent_retired", "parent category is retired")
+	ErrInvalidReassign    = apperror.BadRequest("invalid_reassign_target", "ads can only be reassigned to another live category")
+)
+
+// DescendantsSQL selects the id of a category and of every category below it.
+const DescendantsSQL = "WITH RECURSIVE category_tree(id) AS (" +
+	"SELECT id FROM categories WHERE id = ? " +
+	"UNION ALL SELECT categories.id FROM categories JOIN category_tree ON categories.parent_id = category_tree.id" +
+	") SELECT id FROM category_tree"
+
+// AncestorsSQL selects the id of a category and of every category above it.
+const AncestorsSQL = "WITH RECURSIVE category_tree(id, parent_id) AS (" +
+	"SELECT id, parent_id FROM categories WHERE id = ? " +
+	"UNION ALL SELECT categories.id, categories.parent_id FROM categories JOIN category_tree ON categories.id = category_tree.parent_id" +
+	") SELECT id FROM category_tree"
+
+type CategoryStorer struct {
+	db *gorm.DB
+}
+
+func NewCategoryStorer(db *gorm.DB) CategoryStorer {
+	return CategoryStorer{db: db}
+}
+
+func (c CategoryStorer) List(ctx context.Context, includeRetired bool) ([]models.Category, error) {
+	categories := []models.Category{}
+	query := c.db.WithContext(ctx).Order("position").Order("id")
+	if !includeRetired {
+		query = query.Where("retired = ?", false)
+	}
+
+	result := query.Find(&categories)
+	return categories, result.Error
+}
+
+func (c CategoryStorer) Create(ctx context.Context, name string, position int, parentID *uint) (models.Category, error) {
+	if err := c.checkUniqueName(ctx, name, 0); err != nil {
+		return models.Category{}, err
+	}
+	if parentID != nil {
+		parent, err := c.get(ctx, c.db, int(*parentID))
+		if err != nil {
+			return models.Category{}, err
+		}
+		if parent.Retired {
+			return models.Category{}, ErrParentRetired
+		}
+	}
+
+	category := models.Category{Name: name, Position: position, ParentID: parentID}
+	if err := c.db.WithContext(ctx).Create(&category).Error; err != nil {
+		return models.Category{}, err
+	}
+
+	return category, nil
+}
+
+func (c CategoryStorer) Rename(ctx context.Context, id int, name string) (models.Category, error) {
+	category, err := c.get(ctx, c.db, id)
+	if err != nil {
+		return models.Category{}, err
+	}
+	if err := c.checkUniqueName(ctx, name, category.ID); err != nil {
+		return models.Category{}, err
+	}
+
+	category.Name = name
+	if err := c.db.WithContext(ctx).Model(&category).Update("name", name).Error; err != nil {
+		return models.Category{}, err
+	}
+
+	return category, nil
+}
+
+// Move puts a category under parentID, or at the top level when it is nil.
+func (c CategoryStorer) Move(ctx context.Context, id int, parentID *uint) (models.Category, error) {
+	category, err := c.get(ctx, c.db, id)
+	if err != nil {
+		return models.Category{}, err
+	}
+
+	if parentID != nil {
+		parent, err := c.get(ctx, c.db, int(*parentID))
+		if err != nil {
+			return models.Category{}, err
+		}
+		if parent.Retired {
+			return models.Category{}, ErrParentRetired
+		}
+
+		var descendants []uint
+		if err := c.db.WithContext(ctx).Raw(DescendantsSQL, category.ID).Scan(&descendants).Error; err != nil {
+			return models.Category{}, err
+		}
+		for _, d := range descendants {
+			if d == parent.ID {
+				return models.Category{}, ErrCategoryCycle
+			}
+		}
+	}
+
+	category.ParentID = parentID
+	if err := c.db.WithContext(ctx).Model(&category).Update("parent_id", parentID).Error; err != nil {
+		return models.Category{}, err
+	}
+
+	return category, nil
+}
+
+// Attributes lists the attributes of a category including the ones it
+// inherits from its ancestors.
+func (c CategoryStorer) Attributes(ctx context.Context, id int) ([]models.CategoryAttribute, error) {
+	if _, err := c.get(ctx, c.db, id); err != nil {
+		return nil, err
+	}
+
+	attributes := []models.CategoryAttribute{}
+	result := c.db.WithContext(ctx).
+		Where("category_id IN ("+AncestorsSQL+")", id).
+		Order("id").
+		Find(&attributes)
+	return attributes, result.Error
+}
+
+func (c CategoryStorer) CreateAttribute(ctx context.Context, attribute models.CategoryAttribute) (models.CategoryAttribute, error) {
+	if _, err := c.get(ctx, c.db, int(attribute.CategoryID)); err != nil {
+		return models.CategoryAttribute{}, err
+	}
+
+	// a name must stay unambiguous along the whole branch
+	var count int64
+	err := c.db.WithContext(ctx).Model(&models.CategoryAttribute{}).
+		Where("name = ? AND (category_id IN ("+AncestorsSQL+") OR category_id IN ("+DescendantsSQL+"))",
+			attribute.Name, attribute.CategoryID, attribute.CategoryID).
+		Count(&count).Error
+	if err != nil {
+		return models.CategoryAttribute{}, err
+	} else if count > 0 {
+		return models.CategoryAttribute{}, ErrDuplicateAttribute
+	}
+
+	if err := c.db.WithContext(ctx).Create(&attribute).Error; err != nil {
+		return models.CategoryAttribute{}, err
+	}
+	return attribute, nil
+}
+
+func (c CategoryStorer) DeleteAttribute(ctx context.Context, categoryID int, attributeID int) error {
+	var inUse int64
+	err := c.db.WithContext(ctx).Model(&models.AdAttribute{}).
+		Where("attribute_id = ?", attributeID).
+		Count(&inUse).Error
+	if err != nil {
+		return err
+	} else if inUse > 0 {
+		return ErrAttributeInUse
+	}
+
+	result := c.db.WithContext(ctx).
+		Where("id = ? AND category_id = ?", attributeID, categoryID).
+		Delete(&models.CategoryAttribute{})
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrAttributeNotFound
+	}
+	return nil
+}
+
+// Reorder sets the position of every given category to its index in ids.
+func (c CategoryStorer) Reorder(ctx context.Context, ids []uint) error {
+	return c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, id := range ids {
+			result := tx.Model(&models.Category{}).Where("id = ?", id).Update("position", position)
+			if result.Error != nil {
+				return result.Error
+			} else if result.RowsAffected == 0 {
+				return ErrCategoryNotFound
+			}
+		}
+		return nil
+	})
+}
+
+// Retire hides a category from new ads. Retiring a category that active ads
+// still use fails unless reassignTo names another live category, in which case
+// all of its ads are moved there first.
+func (c CategoryStorer) Retire(ctx context.Context, id int, reassignTo int) (models.Category, error) {
+	var category models.Category
+	err := c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		category, err = c.get(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+
+		if reassignTo != 0 {
+			target, err := c.get(ctx, tx, reassignTo)
+			if err != nil {
+				return err
+			}
+			if target.Retired || target.ID == category.ID {
+				return ErrInvalidReassign
+			}
+			err = tx.Model(&models.Ad{}).
+				Where("category_id = ?", category.ID).
+				Update("category_id", target.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		var liveChildren int64
+		err = tx.Model(&models.Category{}).
+			Where("parent_id = ? AND retired = ?", category.ID, false).
+			Count(&liveChildren).Error
+		if err != nil {
+			return err
+		} else if liveChildren > 0 {
+			return ErrCategoryHasChild
+		}
+
+		var activeAds int64
+		err = tx.Model(&models.Ad{}).
+			Where("category_id = ? AND status = ?", category.ID, consts.ACTIVE).
+			Count(&activeAds).Error
+		if err != nil {
+			return err
+		} else if activeAds > 0 {
+			return ErrCategoryInUse
+		}
+
+		category.Retired = true
+		return tx.Model(&category).Update("retired", true).Error
+	})
+
+	return category, err
+}
+
+func (c CategoryStorer) get(ctx context.Context, db *gorm.DB, id int) (models.Category, error) {
+	var category models.Category
+	err := db.WithContext(ctx).First(&category, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return category, ErrCategoryNotFound
+	}
+	return category, err
+}
+
+func (c CategoryStorer) checkUniqueName(ctx context.Context, name string, exceptID uint) error {
+	var count int64
+	err := c.db.WithContext(ctx).Model(&models.Category{}).
+		Where("name = ? AND id != ?", name, exceptID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	} else if count > 0 {
+		return ErrDuplicateName
+	}
+	return nil
+}
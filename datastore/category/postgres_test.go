@@ -0,0 +1,181 @@
+package category
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestDatastore(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	cleanup := createData(t, db)
+	defer cleanup()
+
+	c := NewCategoryStorer(db)
+	testCategoryStorer_CreateRename(t, c)
+	testCategoryStorer_Reorder(t, c)
+	testCategoryStorer_Retire(t, c, db)
+	testCategoryStorer_Tree(t, c, db)
+}
+
+func testCategoryStorer_CreateRename(t *testing.T, c CategoryStorer) {
+	ctx := context.Background()
+
+	created, err := c.Create(ctx, "helicopter", 5, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), created.ID)
+
+	_, err = c.Create(ctx, "helicopter", 0, nil)
+	assert.ErrorIs(t, err, ErrDuplicateName)
+
+	_, err = c.Rename(ctx, 3, "big-passenger")
+	assert.ErrorIs(t, err, ErrDuplicateName)
+
+	renamed, err := c.Rename(ctx, 3, "rotorcraft")
+	assert.NoError(t, err)
+	assert.Equal(t, "rotorcraft", renamed.Name)
+
+	_, err = c.Rename(ctx, 42, "anything")
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+}
+
+func testCategoryStorer_Reorder(t *testing.T, c CategoryStorer) {
+	ctx := context.Background()
+
+	assert.NoError(t, c.Reorder(ctx, []uint{3, 2, 1}))
+
+	categories, err := c.List(ctx, false)
+	assert.NoError(t, err)
+	var names []string
+	for _, cat := range categories {
+		names = append(names, cat.Name)
+	}
+	assert.Equal(t, []string{"rotorcraft", "big-passenger", "small-passenger"}, names)
+
+	assert.ErrorIs(t, c.Reorder(ctx, []uint{1, 42}), ErrCategoryNotFound)
+}
+
+func testCategoryStorer_Retire(t *testing.T, c CategoryStorer, db *gorm.DB) {
+	ctx := context.Background()
+
+	// category 1 still has an active ad
+	_, err := c.Retire(ctx, 1, 0)
+	assert.ErrorIs(t, err, ErrCategoryInUse)
+
+	_, err = c.Retire(ctx, 1, 1)
+	assert.ErrorIs(t, err, ErrInvalidReassign)
+
+	retired, err := c.Retire(ctx, 1, 2)
+	assert.NoError(t, err)
+	assert.True(t, retired.Retired)
+
+	var moved int64
+	db.Model(&models.Ad{}).Where("category_id = ?", 2).Count(&moved)
+	assert.Equal(t, int64(2), moved)
+
+	// ads can't be moved onto a retired category
+	_, err = c.Retire(ctx, 2, 1)
+	assert.ErrorIs(t, err, ErrInvalidReassign)
+
+	live, err := c.List(ctx, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(live))
+
+	all, err := c.List(ctx, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(all))
+}
+
+func testCategoryStorer_Tree(t *testing.T, c CategoryStorer, db *gorm.DB) {
+	ctx := context.Background()
+
+	cargo, err := c.Create(ctx, "cargo", 0, nil)
+	assert.NoError(t, err)
+	heavy, err := c.Create(ctx, "heavy-cargo", 0, &cargo.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, cargo.ID, *heavy.ParentID)
+
+	// a category can't be moved under its own descendant
+	_, err = c.Move(ctx, int(cargo.ID), &heavy.ID)
+	assert.ErrorIs(t, err, ErrCategoryCycle)
+
+	retiredID := uint(1)
+	_, err = c.Move(ctx, int(heavy.ID), &retiredID)
+	assert.ErrorIs(t, err, ErrParentRetired)
+	_, err = c.Create(ctx, "retired-child", 0, &retiredID)
+	assert.ErrorIs(t, err, ErrParentRetired)
+
+	_, err = c.Retire(ctx, int(cargo.ID), 0)
+	assert.ErrorIs(t, err, ErrCategoryHasChild)
+
+	width, err := c.CreateAttribute(ctx, models.CategoryAttribute{CategoryID: cargo.ID, Name: "cargo_door_width", Type: consts.ATTRIBUTE_NUMBER, Unit: "cm", Required: true})
+	assert.NoError(t, err)
+	_, err = c.CreateAttribute(ctx, models.CategoryAttribute{CategoryID: heavy.ID, Name: "max_payload", Type: consts.ATTRIBUTE_NUMBER, Unit: "kg"})
+	assert.NoError(t, err)
+
+	_, err = c.CreateAttribute(ctx, models.CategoryAttribute{CategoryID: heavy.ID, Name: "cargo_door_width", Type: consts.ATTRIBUTE_STRING})
+	assert.ErrorIs(t, err, ErrDuplicateAttribute)
+
+	inherited, err := c.Attributes(ctx, int(heavy.ID))
+	assert.NoError(t, err)
+	var names []string
+	for _, attr := range inherited {
+		names = append(names, attr.Name)
+	}
+	assert.Equal(t, []string{"cargo_door_width", "max_payload"}, names)
+
+	own, err := c.Attributes(ctx, int(cargo.ID))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(own))
+
+	// ads of sub categories are found through the parent category
+	ad := models.Ad{ID: 3, UserID: 1, Subject: "Freighter", Price: 5000, CategoryID: heavy.ID, Status: string(consts.ACTIVE)}
+	assert.NoError(t, db.Create(&ad).Error)
+	assert.NoError(t, db.Create(&models.AdAttribute{AdsID: ad.ID, AttributeID: width.ID, Value: "350"}).Error)
+
+	var found int64
+	db.Model(&models.Ad{}).Where("category_id IN ("+DescendantsSQL+")", cargo.ID).Count(&found)
+	assert.Equal(t, int64(1), found)
+
+	assert.ErrorIs(t, c.DeleteAttribute(ctx, int(cargo.ID), int(width.ID)), ErrAttributeInUse)
+	assert.ErrorIs(t, c.DeleteAttribute(ctx, int(heavy.ID), 42), ErrAttributeNotFound)
+}
+
+func createData(t *testing.T, db *gorm.DB) func() {
+	categories := []models.Category{
+		{ID: 1, Name: "small-passenger"},
+		{ID: 2, Name: "big-passenger"},
+	}
+	ads := []models.Ad{
+		{ID: 1, UserID: 1, Subject: "Active Ad", Price: 1000, CategoryID: 1, Status: string(consts.ACTIVE)},
+		{ID: 2, UserID: 1, Subject: "Inactive Ad", Price: 2000, CategoryID: 1, Status: string(consts.INACTIVE)},
+	}
+
+	if err := db.Create(&models.User{ID: 1, Username: "owner", Password: "owner123", Role: consts.ROLE_AIRLINE}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&categories).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&ads).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return func() {
+		db.Exec("DELETE FROM ad_attributes")
+		db.Exec("DELETE FROM category_attributes")
+		db.Exec("DELETE FROM ads")
+		db.Exec("DELETE FROM categories")
+		db.Exec("DELETE FROM users")
+	}
+}
@@ -0,0 +1,174 @@
+package conversation
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAdNotFound           = apperror.NotFound("ad_not_found", "ad not found")
+	ErrOwnAd                = apperror.BadRequest("own_ad", "you can not start a conversation on your own ad")
+	ErrAdNotAvailable       = apperror.Conflict("ad_not_available", "ad is not available for new conversations")
+	ErrConversationNotFound = apperror.NotFound("conversation_not_found", "conversation not found")
+	ErrNotParticipant       = apperror.Forbidden("not_participant", "only the buyer and the seller can send messages")
+)
+
+// unread messages of the other party, ? is the user id
+const unreadSQL = "(SELECT COUNT(*) FROM messages WHERE messages.conversation_id = conversations.id " +
+	"AND messages.sender_id <> ? AND messages.read_at IS NULL) AS unread"
+
+type ConversationStorer struct {
+	db *gorm.DB
+}
+
+func NewConversationStorer(db *gorm.DB) ConversationStorer {
+	return ConversationStorer{db: db}
+}
+
+// Start returns the conversation of the buyer on an ad, creating it on the
+// first contact. Only active ads can be contacted, a buyer who already
+// talked to the seller keeps the conversation once the ad leaves the market.
+func (c ConversationStorer) Start(ctx context.Context, adID int, buyer models.User) (models.Conversation, error) {
+	var ad models.Ad
+	if err := c.db.WithContext(ctx).First(&ad, adID).Error; err == gorm.ErrRecordNotFound {
+		return models.Conversation{}, ErrAdNotFound
+	} else if err != nil {
+		return models.Conversation{}, err
+	}
+	if ad.UserID == buyer.ID {
+		return models.Conversation{}, ErrOwnAd
+	}
+
+	var conversation models.Conversation
+	err := c.db.WithContext(ctx).
+		Where(models.Conversation{AdsID: ad.ID, BuyerID: buyer.ID}).
+		First(&conversation).Error
+	if err != gorm.ErrRecordNotFound {
+		return conversation, err
+	}
+	if ad.Status != string(consts.ACTIVE) {
+		return models.Conversation{}, ErrAdNotAvailable
+	}
+
+	conversation = models.Conversation{
+		AdsID:         ad.ID,
+		BuyerID:       buyer.ID,
+		SellerID:      ad.UserID,
+		LastMessageAt: time.Now(),
+	}
+	err = c.db.WithContext(ctx).
+		Where(models.Conversation{AdsID: ad.ID, BuyerID: buyer.ID}).
+		FirstOrCreate(&conversation).Error
+	return conversation, err
+}
+
+// Inbox lists the conversations of the user, the most recently active first.
+func (c ConversationStorer) Inbox(ctx context.Context, user models.User) ([]models.Conversation, error) {
+	conversations := []models.Conversation{}
+	err := c.db.WithContext(ctx).
+		Select("conversations.*, "+unreadSQL, user.ID).
+		Where("buyer_id = ? OR seller_id = ?", user.ID, user.ID).
+		Order("last_message_at DESC, id DESC").
+		Find(&conversations).Error
+	return conversations, err
+}
+
+// ListByAd lists every conversation on an ad, for dispute review.
+func (c ConversationStorer) ListByAd(ctx context.Context, adID int) ([]models.Conversation, error) {
+	conversations := []models.Conversation{}
+	err := c.db.WithContext(ctx).
+		Where("ads_id = ?", adID).
+		Order("last_message_at DESC, id DESC").
+		Find(&conversations).Error
+	return conversations, err
+}
+
+// Messages returns the messages of a conversation, oldest first. When a
+// participant reads them the messages of the other party are marked read.
+func (c ConversationStorer) Messages(ctx context.Context, id int, user models.User) ([]models.Message, error) {
+	conversation, err := c.get(ctx, id, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role != consts.ROLE_ADMIN || isParticipant(conversation, user) {
+		err := c.db.WithContext(ctx).Model(&models.Message{}).
+			Where("conversation_id = ? AND sender_id <> ? AND read_at IS NULL", conversation.ID, user.ID).
+			Update("read_at", time.Now()).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	messages := []models.Message{}
+	err = c.db.WithContext(ctx).
+		Where("conversation_id = ?", conversation.ID).
+		Order("created_at, id").
+		Find(&messages).Error
+	return messages, err
+}
+
+func (c ConversationStorer) Send(ctx context.Context, id int, user models.User, body string, attachment string) (models.Message, error) {
+	conversation, err := c.get(ctx, id, user)
+	if err != nil {
+		return models.Message{}, err
+	}
+	if !isParticipant(conversation, user) {
+		return models.Message{}, ErrNotParticipant
+	}
+
+	now := time.Now()
+	message := models.Message{
+		ConversationID: conversation.ID,
+		SenderID:       user.ID,
+		Body:           body,
+		Attachment:     attachment,
+		CreatedAt:      now,
+	}
+	err = c.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&message).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Conversation{}).
+			Where("id = ?", conversation.ID).
+			Update("last_message_at", now).Error
+	})
+	if err != nil {
+		return models.Message{}, err
+	}
+	return message, nil
+}
+
+// UnreadCount is the number of unread messages over all the user's conversations.
+func (c ConversationStorer) UnreadCount(ctx context.Context, user models.User) (int64, error) {
+	var count int64
+	err := c.db.WithContext(ctx).Model(&models.Message{}).
+		Joins("JOIN conversations ON conversations.id = messages.conversation_id").
+		Where("(conversations.buyer_id = ? OR conversations.seller_id = ?) AND messages.sender_id <> ? AND messages.read_at IS NULL",
+			user.ID, user.ID, user.ID).
+		Count(&count).Error
+	return count, err
+}
+
+// get loads a conversation the user takes part in; admins can load any.
+func (c ConversationStorer) get(ctx context.Context, id int, user models.User) (models.Conversation, error) {
+	var conversation models.Conversation
+	if err := c.db.WithContext(ctx).First(&conversation, id).Error; err == gorm.ErrRecordNotFound {
+		return models.Conversation{}, ErrConversationNotFound
+	} else if err != nil {
+		return models.Conversation{}, err
+	}
+	if user.Role != consts.ROLE_ADMIN && !isParticipant(conversation, user) {
+		return models.Conversation{}, ErrConversationNotFound
+	}
+	return conversation, nil
+}
+
+func isParticipant(conversation models.Conversation, user models.User) bool {
+	return conversation.BuyerID == user.ID || conversation.SellerID == user.ID
+}
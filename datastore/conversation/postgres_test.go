@@ -0,0 +1,147 @@
+package conversation
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var (
+	seller = models.User{ID: 1, Username: "seller", Password: "seller123", Role: consts.ROLE_AIRLINE}
+	buyer  = models.User{ID: 2, Username: "buyer", Password: "buyer123", Role: consts.ROLE_AIRLINE}
+	other  = models.User{ID: 3, Username: "other", Password: "other123", Role: consts.ROLE_AIRLINE}
+	admin  = models.User{ID: 4, Username: "admin", Password: "admin123", Role: consts.ROLE_ADMIN}
+)
+
+func TestDatastore(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	cleanup := createData(t, db)
+	defer cleanup()
+
+	c := NewConversationStorer(db)
+	testConversationStorer_StartSend(t, c)
+	testConversationStorer_ReadReceipts(t, c)
+	testConversationStorer_Access(t, c)
+	testConversationStorer_Unavailable(t, c, db)
+}
+
+func testConversationStorer_StartSend(t *testing.T, c ConversationStorer) {
+	ctx := context.Background()
+
+	_, err := c.Start(ctx, 1, seller)
+	assert.ErrorIs(t, err, ErrOwnAd)
+
+	_, err = c.Start(ctx, 42, buyer)
+	assert.ErrorIs(t, err, ErrAdNotFound)
+
+	conv, err := c.Start(ctx, 1, buyer)
+	assert.NoError(t, err)
+	assert.Equal(t, seller.ID, conv.SellerID)
+
+	// contacting the seller again continues the same thread
+	again, err := c.Start(ctx, 1, buyer)
+	assert.NoError(t, err)
+	assert.Equal(t, conv.ID, again.ID)
+
+	_, err = c.Send(ctx, int(conv.ID), buyer, "Is the engine overhauled?", "")
+	assert.NoError(t, err)
+	_, err = c.Send(ctx, int(conv.ID), buyer, "Logbook photo", "https://example.com/logbook.jpg")
+	assert.NoError(t, err)
+}
+
+func testConversationStorer_ReadReceipts(t *testing.T, c ConversationStorer) {
+	ctx := context.Background()
+
+	inbox, err := c.Inbox(ctx, seller)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(inbox)) {
+		assert.Equal(t, int64(2), inbox[0].Unread)
+	}
+
+	// the sender's own messages are not unread for them
+	count, err := c.UnreadCount(ctx, buyer)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	// admins reviewing a thread don't mark it read
+	_, err = c.Messages(ctx, 1, admin)
+	assert.NoError(t, err)
+	count, err = c.UnreadCount(ctx, seller)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), count)
+
+	messages, err := c.Messages(ctx, 1, seller)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(messages))
+	for _, m := range messages {
+		assert.NotNil(t, m.ReadAt)
+	}
+
+	count, err = c.UnreadCount(ctx, seller)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+}
+
+func testConversationStorer_Access(t *testing.T, c ConversationStorer) {
+	ctx := context.Background()
+
+	_, err := c.Messages(ctx, 1, other)
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+
+	_, err = c.Send(ctx, 1, other, "hello", "")
+	assert.ErrorIs(t, err, ErrConversationNotFound)
+
+	_, err = c.Send(ctx, 1, admin, "hello", "")
+	assert.ErrorIs(t, err, ErrNotParticipant)
+
+	conversations, err := c.ListByAd(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(conversations))
+}
+
+func testConversationStorer_Unavailable(t *testing.T, c ConversationStorer, db *gorm.DB) {
+	ctx := context.Background()
+
+	for _, status := range []consts.AdStatus{consts.HIDDEN, consts.INACTIVE, consts.RESERVED, consts.SOLD} {
+		db.Model(&models.Ad{}).Where("id = ?", 1).Update("status", status)
+
+		_, err := c.Start(ctx, 1, other)
+		assert.ErrorIs(t, err, ErrAdNotAvailable, status)
+
+		// the buyer who already contacted the seller keeps the thread
+		_, err = c.Start(ctx, 1, buyer)
+		assert.NoError(t, err, status)
+	}
+	db.Model(&models.Ad{}).Where("id = ?", 1).Update("status", consts.ACTIVE)
+}
+
+func createData(t *testing.T, db *gorm.DB) func() {
+	if err := db.Create([]models.User{seller, buyer, other, admin}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Category{ID: 1, Name: "small-passenger"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	ad := models.Ad{ID: 1, UserID: seller.ID, Subject: "Cessna 172", Price: 1000, CategoryID: 1, Status: string(consts.ACTIVE)}
+	if err := db.Create(&ad).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return func() {
+		db.Exec("DELETE FROM messages")
+		db.Exec("DELETE FROM conversations")
+		db.Exec("DELETE FROM ads")
+		db.Exec("DELETE FROM categories")
+		db.Exec("DELETE FROM users")
+	}
+}
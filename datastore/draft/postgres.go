@@ -0,0 +1,111 @@
+package draft
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/models"
+	"context"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrDraftNotFound = apperror.NotFound("draft_not_found", "draft not found")
+
+type DraftStorer struct {
+	db *gorm.DB
+}
+
+func NewDraftStorer(db *gorm.DB) DraftStorer {
+	return DraftStorer{db: db}
+}
+
+func (d DraftStorer) Create(ctx context.Context, userID uint, payload map[string]interface{}) (models.AdDraft, error) {
+	encoded, err := json.Marshal(payload)
+	if err != nil {
+		return models.AdDraft{}, err
+	}
+
+	now := time.Now()
+	draft := models.AdDraft{UserID: userID, Payload: string(encoded), CreatedAt: now, UpdatedAt: now}
+	if err := d.db.WithContext(ctx).Create(&draft).Error; err != nil {
+		return models.AdDraft{}, err
+	}
+	return draft, nil
+}
+
+// Save merges the partial payload into the draft: given fields overwrite the
+// saved ones and null fields are removed.
+func (d DraftStorer) Save(ctx context.Context, id int, userID uint, payload map[string]interface{}) (models.AdDraft, error) {
+	var draft models.AdDraft
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		draft, err = get(tx, id, userID)
+		if err != nil {
+			return err
+		}
+
+		saved := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(draft.Payload), &saved); err != nil {
+			return err
+		}
+		for field, value := range payload {
+			if value == nil {
+				delete(saved, field)
+			} else {
+				saved[field] = value
+			}
+		}
+
+		encoded, err := json.Marshal(saved)
+		if err != nil {
+			return err
+		}
+		draft.Payload = string(encoded)
+		draft.UpdatedAt = time.Now()
+		return tx.Model(&models.AdDraft{}).
+			Where("id = ?", draft.ID).
+			Updates(map[string]interface{}{"payload": draft.Payload, "updated_at": draft.UpdatedAt}).Error
+	})
+	if err != nil {
+		return models.AdDraft{}, err
+	}
+	return draft, nil
+}
+
+// List lists the drafts of the user, the most recently saved first.
+func (d DraftStorer) List(ctx context.Context, userID uint) ([]models.AdDraft, error) {
+	drafts := []models.AdDraft{}
+	err := d.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("updated_at DESC, id DESC").
+		Find(&drafts).Error
+	return drafts, err
+}
+
+func (d DraftStorer) Get(ctx context.Context, id int, userID uint) (models.AdDraft, error) {
+	return get(d.db.WithContext(ctx), id, userID)
+}
+
+func (d DraftStorer) Delete(ctx context.Context, id int, userID uint) error {
+	result := d.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", id, userID).
+		Delete(&models.AdDraft{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrDraftNotFound
+	}
+	return nil
+}
+
+// get loads a draft of the user, drafts of others are not found.
+func get(db *gorm.DB, id int, userID uint) (models.AdDraft, error) {
+	var draft models.AdDraft
+	err := db.Where("id = ? AND user_id = ?", id, userID).First(&draft).Error
+	if err == gorm.ErrRecordNotFound {
+		return models.AdDraft{}, ErrDraftNotFound
+	}
+	return draft, err
+}
@@ -0,0 +1,109 @@
+package draft
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var (
+	writer = models.User{ID: 1, Username: "writer", Password: "writer123", Role: consts.ROLE_AIRLINE}
+	other  = models.User{ID: 2, Username: "other", Password: "other123", Role: consts.ROLE_AIRLINE}
+)
+
+func TestDatastore(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	cleanup := createData(t, db)
+	defer cleanup()
+
+	d := NewDraftStorer(db)
+	testDraftStorer_CreateSave(t, d)
+	testDraftStorer_Access(t, d)
+	testDraftStorer_Delete(t, d)
+}
+
+func testDraftStorer_CreateSave(t *testing.T, d DraftStorer) {
+	ctx := context.Background()
+
+	created, err := d.Create(ctx, writer.ID, map[string]interface{}{"Subject": "Cessna 172", "Price": 1000})
+	assert.NoError(t, err)
+
+	// autosave only sends what changed
+	saved, err := d.Save(ctx, int(created.ID), writer.ID, map[string]interface{}{"Price": 1200, "FlyTime": 300})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Subject": "Cessna 172", "Price": float64(1200), "FlyTime": float64(300)}, payload(t, saved))
+
+	saved, err = d.Save(ctx, int(created.ID), writer.ID, map[string]interface{}{"FlyTime": nil})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"Subject": "Cessna 172", "Price": float64(1200)}, payload(t, saved))
+
+	loaded, err := d.Get(ctx, int(created.ID), writer.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, saved.Payload, loaded.Payload)
+}
+
+func testDraftStorer_Access(t *testing.T, d DraftStorer) {
+	ctx := context.Background()
+
+	_, err := d.Get(ctx, 1, other.ID)
+	assert.ErrorIs(t, err, ErrDraftNotFound)
+
+	_, err = d.Save(ctx, 1, other.ID, map[string]interface{}{"Price": 1})
+	assert.ErrorIs(t, err, ErrDraftNotFound)
+
+	err = d.Delete(ctx, 1, other.ID)
+	assert.ErrorIs(t, err, ErrDraftNotFound)
+
+	drafts, err := d.List(ctx, other.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, drafts)
+}
+
+func testDraftStorer_Delete(t *testing.T, d DraftStorer) {
+	ctx := context.Background()
+
+	_, err := d.Create(ctx, writer.ID, map[string]interface{}{})
+	assert.NoError(t, err)
+
+	drafts, err := d.List(ctx, writer.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(drafts))
+
+	assert.NoError(t, d.Delete(ctx, 1, writer.ID))
+
+	drafts, err = d.List(ctx, writer.ID)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(drafts)) {
+		assert.Equal(t, uint(2), drafts[0].ID)
+	}
+}
+
+func payload(t *testing.T, draft models.AdDraft) map[string]interface{} {
+	decoded := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(draft.Payload), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	return decoded
+}
+
+func createData(t *testing.T, db *gorm.DB) func() {
+	if err := db.Create([]models.User{writer, other}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return func() {
+		db.Exec("DELETE FROM ad_drafts")
+		db.Exec("DELETE FROM users")
+	}
+}
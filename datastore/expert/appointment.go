@@ -0,0 +1,402 @@
+package expert
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrSlotNotFound        = apperror.NotFound("slot_not_found", "slot does not exist")
+	ErrSlotOverlap         = apperror.Conflict("slot_overlap", "the slot overlaps another slot of yours")
+	ErrSlotTaken           = apperror.Conflict("slot_taken", "the slot is already booked")
+	ErrSlotStarted         = apperror.Conflict("slot_started", "the slot has already started")
+	ErrSlotBooked          = apperror.Conflict("slot_booked", "booked slots can't be deleted, cancel the appointment first")
+	ErrAppointmentNotFound = apperror.NotFound("appointment_not_found", "the request has no scheduled appointment")
+	ErrAlreadyScheduled    = apperror.Conflict("already_scheduled", "the request already has an appointment, reschedule it instead")
+	ErrNotSchedulable      = apperror.Conflict("not_schedulable", "only paid expert requests that have an expert and are not done can be scheduled")
+	ErrTooLate             = apperror.Conflict("too_late", "the appointment is too close to change")
+)
+
+type AppointmentStorer struct {
+	db *gorm.DB
+}
+
+func NewAppointmentStorer(db *gorm.DB) AppointmentStorer {
+	return AppointmentStorer{db: db}
+}
+
+// CreateSlot publishes a slot of the expert, the slots of an expert don't
+// overlap.
+func (a AppointmentStorer) CreateSlot(ctx context.Context, slot models.ExpertSlot) (models.ExpertSlot, error) {
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// there is no constraint against overlaps, so the slots of an expert
+		// are added one at a time by locking the expert
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&models.User{}, slot.ExpertID).Error
+		if err != nil {
+			return err
+		}
+
+		var count int64
+		err = tx.Model(&models.ExpertSlot{}).
+			Where("expert_id = ? AND starts_at < ? AND ends_at > ?", slot.ExpertID, slot.EndsAt, slot.StartsAt).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrSlotOverlap
+		}
+		return tx.Create(&slot).Error
+	})
+	if err != nil {
+		return models.ExpertSlot{}, err
+	}
+	return slot, nil
+}
+
+// Slots lists the slots of the expert that didn't end before from, booked or
+// not.
+func (a AppointmentStorer) Slots(ctx context.Context, expertID uint, from time.Time) ([]models.ExpertSlot, error) {
+	slots := []models.ExpertSlot{}
+	err := a.db.WithContext(ctx).
+		Select("expert_slots.*, EXISTS (?) AS booked", scheduledIn(a.db)).
+		Where("expert_id = ? AND ends_at > ?", expertID, from).
+		Order("starts_at, id").
+		Find(&slots).Error
+	return slots, err
+}
+
+// DeleteSlot removes a slot of the expert that isn't booked.
+func (a AppointmentStorer) DeleteSlot(ctx context.Context, slotID int, expertID uint) (models.ExpertSlot, error) {
+	var slot models.ExpertSlot
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("id = ? AND expert_id = ?", slotID, expertID).First(&slot).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSlotNotFound
+		} else if err != nil {
+			return err
+		}
+
+		var count int64
+		err = tx.Model(&models.Appointment{}).
+			Where("slot_id = ? AND status = ?", slot.ID, consts.APPOINTMENT_SCHEDULED).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrSlotBooked
+		}
+
+		// cancelled appointments keep their time and place without the slot
+		err = tx.Model(&models.Appointment{}).Where("slot_id = ?", slot.ID).Update("slot_id", nil).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&slot).Error
+	})
+	if err != nil {
+		return models.ExpertSlot{}, err
+	}
+	return slot, nil
+}
+
+// FreeSlots lists the slots the airline can book for its request, the ones
+// of its expert that didn't start and aren't booked.
+func (a AppointmentStorer) FreeSlots(ctx context.Context, requestID int, user models.User, now time.Time) ([]models.ExpertSlot, error) {
+	db := a.db.WithContext(ctx)
+	expertAd, err := schedulable(db, requestID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	slots := []models.ExpertSlot{}
+	err = db.
+		Where("expert_id = ? AND starts_at > ?", expertAd.ExpertID, now).
+		Where("NOT EXISTS (?)", scheduledIn(a.db)).
+		Order("starts_at, id").
+		Find(&slots).Error
+	return slots, err
+}
+
+// Book schedules the inspection of the request of the airline in a free slot
+// of its expert.
+func (a AppointmentStorer) Book(ctx context.Context, requestID int, slotID uint, user models.User, now time.Time) (models.Appointment, error) {
+	var appointment models.Appointment
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expertAd, err := schedulable(tx, requestID, user.ID)
+		if err != nil {
+			return err
+		}
+		_, err = scheduled(tx, expertAd.ID)
+		if err == nil {
+			return ErrAlreadyScheduled
+		} else if !errors.Is(err, ErrAppointmentNotFound) {
+			return err
+		}
+
+		slot, err := freeSlot(tx, slotID, expertAd.ExpertID, now)
+		if err != nil {
+			return err
+		}
+
+		appointment = models.Appointment{
+			ExpertAdsID: expertAd.ID,
+			SlotID:      &slot.ID,
+			AdsID:       expertAd.AdsID,
+			ExpertID:    expertAd.ExpertID,
+			UserID:      expertAd.UserID,
+			StartsAt:    slot.StartsAt,
+			EndsAt:      slot.EndsAt,
+			Location:    slot.Location,
+			Status:      consts.APPOINTMENT_SCHEDULED,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		return tx.Create(&appointment).Error
+	})
+	if duplicate(a.db, err) {
+		// another booking of the request or of the slot won the race
+		if _, err := scheduled(a.db.WithContext(ctx), uint(requestID)); err == nil {
+			return models.Appointment{}, ErrAlreadyScheduled
+		}
+		return models.Appointment{}, ErrSlotTaken
+	} else if err != nil {
+		return models.Appointment{}, err
+	}
+	return a.get(ctx, appointment.ID)
+}
+
+// Reschedule moves the appointment of the request of the airline to another
+// free slot of its expert. Appointments starting within the configured
+// notice can't move anymore.
+func (a AppointmentStorer) Reschedule(ctx context.Context, requestID int, slotID uint, user models.User, now time.Time) (models.Appointment, error) {
+	var appointment models.Appointment
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		expertAd, err := schedulable(tx, requestID, user.ID)
+		if err != nil {
+			return err
+		}
+		appointment, err = scheduled(tx, expertAd.ID)
+		if err != nil {
+			return err
+		}
+		if appointment.SlotID != nil && *appointment.SlotID == slotID {
+			return nil
+		}
+		if err := inNotice(tx, appointment, now); err != nil {
+			return err
+		}
+
+		slot, err := freeSlot(tx, slotID, expertAd.ExpertID, now)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&appointment).Updates(map[string]interface{}{
+			"slot_id":    slot.ID,
+			"starts_at":  slot.StartsAt,
+			"ends_at":    slot.EndsAt,
+			"location":   slot.Location,
+			"sequence":   gorm.Expr("sequence + 1"),
+			"updated_at": now,
+		}).Error
+	})
+	if duplicate(a.db, err) {
+		return models.Appointment{}, ErrSlotTaken
+	} else if err != nil {
+		return models.Appointment{}, err
+	}
+	return a.get(ctx, appointment.ID)
+}
+
+// Cancel cancels the appointment of the request, the airline and the expert
+// only until the configured notice before it starts, admins at any time.
+func (a AppointmentStorer) Cancel(ctx context.Context, requestID int, user models.User, now time.Time) (models.Appointment, error) {
+	var appointment models.Appointment
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		appointment, err = scheduled(tx, uint(requestID))
+		if err != nil {
+			return err
+		}
+		if !attends(appointment, user) {
+			return ErrAppointmentNotFound
+		}
+		if user.Role != consts.ROLE_ADMIN {
+			if err := inNotice(tx, appointment, now); err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&appointment).Updates(map[string]interface{}{
+			"status":       consts.APPOINTMENT_CANCELLED,
+			"cancelled_by": user.ID,
+			"cancelled_at": now,
+			"sequence":     gorm.Expr("sequence + 1"),
+			"updated_at":   now,
+		}).Error
+	})
+	if err != nil {
+		return models.Appointment{}, err
+	}
+	return a.get(ctx, appointment.ID)
+}
+
+// Appointment is the scheduled appointment of the request, for the airline,
+// the expert and admins.
+func (a AppointmentStorer) Appointment(ctx context.Context, requestID int, user models.User) (models.Appointment, error) {
+	appointment, err := scheduled(a.db.WithContext(ctx), uint(requestID))
+	if err != nil {
+		return models.Appointment{}, err
+	}
+	if !attends(appointment, user) {
+		return models.Appointment{}, ErrAppointmentNotFound
+	}
+	return a.get(ctx, appointment.ID)
+}
+
+// Appointments lists the appointments the user attends as the expert or the
+// airline that didn't end before from, cancelled ones only withCancelled.
+func (a AppointmentStorer) Appointments(ctx context.Context, userID uint, from time.Time, withCancelled bool) ([]models.Appointment, error) {
+	appointments := []models.Appointment{}
+	query := a.db.WithContext(ctx).
+		Preload("Ads").
+		Preload("Expert").
+		Preload("User").
+		Where("(expert_id = ? OR user_id = ?) AND ends_at > ?", userID, userID, from)
+	if !withCancelled {
+		query = query.Where("status = ?", consts.APPOINTMENT_SCHEDULED)
+	}
+	err := query.Order("starts_at, id").Find(&appointments).Error
+	return appointments, err
+}
+
+func (a AppointmentStorer) get(ctx context.Context, id uint) (models.Appointment, error) {
+	var appointment models.Appointment
+	err := a.db.WithContext(ctx).
+		Preload("Ads").
+		Preload("Expert").
+		Preload("User").
+		First(&appointment, id).Error
+	return appointment, err
+}
+
+// cancelStale cancels the scheduled appointment of a request that moved to
+// another expert or lost its expert, the slot was in the calendar of the
+// previous one.
+func cancelStale(tx *gorm.DB, requestID uint, expertID uint, now time.Time) error {
+	return tx.Model(&models.Appointment{}).
+		Where("expert_ads_id = ? AND status = ? AND expert_id != ?", requestID, consts.APPOINTMENT_SCHEDULED, expertID).
+		Updates(map[string]interface{}{
+			"status":       consts.APPOINTMENT_CANCELLED,
+			"cancelled_at": now,
+			"sequence":     gorm.Expr("sequence + 1"),
+			"updated_at":   now,
+		}).Error
+}
+
+// schedulable loads a request of the airline that can have an appointment.
+func schedulable(tx *gorm.DB, requestID int, userID uint) (models.ExpertAds, error) {
+	var expertAd models.ExpertAds
+	err := tx.Where("id = ? AND user_id = ?", requestID, userID).First(&expertAd).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return expertAd, ErrRequestNotFound
+	} else if err != nil {
+		return expertAd, err
+	}
+	switch {
+	case expertAd.Status == consts.WAIT_FOR_PAYMENT_STATUS,
+		expertAd.Status == consts.DONE_STATUS,
+		expertAd.ExpertID == 0:
+		return expertAd, ErrNotSchedulable
+	}
+	return expertAd, nil
+}
+
+func scheduled(tx *gorm.DB, requestID uint) (models.Appointment, error) {
+	var appointment models.Appointment
+	err := tx.Where("expert_ads_id = ? AND status = ?", requestID, consts.APPOINTMENT_SCHEDULED).First(&appointment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return appointment, ErrAppointmentNotFound
+	}
+	return appointment, err
+}
+
+// freeSlot loads a slot of the expert that didn't start and isn't booked.
+// The slot stays locked until tx ends, so it is booked once.
+func freeSlot(tx *gorm.DB, slotID uint, expertID uint, now time.Time) (models.ExpertSlot, error) {
+	var slot models.ExpertSlot
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND expert_id = ?", slotID, expertID).
+		First(&slot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return slot, ErrSlotNotFound
+	} else if err != nil {
+		return slot, err
+	}
+	if !slot.StartsAt.After(now) {
+		return slot, ErrSlotStarted
+	}
+
+	var count int64
+	err = tx.Model(&models.Appointment{}).
+		Where("slot_id = ? AND status = ?", slot.ID, consts.APPOINTMENT_SCHEDULED).
+		Count(&count).Error
+	if err != nil {
+		return slot, err
+	}
+	if count > 0 {
+		return slot, ErrSlotTaken
+	}
+	return slot, nil
+}
+
+// duplicate tells if err is the violation of a unique index, the partial
+// ones keep a request and a slot to one scheduled appointment.
+func duplicate(db *gorm.DB, err error) bool {
+	if err == nil {
+		return false
+	}
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+// inNotice returns ErrTooLate when the appointment starts within the
+// configured notice.
+func inNotice(tx *gorm.DB, appointment models.Appointment, now time.Time) error {
+	hours := float64(consts.DEFAULT_APPOINTMENT_NOTICE_HOURS)
+	var config models.Configuration
+	err := tx.Session(&gorm.Session{NewDB: true}).Where("name = ?", consts.CONFIG_APPOINTMENT_NOTICE).First(&config).Error
+	if err == nil {
+		hours = config.Value
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if now.Add(time.Duration(hours * float64(time.Hour))).After(appointment.StartsAt) {
+		return ErrTooLate
+	}
+	return nil
+}
+
+func attends(appointment models.Appointment, user models.User) bool {
+	return user.Role == consts.ROLE_ADMIN || appointment.UserID == user.ID || appointment.ExpertID == user.ID
+}
+
+// scheduledIn is the subquery of the scheduled appointments of the slot of
+// the outer query.
+func scheduledIn(db *gorm.DB) *gorm.DB {
+	return db.Session(&gorm.Session{NewDB: true}).
+		Model(&models.Appointment{}).
+		Select("1").
+		Where("appointments.slot_id = expert_slots.id AND appointments.status = ?", consts.APPOINTMENT_SCHEDULED)
+}
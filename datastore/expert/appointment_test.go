@@ -0,0 +1,220 @@
+package expert
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAppointmentStorer(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	createAssignmentData(t, db)
+	// request 1 is in progress with expert 2, request 2 has no expert yet
+	assert.NoError(t, db.Model(&models.ExpertAds{}).Where("id = ?", 1).
+		Updates(map[string]interface{}{"status": consts.IN_PROGRESS_STATUS, "expert_id": 2}).Error)
+
+	ctx := context.Background()
+	a := NewAppointmentStorer(db)
+	airline := models.User{ID: 1, Role: consts.ROLE_AIRLINE}
+	expert := models.User{ID: 2, Role: consts.ROLE_EXPERT}
+	other := models.User{ID: 3, Role: consts.ROLE_EXPERT}
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	day := func(days int, hour int) time.Time {
+		return now.AddDate(0, 0, days).Add(time.Duration(hour-9) * time.Hour)
+	}
+
+	first, err := a.CreateSlot(ctx, models.ExpertSlot{ExpertID: 2, StartsAt: day(3, 10), EndsAt: day(3, 12), Location: "IKA hangar 4"})
+	assert.NoError(t, err)
+	second, err := a.CreateSlot(ctx, models.ExpertSlot{ExpertID: 2, StartsAt: day(5, 10), EndsAt: day(5, 12), Location: "THR apron"})
+	assert.NoError(t, err)
+	_, err = a.CreateSlot(ctx, models.ExpertSlot{ExpertID: 2, StartsAt: day(3, 11), EndsAt: day(3, 13), Location: "IKA"})
+	assert.ErrorIs(t, err, ErrSlotOverlap)
+	otherSlot, err := a.CreateSlot(ctx, models.ExpertSlot{ExpertID: 3, StartsAt: day(3, 11), EndsAt: day(3, 13), Location: "MHD"})
+	assert.NoError(t, err)
+
+	_, err = a.Book(ctx, 2, first.ID, airline, now)
+	assert.ErrorIs(t, err, ErrNotSchedulable)
+	_, err = a.Book(ctx, 1, otherSlot.ID, airline, now)
+	assert.ErrorIs(t, err, ErrSlotNotFound)
+	_, err = a.Book(ctx, 1, first.ID, models.User{ID: 3, Role: consts.ROLE_AIRLINE}, now)
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+
+	appointment, err := a.Book(ctx, 1, first.ID, airline, now)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.APPOINTMENT_SCHEDULED, appointment.Status)
+	assert.Equal(t, "IKA hangar 4", appointment.Location)
+	assert.Equal(t, "jet", appointment.Ads.Subject)
+	assert.Equal(t, "expert2", appointment.Expert.Username)
+	_, err = a.Book(ctx, 1, second.ID, airline, now)
+	assert.ErrorIs(t, err, ErrAlreadyScheduled)
+
+	free, err := a.FreeSlots(ctx, 1, airline, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(free))
+	assert.Equal(t, second.ID, free[0].ID)
+	slots, err := a.Slots(ctx, 2, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(slots))
+	assert.True(t, slots[0].Booked)
+	assert.False(t, slots[1].Booked)
+	_, err = a.DeleteSlot(ctx, int(first.ID), 2)
+	assert.ErrorIs(t, err, ErrSlotBooked)
+
+	// moving the appointment frees its slot and bumps the sequence
+	moved, err := a.Reschedule(ctx, 1, second.ID, airline, now)
+	assert.NoError(t, err)
+	assert.Equal(t, second.ID, *moved.SlotID)
+	assert.Equal(t, day(5, 10), moved.StartsAt.UTC())
+	assert.Equal(t, 1, moved.Sequence)
+	_, err = a.Reschedule(ctx, 1, first.ID, airline, day(5, 0))
+	assert.ErrorIs(t, err, ErrTooLate)
+
+	_, err = a.Appointment(ctx, 1, other)
+	assert.ErrorIs(t, err, ErrAppointmentNotFound)
+	_, err = a.Cancel(ctx, 1, expert, day(5, 0))
+	assert.ErrorIs(t, err, ErrTooLate)
+	cancelled, err := a.Cancel(ctx, 1, models.User{ID: 4, Role: consts.ROLE_ADMIN}, day(5, 0))
+	assert.NoError(t, err)
+	assert.Equal(t, consts.APPOINTMENT_CANCELLED, cancelled.Status)
+	assert.Equal(t, 2, cancelled.Sequence)
+
+	// deleting a slot keeps the cancelled appointments that were in it
+	_, err = a.DeleteSlot(ctx, int(second.ID), 2)
+	assert.NoError(t, err)
+	appointments, err := a.Appointments(ctx, 2, now, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(appointments))
+	assert.Nil(t, appointments[0].SlotID)
+	appointments, err = a.Appointments(ctx, 1, now, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(appointments))
+
+	// the appointment goes when the request moves to another expert
+	_, err = a.Book(ctx, 1, first.ID, airline, now)
+	assert.NoError(t, err)
+	_, err = NewAssignmentStorer(db).Assign(ctx, 1, 3, true, now)
+	assert.NoError(t, err)
+	_, err = a.Appointment(ctx, 1, airline)
+	assert.ErrorIs(t, err, ErrAppointmentNotFound)
+}
+
+func TestAppointmentStorer_Races(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	createAssignmentData(t, db)
+	assert.NoError(t, db.Model(&models.ExpertAds{}).Where("id IN ?", []int{1, 2}).
+		Updates(map[string]interface{}{"status": consts.IN_PROGRESS_STATUS, "expert_id": 2}).Error)
+
+	ctx := context.Background()
+	a := NewAppointmentStorer(db)
+	airline := models.User{ID: 1, Role: consts.ROLE_AIRLINE}
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	slot := func(days int) models.ExpertSlot {
+		return models.ExpertSlot{ExpertID: 2, StartsAt: now.AddDate(0, 0, days), EndsAt: now.AddDate(0, 0, days).Add(time.Hour), Location: "IKA"}
+	}
+
+	// the same slot added at once is only added once
+	succeeded, failed := hammer(make([]models.User, 4), func(models.User) error {
+		_, err := a.CreateSlot(ctx, slot(1))
+		return err
+	})
+	assert.Equal(t, 1, len(succeeded))
+	for _, err := range failed {
+		assert.ErrorIs(t, err, ErrSlotOverlap)
+	}
+
+	// two requests booking the same slot at once
+	var booked models.Appointment
+	var first models.ExpertSlot
+	assert.NoError(t, db.Where("expert_id = ?", 2).First(&first).Error)
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			appointment, err := a.Book(ctx, i+1, first.ID, airline, now)
+			if err == nil {
+				booked = appointment
+			}
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+	loser := 2
+	if errs[0] != nil {
+		loser = 1
+		assert.ErrorIs(t, errs[0], ErrSlotTaken)
+	} else {
+		assert.ErrorIs(t, errs[1], ErrSlotTaken)
+	}
+	assert.Equal(t, first.ID, *booked.SlotID)
+
+	// a booking that commits between the checks and the insert of another
+	// one trips the unique index of the slot
+	var race *models.Appointment
+	racer := func(tx *gorm.DB) {
+		if race != nil && tx.Statement.Table == "appointments" {
+			row := *race
+			race = nil
+			assert.NoError(t, tx.Session(&gorm.Session{NewDB: true}).Create(&row).Error)
+		}
+	}
+	assert.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:race", racer))
+	defer db.Callback().Create().Remove("test:race")
+
+	second, err := a.CreateSlot(ctx, slot(2))
+	assert.NoError(t, err)
+	race = &models.Appointment{
+		ExpertAdsID: 3, SlotID: &second.ID, AdsID: 2, ExpertID: 2, UserID: 1,
+		StartsAt: second.StartsAt, EndsAt: second.EndsAt, Location: second.Location,
+		Status: consts.APPOINTMENT_SCHEDULED, CreatedAt: now, UpdatedAt: now,
+	}
+	_, err = a.Book(ctx, loser, second.ID, airline, now)
+	assert.ErrorIs(t, err, ErrSlotTaken)
+}
+
+func TestCalendarStorer(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	createAssignmentData(t, db)
+
+	ctx := context.Background()
+	c := NewCalendarStorer(db)
+
+	assert.NoError(t, c.Rotate(ctx, 2, "first"))
+	user, err := c.Resolve(ctx, "first")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), user.ID)
+
+	assert.NoError(t, c.Rotate(ctx, 2, "second"))
+	_, err = c.Resolve(ctx, "first")
+	assert.ErrorIs(t, err, ErrFeedNotFound)
+	_, err = c.Resolve(ctx, "second")
+	assert.NoError(t, err)
+
+	assert.NoError(t, c.Revoke(ctx, 2))
+	_, err = c.Resolve(ctx, "second")
+	assert.ErrorIs(t, err, ErrFeedNotFound)
+	assert.ErrorIs(t, c.Revoke(ctx, 2), ErrFeedNotFound)
+}
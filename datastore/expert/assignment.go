@@ -0,0 +1,189 @@
+package expert
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore/sla"
+	"Airplane-Divar/models"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrExpertNotFound   = apperror.NotFound("expert_not_found", "expert does not exist")
+	ErrCategoryNotFound = apperror.NotFound("category_not_found", "category does not exist")
+	ErrAlreadyAssigned  = apperror.Conflict("already_assigned", "the expert request is already assigned")
+	ErrNotAssignable    = apperror.Conflict("not_assignable", "only paid expert requests that are not done can be assigned")
+)
+
+// activeStatuses are the statuses of the requests an expert still works on.
+var activeStatuses = []consts.Status{consts.EXPERT_PENDING_STATUS, consts.IN_PROGRESS_STATUS}
+
+type AssignmentStorer struct {
+	db *gorm.DB
+}
+
+func NewAssignmentStorer(db *gorm.DB) AssignmentStorer {
+	return AssignmentStorer{db: db}
+}
+
+// Strategy is the configured assignment strategy, one of consts.ASSIGN_*.
+func (a AssignmentStorer) Strategy(ctx context.Context) (int, error) {
+	var config models.Configuration
+	err := a.db.WithContext(ctx).
+		Where("name = ?", consts.CONFIG_EXPERT_ASSIGNMENT).
+		First(&config).Error
+	if err == gorm.ErrRecordNotFound {
+		return consts.DEFAULT_EXPERT_ASSIGNMENT, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return int(config.Value), nil
+}
+
+// Profile is the profile of the expert, experts who never saved one get the
+// default profile.
+func (a AssignmentStorer) Profile(ctx context.Context, expertID uint) (models.ExpertProfile, error) {
+	profile := models.ExpertProfile{UserID: expertID, Available: true}
+	err := a.db.WithContext(ctx).Preload("Specialties").First(&profile, expertID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.ExpertProfile{}, err
+	}
+	if profile.Specialties == nil {
+		profile.Specialties = []models.ExpertSpecialty{}
+	}
+	return profile, nil
+}
+
+// SaveProfile sets the availability of the expert and replaces their
+// specialties.
+func (a AssignmentStorer) SaveProfile(ctx context.Context, expertID uint, available bool, categoryIDs []uint) (models.ExpertProfile, error) {
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(categoryIDs) > 0 {
+			var count int64
+			if err := tx.Model(&models.Category{}).Where("id IN ?", categoryIDs).Count(&count).Error; err != nil {
+				return err
+			}
+			if count != int64(len(categoryIDs)) {
+				return ErrCategoryNotFound
+			}
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"available"}),
+		}).Create(&models.ExpertProfile{UserID: expertID, Available: available}).Error
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Where("expert_id = ?", expertID).Delete(&models.ExpertSpecialty{}).Error; err != nil {
+			return err
+		}
+		for _, categoryID := range categoryIDs {
+			err := tx.Create(&models.ExpertSpecialty{ExpertID: expertID, CategoryID: categoryID}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.ExpertProfile{}, err
+	}
+	return a.Profile(ctx, expertID)
+}
+
+// Candidates are the active and available experts who can check the ad of
+// the request, by ID.
+func (a AssignmentStorer) Candidates(ctx context.Context, requestID int) ([]models.ExpertCandidate, error) {
+	db := a.db.WithContext(ctx)
+
+	var expertAd models.ExpertAds
+	err := db.Preload("Ads").First(&expertAd, requestID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRequestNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	categoryID := expertAd.Ads.CategoryID
+
+	candidates := []models.ExpertCandidate{}
+	err = db.Table("users").
+		Select(`users.id AS expert_id, expert_profiles.last_assigned_at,
+			(SELECT COUNT(*) FROM expert_ads WHERE expert_ads.expert_id = users.id AND expert_ads.status IN ?) AS active_requests,
+			EXISTS (SELECT 1 FROM expert_specialties WHERE expert_specialties.expert_id = users.id AND expert_specialties.category_id = ?) AS specialist,
+			(SELECT COALESCE(AVG(rating), 0) FROM expert_reviews WHERE expert_reviews.expert_id = users.id AND expert_reviews.hidden = ?) AS rating,
+			(SELECT COUNT(*) FROM expert_reviews WHERE expert_reviews.expert_id = users.id AND expert_reviews.hidden = ?) AS reviews`,
+			activeStatuses, categoryID, false, false).
+		Joins("LEFT JOIN expert_profiles ON expert_profiles.user_id = users.id").
+		Where("users.role = ? AND users.is_active = ?", consts.ROLE_EXPERT, true).
+		Where("expert_profiles.user_id IS NULL OR expert_profiles.available = ?", true).
+		Where(`NOT EXISTS (SELECT 1 FROM expert_specialties WHERE expert_specialties.expert_id = users.id)
+			OR EXISTS (SELECT 1 FROM expert_specialties WHERE expert_specialties.expert_id = users.id AND expert_specialties.category_id = ?)`,
+			categoryID).
+		Order("users.id").
+		Scan(&candidates).Error
+	return candidates, err
+}
+
+// Assign gives the request to the expert. Without reassign only unassigned
+// requests waiting for an expert are taken, with it requests in progress can
+// move to another expert too.
+func (a AssignmentStorer) Assign(ctx context.Context, requestID int, expertID uint, reassign bool, now time.Time) (models.ExpertAds, error) {
+	var expertAd models.ExpertAds
+	err := a.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var expert models.User
+		err := tx.Where("id = ? AND role = ? AND is_active = ?", expertID, consts.ROLE_EXPERT, true).First(&expert).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrExpertNotFound
+		} else if err != nil {
+			return err
+		}
+
+		err = tx.First(&expertAd, requestID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRequestNotFound
+		} else if err != nil {
+			return err
+		}
+
+		query := tx.Model(&models.ExpertAds{}).Where("id = ?", requestID)
+		if reassign {
+			query = query.Where("status IN ?", activeStatuses)
+		} else {
+			query = query.Where("status = ? AND expert_id IS NULL", consts.EXPERT_PENDING_STATUS)
+		}
+		result := query.Update("expert_id", expertID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if !reassign && expertAd.Status == consts.EXPERT_PENDING_STATUS {
+				return ErrAlreadyAssigned
+			}
+			return ErrNotAssignable
+		}
+		expertAd.ExpertID = expertID
+		if err := sla.Assign(tx, consts.SLA_EXPERT, expertAd.ID, expertID); err != nil {
+			return err
+		}
+		if err := cancelStale(tx, expertAd.ID, expertID, now); err != nil {
+			return err
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"last_assigned_at"}),
+		}).Create(&models.ExpertProfile{UserID: expertID, Available: true, LastAssignedAt: &now}).Error
+	})
+	if err != nil {
+		return models.ExpertAds{}, err
+	}
+	return expertAd, nil
+}
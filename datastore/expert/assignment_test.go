@@ -0,0 +1,118 @@
+package expert
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAssignmentStorer(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	createAssignmentData(t, db)
+
+	ctx := context.Background()
+	now := time.Date(2023, 8, 1, 12, 0, 0, 0, time.UTC)
+	a := NewAssignmentStorer(db)
+
+	strategy, err := a.Strategy(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.DEFAULT_EXPERT_ASSIGNMENT, strategy)
+
+	// experts 2 and 3 have no profile, they take every category
+	candidates, err := a.Candidates(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{2, 3, 4}, candidateIDs(candidates))
+
+	// expert 3 only checks helicopters and expert 4 is away
+	_, err = a.SaveProfile(ctx, 3, true, []uint{2})
+	assert.NoError(t, err)
+	profile, err := a.SaveProfile(ctx, 4, false, nil)
+	assert.NoError(t, err)
+	assert.False(t, profile.Available)
+	_, err = a.SaveProfile(ctx, 3, true, []uint{9})
+	assert.ErrorIs(t, err, ErrCategoryNotFound)
+
+	candidates, err = a.Candidates(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{2}, candidateIDs(candidates))
+	candidates, err = a.Candidates(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{2, 3}, candidateIDs(candidates))
+	assert.True(t, candidates[1].Specialist)
+	assert.False(t, candidates[0].Specialist)
+
+	expertAd, err := a.Assign(ctx, 1, 2, false, now)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), expertAd.ExpertID)
+	_, err = a.Assign(ctx, 1, 3, false, now)
+	assert.ErrorIs(t, err, ErrAlreadyAssigned)
+	_, err = a.Assign(ctx, 3, 2, false, now)
+	assert.ErrorIs(t, err, ErrNotAssignable)
+	_, err = a.Assign(ctx, 1, 1, true, now)
+	assert.ErrorIs(t, err, ErrExpertNotFound)
+
+	candidates, err = a.Candidates(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, candidates[0].ActiveRequests)
+	assert.True(t, candidates[0].LastAssignedAt.Equal(now))
+
+	// admins move requests between experts
+	expertAd, err = a.Assign(ctx, 1, 3, true, now.Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), expertAd.ExpertID)
+
+	profile, err = a.Profile(ctx, 3)
+	assert.NoError(t, err)
+	assert.True(t, profile.Available)
+	assert.Equal(t, 1, len(profile.Specialties))
+	assert.True(t, profile.LastAssignedAt.Equal(now.Add(time.Minute)))
+}
+
+func candidateIDs(candidates []models.ExpertCandidate) []uint {
+	ids := []uint{}
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.ExpertID)
+	}
+	return ids
+}
+
+func createAssignmentData(t *testing.T, db *gorm.DB) {
+	users := []models.User{
+		{ID: 1, Username: "airline", Password: "-", Token: "-", Role: consts.ROLE_AIRLINE, IsActive: true},
+		{ID: 2, Username: "expert2", Password: "-", Token: "-", Role: consts.ROLE_EXPERT, IsActive: true},
+		{ID: 3, Username: "expert3", Password: "-", Token: "-", Role: consts.ROLE_EXPERT, IsActive: true},
+		{ID: 4, Username: "expert4", Password: "-", Token: "-", Role: consts.ROLE_EXPERT, IsActive: true},
+	}
+	categories := []models.Category{{ID: 1, Name: "Jet"}, {ID: 2, Name: "Helicopter"}}
+	ads := []models.Ad{
+		{ID: 1, UserID: 1, Subject: "jet", Price: 100, CategoryID: 1, Status: string(consts.ACTIVE)},
+		{ID: 2, UserID: 1, Subject: "helicopter", Price: 100, CategoryID: 2, Status: string(consts.ACTIVE)},
+	}
+	for _, rows := range []interface{}{&users, &categories, &ads} {
+		if err := db.Create(rows).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	requests := []map[string]interface{}{
+		{"ID": 1, "AdsID": 1, "UserID": 1, "Status": consts.EXPERT_PENDING_STATUS},
+		{"ID": 2, "AdsID": 2, "UserID": 1, "Status": consts.EXPERT_PENDING_STATUS},
+		{"ID": 3, "AdsID": 2, "UserID": 1, "Status": consts.WAIT_FOR_PAYMENT_STATUS},
+	}
+	for _, request := range requests {
+		if err := db.Model(&models.ExpertAds{}).Create(request).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
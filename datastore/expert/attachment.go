@@ -0,0 +1,138 @@
+package expert
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAttachmentNotFound = apperror.NotFound("attachment_not_found", "attachment does not exist")
+	ErrItemNotFound       = apperror.NotFound("checklist_item_not_found", "the checklist of the request has no such item")
+	ErrNoAttachmentAccess = apperror.Forbidden(apperror.CodeForbidden, "You don't have access to expert requests.")
+)
+
+type AttachmentStorer struct {
+	db *gorm.DB
+}
+
+func NewAttachmentStorer(db *gorm.DB) AttachmentStorer {
+	return AttachmentStorer{db: db}
+}
+
+// Add records an attachment uploaded by the expert working on the request.
+// Attachments linked to a checklist item must use an item of the template of
+// the request.
+func (s AttachmentStorer) Add(ctx context.Context, attachment models.ExpertAttachment) (models.ExpertAttachment, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := workingOn(tx, attachment.ExpertAdsID, attachment.UploaderID); err != nil {
+			return err
+		}
+
+		if attachment.TemplateItemID != nil {
+			template, err := NewInspectionStorer(tx).TemplateFor(ctx, int(attachment.ExpertAdsID))
+			if errors.Is(err, ErrTemplateNotFound) {
+				return ErrItemNotFound
+			} else if err != nil {
+				return err
+			}
+			found := false
+			for _, item := range template.Items {
+				found = found || item.ID == *attachment.TemplateItemID
+			}
+			if !found {
+				return ErrItemNotFound
+			}
+		}
+
+		return tx.Create(&attachment).Error
+	})
+	if err != nil {
+		return models.ExpertAttachment{}, err
+	}
+	return attachment, nil
+}
+
+// List returns the attachments of a request the user can see, with the same
+// rules as ExpertStorer.Get. Repair staff never see them.
+func (s AttachmentStorer) List(ctx context.Context, requestID int, user models.User) ([]models.ExpertAttachment, error) {
+	if err := s.canSee(ctx, requestID, user); err != nil {
+		return nil, err
+	}
+
+	attachments := []models.ExpertAttachment{}
+	err := s.db.WithContext(ctx).
+		Where("expert_ads_id = ?", requestID).
+		Order("id").
+		Find(&attachments).Error
+	return attachments, err
+}
+
+// Get returns an attachment of a request the user can see.
+func (s AttachmentStorer) Get(ctx context.Context, requestID int, attachmentID int, user models.User) (models.ExpertAttachment, error) {
+	if err := s.canSee(ctx, requestID, user); err != nil {
+		return models.ExpertAttachment{}, err
+	}
+	return getAttachment(s.db.WithContext(ctx), requestID, attachmentID)
+}
+
+func (s AttachmentStorer) canSee(ctx context.Context, requestID int, user models.User) error {
+	if user.Role == consts.ROLE_MATIN {
+		return ErrNoAttachmentAccess
+	}
+	_, err := NewExpertStorer(s.db).Get(ctx, requestID, user)
+	return err
+}
+
+// Delete removes an attachment from a request the expert is working on and
+// returns it, so its file can be removed from the storage.
+func (s AttachmentStorer) Delete(ctx context.Context, requestID int, attachmentID int, expertID uint) (models.ExpertAttachment, error) {
+	var attachment models.ExpertAttachment
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := workingOn(tx, uint(requestID), expertID); err != nil {
+			return err
+		}
+
+		var err error
+		attachment, err = getAttachment(tx, requestID, attachmentID)
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&attachment).Error
+	})
+	if err != nil {
+		return models.ExpertAttachment{}, err
+	}
+	return attachment, nil
+}
+
+// workingOn loads a paid request the expert holds and can still change.
+func workingOn(tx *gorm.DB, requestID uint, expertID uint) (models.ExpertAds, error) {
+	var expertAd models.ExpertAds
+	err := tx.Where("id = ? AND status != ?", requestID, consts.WAIT_FOR_PAYMENT_STATUS).First(&expertAd).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return expertAd, ErrRequestNotFound
+	} else if err != nil {
+		return expertAd, err
+	}
+	if expertAd.Status == consts.DONE_STATUS {
+		return expertAd, ErrStatusLocked
+	}
+	if expertAd.ExpertID != expertID {
+		return expertAd, ErrNotClaimed
+	}
+	return expertAd, nil
+}
+
+func getAttachment(db *gorm.DB, requestID int, attachmentID int) (models.ExpertAttachment, error) {
+	var attachment models.ExpertAttachment
+	err := db.Where("id = ? AND expert_ads_id = ?", attachmentID, requestID).First(&attachment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return attachment, ErrAttachmentNotFound
+	}
+	return attachment, err
+}
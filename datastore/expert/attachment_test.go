@@ -0,0 +1,113 @@
+package expert
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachmentStorer(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	createAssignmentData(t, db)
+	if err := db.Model(&models.ExpertAds{}).Where("id = ?", 1).Update("expert_id", 2).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	template, err := NewInspectionStorer(db).CreateTemplate(ctx, models.InspectionTemplate{
+		CategoryID: 1,
+		Name:       "Jet",
+		Items:      []models.InspectionTemplateItem{{Section: consts.INSPECTION_ENGINES, Label: "Borescope"}},
+	})
+	assert.NoError(t, err)
+	itemID := template.Items[0].ID
+	otherItem := itemID + 1
+
+	s := NewAttachmentStorer(db)
+	attachment := func(requestID uint, uploaderID uint, item *uint, key string) models.ExpertAttachment {
+		return models.ExpertAttachment{
+			ExpertAdsID:    requestID,
+			TemplateItemID: item,
+			UploaderID:     uploaderID,
+			FileName:       "blade.jpg",
+			ContentType:    "image/jpeg",
+			Size:           10,
+			StorageKey:     key,
+		}
+	}
+
+	added, err := s.Add(ctx, attachment(1, 2, &itemID, "a"))
+	assert.NoError(t, err)
+	_, err = s.Add(ctx, attachment(1, 2, nil, "b"))
+	assert.NoError(t, err)
+	_, err = s.Add(ctx, attachment(1, 2, &otherItem, "c"))
+	assert.ErrorIs(t, err, ErrItemNotFound)
+	_, err = s.Add(ctx, attachment(1, 3, nil, "d"))
+	assert.ErrorIs(t, err, ErrNotClaimed)
+	_, err = s.Add(ctx, attachment(3, 2, nil, "e"))
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+	// the helicopter has no checklist yet
+	if err := db.Model(&models.ExpertAds{}).Where("id = ?", 2).Update("expert_id", 2).Error; err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Add(ctx, attachment(2, 2, &itemID, "f"))
+	assert.ErrorIs(t, err, ErrItemNotFound)
+
+	// visibility follows ExpertStorer.Get
+	expert := models.User{ID: 2, Role: consts.ROLE_EXPERT}
+	owner := models.User{ID: 1, Role: consts.ROLE_AIRLINE}
+	stranger := models.User{ID: 5, Role: consts.ROLE_AIRLINE}
+	other := models.User{ID: 3, Role: consts.ROLE_EXPERT}
+	admin := models.User{ID: 6, Role: consts.ROLE_ADMIN}
+	for _, user := range []models.User{expert, owner, admin} {
+		attachments, err := s.List(ctx, 1, user)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(attachments))
+		found, err := s.Get(ctx, 1, int(added.ID), user)
+		assert.NoError(t, err)
+		assert.Equal(t, itemID, *found.TemplateItemID)
+	}
+	for _, user := range []models.User{stranger, other} {
+		_, err = s.List(ctx, 1, user)
+		assert.ErrorIs(t, err, ErrRequestNotFound)
+		_, err = s.Get(ctx, 1, int(added.ID), user)
+		assert.ErrorIs(t, err, ErrRequestNotFound)
+	}
+	_, err = s.Get(ctx, 2, int(added.ID), admin)
+	assert.ErrorIs(t, err, ErrAttachmentNotFound)
+
+	// ExpertStorer.Get lets repair staff through, the evidence does not
+	matin := models.User{ID: 7, Role: consts.ROLE_MATIN}
+	_, err = s.List(ctx, 1, matin)
+	assert.ErrorIs(t, err, ErrNoAttachmentAccess)
+	_, err = s.Get(ctx, 1, int(added.ID), matin)
+	assert.ErrorIs(t, err, ErrNoAttachmentAccess)
+
+	_, err = s.Delete(ctx, 1, int(added.ID), 3)
+	assert.ErrorIs(t, err, ErrNotClaimed)
+	deleted, err := s.Delete(ctx, 1, int(added.ID), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", deleted.StorageKey)
+	_, err = s.Delete(ctx, 1, int(added.ID), 2)
+	assert.ErrorIs(t, err, ErrAttachmentNotFound)
+
+	// finished reports keep their evidence
+	if err := db.Model(&models.ExpertAds{}).Where("id = ?", 1).Update("status", consts.DONE_STATUS).Error; err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Add(ctx, attachment(1, 2, nil, "g"))
+	assert.ErrorIs(t, err, ErrStatusLocked)
+	attachments, err := s.List(ctx, 1, owner)
+	assert.NoError(t, err)
+	_, err = s.Delete(ctx, 1, int(attachments[0].ID), 2)
+	assert.ErrorIs(t, err, ErrStatusLocked)
+}
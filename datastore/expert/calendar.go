@@ -0,0 +1,64 @@
+package expert
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/models"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrFeedNotFound is also returned for revoked feeds, so a token can't be
+// told apart from one that never existed.
+var ErrFeedNotFound = apperror.NotFound("calendar_not_found", "calendar not found")
+
+type CalendarStorer struct {
+	db *gorm.DB
+}
+
+func NewCalendarStorer(db *gorm.DB) CalendarStorer {
+	return CalendarStorer{db: db}
+}
+
+// HashFeedToken is how calendar feed tokens are stored and looked up.
+func HashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Rotate gives the user a feed with the token, the link of their previous
+// feed stops working.
+func (c CalendarStorer) Rotate(ctx context.Context, userID uint, token string) error {
+	return c.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token_hash", "created_at"}),
+	}).Create(&models.CalendarFeed{UserID: userID, TokenHash: HashFeedToken(token), CreatedAt: time.Now()}).Error
+}
+
+func (c CalendarStorer) Revoke(ctx context.Context, userID uint) error {
+	result := c.db.WithContext(ctx).Where("user_id = ?", userID).Delete(&models.CalendarFeed{})
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrFeedNotFound
+	}
+	return nil
+}
+
+// Resolve is the active user the feed of the token belongs to.
+func (c CalendarStorer) Resolve(ctx context.Context, token string) (models.User, error) {
+	var user models.User
+	err := c.db.WithContext(ctx).
+		Joins("JOIN calendar_feeds ON calendar_feeds.user_id = users.id").
+		Where("calendar_feeds.token_hash = ? AND users.is_active = ?", HashFeedToken(token), true).
+		First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.User{}, ErrFeedNotFound
+	}
+	return user, err
+}
@@ -0,0 +1,103 @@
+package expert
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrCertificateNotFound = apperror.NotFound("certificate_not_found", "the expert report has no such certificate")
+	ErrNotCertifiable      = apperror.Conflict("report_not_done", "only finished expert reports get certificates")
+)
+
+type CertificateStorer struct {
+	db *gorm.DB
+}
+
+func NewCertificateStorer(db *gorm.DB) CertificateStorer {
+	return CertificateStorer{db: db}
+}
+
+// Issue stores the next revision of the certificate of a finished report.
+// sign gets the report and the revision it is for and returns the signed
+// certificate, it runs in the transaction so concurrent issues can't get
+// the same revision.
+func (s CertificateStorer) Issue(
+	ctx context.Context,
+	requestID int,
+	sign func(report models.ExpertAds, revision int) (models.ExpertCertificate, error),
+) (models.ExpertCertificate, error) {
+	var certificate models.ExpertCertificate
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		report, err := certifiedReport(tx, requestID)
+		if err != nil {
+			return err
+		}
+		if report.Status != consts.DONE_STATUS {
+			return ErrNotCertifiable
+		}
+
+		var revision int
+		err = tx.Model(&models.ExpertCertificate{}).
+			Select("COALESCE(MAX(revision), 0)").
+			Where("expert_ads_id = ?", requestID).
+			Scan(&revision).Error
+		if err != nil {
+			return err
+		}
+
+		certificate, err = sign(report, revision+1)
+		if err != nil {
+			return err
+		}
+		certificate.ExpertAdsID = report.ID
+		certificate.Revision = revision + 1
+		return tx.Create(&certificate).Error
+	})
+	if err != nil {
+		return models.ExpertCertificate{}, err
+	}
+	return certificate, nil
+}
+
+// Report loads a report with everything its certificate covers.
+func (s CertificateStorer) Report(ctx context.Context, requestID int) (models.ExpertAds, error) {
+	return certifiedReport(s.db.WithContext(ctx), requestID)
+}
+
+// List returns the certificates of a report, oldest revision first.
+func (s CertificateStorer) List(ctx context.Context, requestID int) ([]models.ExpertCertificate, error) {
+	var certificates []models.ExpertCertificate
+	err := s.db.WithContext(ctx).
+		Where("expert_ads_id = ?", requestID).
+		Order("revision").
+		Find(&certificates).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(certificates) == 0 {
+		return nil, ErrCertificateNotFound
+	}
+	return certificates, nil
+}
+
+func certifiedReport(db *gorm.DB, requestID int) (models.ExpertAds, error) {
+	var report models.ExpertAds
+	err := db.
+		Preload("Ads").
+		Preload("Ads.Category").
+		Preload("Ads.Attributes").
+		Preload("Ads.Attributes.Attribute").
+		Preload("Expert").
+		Preload("Checklist", orderChecklist).
+		First(&report, requestID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return report, ErrRequestNotFound
+	}
+	return report, err
+}
@@ -0,0 +1,76 @@
+package expert
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCertificateStorer(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	createAssignmentData(t, db)
+	rows := []interface{}{
+		&models.CategoryAttribute{ID: 1, CategoryID: 1, Name: "serial_number", Type: "string"},
+		&models.AdAttribute{AdsID: 1, AttributeID: 1, Value: "MSN-1234"},
+	}
+	for _, row := range rows {
+		if err := db.Create(row).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = db.Model(&models.ExpertAds{}).Where("id = ?", 1).
+		Updates(map[string]interface{}{"expert_id": 2, "status": consts.DONE_STATUS, "report": "airworthy"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	s := NewCertificateStorer(db)
+	sign := func(report models.ExpertAds, revision int) (models.ExpertCertificate, error) {
+		assert.Equal(t, "expert2", report.Expert.Username)
+		assert.Equal(t, "MSN-1234", report.Ads.Attributes[0].Value)
+		assert.Equal(t, "serial_number", report.Ads.Attributes[0].Attribute.Name)
+		return models.ExpertCertificate{
+			Payload:   report.Report,
+			Signature: "signature",
+			KeyID:     "key",
+			IssuedAt:  time.Now(),
+		}, nil
+	}
+
+	_, err = s.List(ctx, 1)
+	assert.ErrorIs(t, err, ErrCertificateNotFound)
+	_, err = s.Issue(ctx, 2, sign)
+	assert.ErrorIs(t, err, ErrNotCertifiable)
+	_, err = s.Issue(ctx, 9, sign)
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+
+	first, err := s.Issue(ctx, 1, sign)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, first.Revision)
+	assert.Equal(t, uint(1), first.ExpertAdsID)
+
+	// the expert revises the report
+	if err := db.Model(&models.ExpertAds{}).Where("id = ?", 1).Update("report", "airworthy, new tires").Error; err != nil {
+		t.Fatal(err)
+	}
+	second, err := s.Issue(ctx, 1, sign)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, second.Revision)
+
+	certificates, err := s.List(ctx, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(certificates))
+	assert.Equal(t, "airworthy", certificates[0].Payload)
+	assert.Equal(t, "airworthy, new tires", certificates[1].Payload)
+}
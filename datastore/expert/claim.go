@@ -0,0 +1,114 @@
+package expert
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore/sla"
+	"Airplane-Divar/models"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAlreadyClaimed = apperror.Conflict("already_claimed", "another expert already claimed this request")
+	ErrNotClaimed     = apperror.Conflict("not_claimed", "you haven't claimed this request")
+)
+
+// Claim makes the expert the one working on a request waiting for an expert.
+// The check and the update are one statement, so of experts claiming at the
+// same time only one wins and the others get ErrAlreadyClaimed. Claiming a
+// request the expert already works on does nothing.
+func (e ExpertStorer) Claim(ctx context.Context, requestID int, user models.User) (models.ExpertAds, error) {
+	var expertAd models.ExpertAds
+	result, err := e.transition(ctx, &expertAd, func(tx *gorm.DB) *gorm.DB {
+		return tx.
+			Where(
+				"id = ? AND status = ? AND (expert_id IS NULL OR expert_id = ?)",
+				requestID, consts.EXPERT_PENDING_STATUS, user.ID,
+			).
+			Updates(map[string]interface{}{"expert_id": user.ID, "status": consts.IN_PROGRESS_STATUS})
+	})
+	if err != nil {
+		return models.ExpertAds{}, err
+	}
+	if result.RowsAffected > 0 {
+		return expertAd, nil
+	}
+
+	current, err := e.current(ctx, requestID)
+	if err != nil {
+		return models.ExpertAds{}, err
+	}
+	switch {
+	case current.Status == consts.DONE_STATUS:
+		return models.ExpertAds{}, ErrStatusLocked
+	case current.ExpertID == user.ID && current.Status == consts.IN_PROGRESS_STATUS:
+		return current, nil
+	}
+	return models.ExpertAds{}, ErrAlreadyClaimed
+}
+
+// Release gives a request the expert claimed back to the other experts.
+func (e ExpertStorer) Release(ctx context.Context, requestID int, user models.User) (models.ExpertAds, error) {
+	var expertAd models.ExpertAds
+	result, err := e.transition(ctx, &expertAd, func(tx *gorm.DB) *gorm.DB {
+		return tx.
+			Where(
+				"id = ? AND expert_id = ? AND status IN ?",
+				requestID, user.ID, []consts.Status{consts.EXPERT_PENDING_STATUS, consts.IN_PROGRESS_STATUS},
+			).
+			Updates(map[string]interface{}{"expert_id": nil, "status": consts.EXPERT_PENDING_STATUS})
+	})
+	if err != nil {
+		return models.ExpertAds{}, err
+	}
+	if result.RowsAffected > 0 {
+		return expertAd, nil
+	}
+
+	current, err := e.current(ctx, requestID)
+	if err != nil {
+		return models.ExpertAds{}, err
+	}
+	if current.Status == consts.DONE_STATUS {
+		return models.ExpertAds{}, ErrStatusLocked
+	}
+	return models.ExpertAds{}, ErrNotClaimed
+}
+
+// transition runs a status update returning the request into expertAd and
+// moves its SLA to the new status in the same transaction. An appointment
+// with an expert the request no longer has is cancelled.
+func (e ExpertStorer) transition(
+	ctx context.Context, expertAd *models.ExpertAds, update func(tx *gorm.DB) *gorm.DB,
+) (*gorm.DB, error) {
+	var result *gorm.DB
+	err := e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result = update(tx.Clauses(clause.Returning{}).Model(expertAd))
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		now := time.Now()
+		if err := cancelStale(tx, expertAd.ID, expertAd.ExpertID, now); err != nil {
+			return err
+		}
+		return sla.Transition(tx, consts.SLA_EXPERT, expertAd.ID, expertAd.Status, expertAd.ExpertID, now)
+	})
+	return result, err
+}
+
+// current loads a paid request, the ones waiting for payment are not found.
+func (e ExpertStorer) current(ctx context.Context, requestID int) (models.ExpertAds, error) {
+	var expertAd models.ExpertAds
+	err := e.db.WithContext(ctx).
+		Where("id = ? AND status != ?", requestID, consts.WAIT_FOR_PAYMENT_STATUS).
+		First(&expertAd).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.ExpertAds{}, ErrRequestNotFound
+	}
+	return expertAd, err
+}
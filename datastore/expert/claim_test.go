@@ -0,0 +1,110 @@
+package expert
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpertStorer_Claim(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	createAssignmentData(t, db)
+
+	const experts = 30
+	users := []models.User{}
+	for i := 0; i < experts; i++ {
+		users = append(users, models.User{
+			ID: uint(100 + i), Username: fmt.Sprintf("claimer%d", i), Password: "-", Token: "-",
+			Role: consts.ROLE_EXPERT, IsActive: true,
+		})
+	}
+	assert.NoError(t, db.Create(&users).Error)
+
+	ctx := context.Background()
+	e := NewExpertStorer(db)
+
+	claimed, failed := hammer(users, func(user models.User) error {
+		_, err := e.Claim(ctx, 1, user)
+		return err
+	})
+	assert.Equal(t, 1, len(claimed))
+	for _, err := range failed {
+		assert.ErrorIs(t, err, ErrAlreadyClaimed)
+	}
+	winner, loser := claimed[0], users[0]
+	if loser.ID == winner.ID {
+		loser = users[1]
+	}
+
+	var expertAd models.ExpertAds
+	assert.NoError(t, db.First(&expertAd, 1).Error)
+	assert.Equal(t, winner.ID, expertAd.ExpertID)
+	assert.Equal(t, consts.IN_PROGRESS_STATUS, expertAd.Status)
+
+	// claiming again is fine for the winner only
+	_, err = e.Claim(ctx, 1, winner)
+	assert.NoError(t, err)
+	_, err = e.Claim(ctx, 1, loser)
+	assert.ErrorIs(t, err, ErrAlreadyClaimed)
+	_, err = e.Release(ctx, 1, loser)
+	assert.ErrorIs(t, err, ErrNotClaimed)
+
+	released, err := e.Release(ctx, 1, winner)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), released.ExpertID)
+	assert.Equal(t, consts.EXPERT_PENDING_STATUS, released.Status)
+
+	_, err = e.Claim(ctx, 3, winner)
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+
+	// updating the status takes the request the same way
+	updated, failed := hammer(users, func(user models.User) error {
+		_, err := e.UpdateByExpert(ctx, 2, user, models.UpdateExpertCheckRequest{Status: consts.IN_PROGRESS_STATUS})
+		return err
+	})
+	assert.Equal(t, 1, len(updated))
+	for _, err := range failed {
+		assert.ErrorIs(t, err, ErrAlreadyClaimed)
+	}
+}
+
+// hammer runs action for every user at the same time, it returns the users
+// it succeeded for and the errors of the others.
+func hammer(users []models.User, action func(user models.User) error) ([]models.User, []error) {
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		start     = make(chan struct{})
+		succeeded = []models.User{}
+		failed    = []error{}
+	)
+	for _, user := range users {
+		wg.Add(1)
+		go func(user models.User) {
+			defer wg.Done()
+			<-start
+			err := action(user)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failed = append(failed, err)
+			} else {
+				succeeded = append(succeeded, user)
+			}
+		}(user)
+	}
+	close(start)
+	wg.Wait()
+	return succeeded, failed
+}
@@ -0,0 +1,241 @@
+package expert
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"context"
+	"errors"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrTemplateNotFound = apperror.NotFound("template_not_found", "there is no inspection template for this category")
+	ErrTemplateExists   = apperror.Conflict("template_exists", "the category already has an inspection template")
+)
+
+type InspectionStorer struct {
+	db *gorm.DB
+}
+
+func NewInspectionStorer(db *gorm.DB) InspectionStorer {
+	return InspectionStorer{db: db}
+}
+
+func (s InspectionStorer) CreateTemplate(ctx context.Context, template models.InspectionTemplate) (models.InspectionTemplate, error) {
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&models.Category{}, template.CategoryID).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryNotFound
+		} else if err != nil {
+			return err
+		}
+
+		var count int64
+		err := tx.Model(&models.InspectionTemplate{}).Where("category_id = ?", template.CategoryID).Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrTemplateExists
+		}
+		return tx.Create(&template).Error
+	})
+	if err != nil {
+		return models.InspectionTemplate{}, err
+	}
+	return template, nil
+}
+
+func (s InspectionStorer) ListTemplates(ctx context.Context) ([]models.InspectionTemplate, error) {
+	templates := []models.InspectionTemplate{}
+	err := s.db.WithContext(ctx).Preload("Items", orderItems).Order("id").Find(&templates).Error
+	return templates, err
+}
+
+func (s InspectionStorer) GetTemplate(ctx context.Context, id int) (models.InspectionTemplate, error) {
+	return getTemplate(s.db.WithContext(ctx), "id = ?", id)
+}
+
+// UpdateTemplate renames the template and replaces its items, reports that
+// were already filled keep their items.
+func (s InspectionStorer) UpdateTemplate(ctx context.Context, id int, name string, items []models.InspectionTemplateItem) (models.InspectionTemplate, error) {
+	var template models.InspectionTemplate
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		if template, err = getTemplate(tx, "id = ?", id); err != nil {
+			return err
+		}
+		if err := tx.Model(&template).Update("name", name).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id = ?", id).Delete(&models.InspectionTemplateItem{}).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].TemplateID = template.ID
+		}
+		if len(items) > 0 {
+			if err := tx.Create(&items).Error; err != nil {
+				return err
+			}
+		}
+		template.Name = name
+		template.Items = items
+		return nil
+	})
+	if err != nil {
+		return models.InspectionTemplate{}, err
+	}
+	return template, nil
+}
+
+func (s InspectionStorer) DeleteTemplate(ctx context.Context, id int) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := getTemplate(tx, "id = ?", id); err != nil {
+			return err
+		}
+		if err := tx.Where("template_id = ?", id).Delete(&models.InspectionTemplateItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.InspectionTemplate{}, id).Error
+	})
+}
+
+// TemplateFor is the template of the category of the ad of the request.
+// Categories without a template use the one of their closest parent.
+func (s InspectionStorer) TemplateFor(ctx context.Context, requestID int) (models.InspectionTemplate, error) {
+	db := s.db.WithContext(ctx)
+
+	var expertAd models.ExpertAds
+	err := db.Preload("Ads").First(&expertAd, requestID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.InspectionTemplate{}, ErrRequestNotFound
+	} else if err != nil {
+		return models.InspectionTemplate{}, err
+	}
+
+	categoryID := &expertAd.Ads.CategoryID
+	for seen := map[uint]bool{}; categoryID != nil && !seen[*categoryID]; {
+		seen[*categoryID] = true
+
+		template, err := getTemplate(db, "category_id = ?", *categoryID)
+		if !errors.Is(err, ErrTemplateNotFound) {
+			return template, err
+		}
+
+		var category models.Category
+		if err := db.First(&category, *categoryID).Error; err != nil {
+			break
+		}
+		categoryID = category.ParentID
+	}
+	return models.InspectionTemplate{}, ErrTemplateNotFound
+}
+
+// SaveChecklist replaces the checklist of a request the expert is working
+// on and grades it.
+func (s InspectionStorer) SaveChecklist(ctx context.Context, requestID int, expertID uint, items []models.InspectionItem) (models.ExpertAds, error) {
+	var expertAd models.ExpertAds
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		expertAd, err = workingOn(tx, uint(requestID), expertID)
+		if err != nil {
+			return err
+		}
+
+		score, grade := Grade(items)
+		result := tx.Model(&models.ExpertAds{}).
+			Where("id = ? AND expert_id = ? AND status != ?", requestID, expertID, consts.DONE_STATUS).
+			Updates(map[string]interface{}{"grade": grade, "score": score})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotClaimed
+		}
+
+		if err := tx.Where("expert_ads_id = ?", requestID).Delete(&models.InspectionItem{}).Error; err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].ExpertAdsID = expertAd.ID
+		}
+		if len(items) > 0 {
+			if err := tx.Create(&items).Error; err != nil {
+				return err
+			}
+		}
+
+		expertAd.Grade = grade
+		expertAd.Score = score
+		expertAd.Checklist = items
+		return nil
+	})
+	if err != nil {
+		return models.ExpertAds{}, err
+	}
+	return expertAd, nil
+}
+
+// Grade scores a checklist out of 100 from the ratings of its items and
+// turns the score into a letter. A major finding keeps the grade at C or
+// below and a critical one fails the airplane.
+func Grade(items []models.InspectionItem) (float64, string) {
+	if len(items) == 0 {
+		return 0, ""
+	}
+
+	var total float64
+	worst := consts.SEVERITY_NONE
+	for _, item := range items {
+		total += float64(item.Rating-consts.INSPECTION_MIN_RATING) / float64(consts.INSPECTION_MAX_RATING-consts.INSPECTION_MIN_RATING)
+		if severityRank[item.Severity] > severityRank[worst] {
+			worst = item.Severity
+		}
+	}
+	score := math.Round(total/float64(len(items))*1000) / 10
+
+	grade := "F"
+	switch {
+	case score >= 90:
+		grade = "A"
+	case score >= 75:
+		grade = "B"
+	case score >= 60:
+		grade = "C"
+	case score >= 40:
+		grade = "D"
+	}
+	if worst == consts.SEVERITY_CRITICAL {
+		grade = "F"
+	} else if worst == consts.SEVERITY_MAJOR && grade < "C" {
+		grade = "C"
+	}
+	return score, grade
+}
+
+var severityRank = map[string]int{
+	consts.SEVERITY_NONE:     0,
+	consts.SEVERITY_MINOR:    1,
+	consts.SEVERITY_MAJOR:    2,
+	consts.SEVERITY_CRITICAL: 3,
+}
+
+func getTemplate(db *gorm.DB, query string, arg interface{}) (models.InspectionTemplate, error) {
+	var template models.InspectionTemplate
+	err := db.Preload("Items", orderItems).Where(query, arg).First(&template).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.InspectionTemplate{}, ErrTemplateNotFound
+	}
+	return template, err
+}
+
+func orderItems(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+func orderChecklist(db *gorm.DB) *gorm.DB {
+	return db.Order("position, template_item_id")
+}
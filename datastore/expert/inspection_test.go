@@ -0,0 +1,132 @@
+package expert
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspectionStorer(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	createAssignmentData(t, db)
+
+	// a business jet is a jet, it has no template of its own
+	parentID := uint(1)
+	if err := db.Create(&models.Category{ID: 3, Name: "Business Jet", ParentID: &parentID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Ad{ID: 3, UserID: 1, Subject: "business jet", Price: 100, CategoryID: 3, Status: string(consts.ACTIVE)}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Model(&models.ExpertAds{}).Create(map[string]interface{}{
+		"ID": 4, "AdsID": 3, "UserID": 1, "ExpertID": 2, "Status": consts.EXPERT_PENDING_STATUS,
+	}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	s := NewInspectionStorer(db)
+
+	template, err := s.CreateTemplate(ctx, models.InspectionTemplate{
+		CategoryID: 1,
+		Name:       "Jet",
+		Items: []models.InspectionTemplateItem{
+			{Section: consts.INSPECTION_ENGINES, Label: "Compressor blades", Position: 1},
+			{Section: consts.INSPECTION_AIRFRAME, Label: "Corrosion", Position: 0},
+		},
+	})
+	assert.NoError(t, err)
+	_, err = s.CreateTemplate(ctx, models.InspectionTemplate{CategoryID: 1, Name: "Jet again"})
+	assert.ErrorIs(t, err, ErrTemplateExists)
+
+	found, err := s.TemplateFor(ctx, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, template.ID, found.ID)
+	assert.Equal(t, "Corrosion", found.Items[0].Label)
+	_, err = s.TemplateFor(ctx, 2)
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+	_, err = s.TemplateFor(ctx, 9)
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+
+	checklist := func() []models.InspectionItem {
+		return []models.InspectionItem{
+			{TemplateItemID: found.Items[0].ID, Section: consts.INSPECTION_AIRFRAME, Label: "Corrosion", Position: 0, Rating: 5, Severity: consts.SEVERITY_NONE},
+			{TemplateItemID: found.Items[1].ID, Section: consts.INSPECTION_ENGINES, Label: "Compressor blades", Position: 1, Rating: 4, Finding: "Nicked blade", Severity: consts.SEVERITY_MINOR},
+		}
+	}
+
+	_, err = s.SaveChecklist(ctx, 4, 3, checklist())
+	assert.ErrorIs(t, err, ErrNotClaimed)
+	_, err = s.SaveChecklist(ctx, 3, 2, checklist())
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+
+	expertAd, err := s.SaveChecklist(ctx, 4, 2, checklist())
+	assert.NoError(t, err)
+	assert.Equal(t, "B", expertAd.Grade)
+	assert.Equal(t, 87.5, expertAd.Score)
+
+	// saving again replaces the checklist
+	expertAd, err = s.SaveChecklist(ctx, 4, 2, checklist())
+	assert.NoError(t, err)
+	stored, err := NewExpertStorer(db).Get(ctx, 4, models.User{ID: 2, Role: consts.ROLE_EXPERT})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(stored.Checklist))
+	assert.Equal(t, "Corrosion", stored.Checklist[0].Label)
+	assert.Equal(t, expertAd.Grade, stored.Grade)
+
+	// templates can change without touching filled checklists
+	_, err = s.UpdateTemplate(ctx, int(template.ID), "Jet", []models.InspectionTemplateItem{
+		{Section: consts.INSPECTION_AVIONICS, Label: "Autopilot", Position: 0},
+	})
+	assert.NoError(t, err)
+	stored, err = NewExpertStorer(db).Get(ctx, 4, models.User{ID: 2, Role: consts.ROLE_EXPERT})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(stored.Checklist))
+
+	if err := db.Model(&models.ExpertAds{}).Where("id = ?", 4).Update("status", consts.DONE_STATUS).Error; err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.SaveChecklist(ctx, 4, 2, checklist())
+	assert.ErrorIs(t, err, ErrStatusLocked)
+
+	assert.NoError(t, s.DeleteTemplate(ctx, int(template.ID)))
+	_, err = s.GetTemplate(ctx, int(template.ID))
+	assert.ErrorIs(t, err, ErrTemplateNotFound)
+}
+
+func TestGrade(t *testing.T) {
+	item := func(rating int, severity string) models.InspectionItem {
+		return models.InspectionItem{Rating: rating, Severity: severity}
+	}
+
+	tests := []struct {
+		name  string
+		items []models.InspectionItem
+		score float64
+		grade string
+	}{
+		{"empty", nil, 0, ""},
+		{"perfect", []models.InspectionItem{item(5, consts.SEVERITY_NONE), item(5, consts.SEVERITY_NONE)}, 100, "A"},
+		{"average", []models.InspectionItem{item(4, consts.SEVERITY_MINOR), item(3, consts.SEVERITY_NONE)}, 62.5, "C"},
+		{"poor", []models.InspectionItem{item(1, consts.SEVERITY_NONE), item(3, consts.SEVERITY_NONE)}, 25, "F"},
+		{"major caps", []models.InspectionItem{item(5, consts.SEVERITY_MAJOR), item(5, consts.SEVERITY_NONE)}, 100, "C"},
+		{"major below cap", []models.InspectionItem{item(2, consts.SEVERITY_MAJOR), item(3, consts.SEVERITY_NONE)}, 37.5, "F"},
+		{"critical fails", []models.InspectionItem{item(5, consts.SEVERITY_CRITICAL)}, 100, "F"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, grade := Grade(tt.items)
+			assert.Equal(t, tt.score, score)
+			assert.Equal(t, tt.grade, grade)
+		})
+	}
+}
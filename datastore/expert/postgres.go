@@ -1,6 +1,7 @@
 package expert
 
 import (
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/consts"
 	"Airplane-Divar/models"
 	"Airplane-Divar/utils"
@@ -12,6 +13,14 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+var (
+	ErrAlreadyChecked   = apperror.Conflict("already_checked", "this had been already checked by experts")
+	ErrAlreadyRequested = apperror.Conflict("already_requested", "you had been requested for expert check")
+	ErrStatusNotAllowed = apperror.Forbidden("status_not_allowed", "not allowed")
+	ErrRequestNotFound  = apperror.NotFound("expert_request_not_found", "expert check request does not exist")
+	ErrStatusLocked     = apperror.Conflict("status_locked", "you can't change the status")
+)
+
 type ExpertStorer struct {
 	db *gorm.DB
 }
@@ -28,6 +37,7 @@ func (e ExpertStorer) GetByAd(
 	var expertAd models.ExpertAds
 	query := e.db.WithContext(ctx).
 		Joins("Ads", e.db.Select("Ads.subject")).
+		Preload("Checklist", orderChecklist).
 		Where("expert_ads.ads_id = ?", adID)
 
 	if user.Role == consts.ROLE_EXPERT { // is_expert
@@ -38,9 +48,12 @@ func (e ExpertStorer) GetByAd(
 	} else if user.Role == consts.ROLE_AIRLINE { // is advertiser
 		query.Where(&models.Ad{UserID: user.ID})
 	}
-	result := query.First(&expertAd)
+	err := query.First(&expertAd).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return expertAd, ErrRequestNotFound
+	}
 
-	return expertAd, result.Error
+	return expertAd, err
 }
 
 func (e ExpertStorer) Get(
@@ -51,6 +64,7 @@ func (e ExpertStorer) Get(
 	var expertAd models.ExpertAds
 	query := e.db.WithContext(ctx).
 		Joins("Ads", e.db.Select("Ads.subject")).
+		Preload("Checklist", orderChecklist).
 		Where("expert_ads.id = ?", requestID)
 
 	if user.Role == consts.ROLE_EXPERT { // is_expert
@@ -61,9 +75,12 @@ func (e ExpertStorer) Get(
 	} else if user.Role == consts.ROLE_AIRLINE { // is advertiser
 		query.Where(&models.Ad{UserID: user.ID})
 	}
-	result := query.First(&expertAd)
+	err := query.First(&expertAd).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return expertAd, ErrRequestNotFound
+	}
 
-	return expertAd, result.Error
+	return expertAd, err
 }
 
 func (e ExpertStorer) RequestToExpertCheck(
@@ -75,7 +92,7 @@ func (e ExpertStorer) RequestToExpertCheck(
 		return err
 	}
 	if ad.ExpertCheck {
-		return errors.New("this had been already checked by experts")
+		return ErrAlreadyChecked
 	}
 
 	// get or create expert_ad
@@ -89,7 +106,7 @@ func (e ExpertStorer) RequestToExpertCheck(
 
 	if expertAd.ID != 0 {
 		if expertAd.Status != consts.EXPERT_PENDING_STATUS {
-			return errors.New("you had been requested for expert check")
+			return ErrAlreadyRequested
 		}
 		return nil
 	}
@@ -137,10 +154,10 @@ func (e ExpertStorer) GetAllExpertRequests(
 func (e ExpertStorer) Update(
 	ctx context.Context, expertAdID int, upadtedColumn map[string]interface{},
 ) error {
-	err := e.db.Model(&models.ExpertAds{}).
-		Where("id = ?", expertAdID).
-		Updates(upadtedColumn).Error
-
+	var expertAd models.ExpertAds
+	_, err := e.transition(ctx, &expertAd, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("id = ?", expertAdID).Updates(upadtedColumn)
+	})
 	return err
 }
 
@@ -161,7 +178,7 @@ func (e ExpertStorer) UpdateByExpert(
 		if body.Status == consts.EXPERT_PENDING_STATUS {
 			updatedMap["expert_id"] = nil
 		} else if body.Status == consts.WAIT_FOR_PAYMENT_STATUS {
-			return tmpExpertAd, errors.New("not allowed")
+			return tmpExpertAd, ErrStatusNotAllowed
 		} else {
 			updatedMap["expert_id"] = user.ID
 		}
@@ -175,21 +192,37 @@ func (e ExpertStorer) UpdateByExpert(
 	err := e.db.WithContext(ctx).First(&expertAd, expertAdID).Error
 	if err != nil {
 		log.Println(err)
-		return expertAd, errors.New("expert check request does not exist")
+		return expertAd, ErrRequestNotFound
 	} else if expertAd.Status == consts.DONE_STATUS && expertAd.Status != body.Status {
-		return expertAd, errors.New("you can't change the status")
+		return expertAd, ErrStatusLocked
 	}
 
-	result := e.db.WithContext(ctx).
-		Clauses(clause.Returning{}).
-		Model(&tmpExpertAd).
-		Where(
+	// checking the expert and the lock in the update itself keeps another
+	// expert from taking the request between the read above and here
+	result, err := e.transition(ctx, &tmpExpertAd, func(tx *gorm.DB) *gorm.DB {
+		query := tx.Where(
 			"id = ? AND (expert_id = ? OR expert_id IS NULL) AND status != ?",
 			expertAdID, user.ID, consts.WAIT_FOR_PAYMENT_STATUS,
-		).
-		Updates(updatedMap)
+		)
+		if body.Status != consts.DONE_STATUS {
+			query = query.Where("status != ?", consts.DONE_STATUS)
+		}
+		return query.Updates(updatedMap)
+	})
+	if err != nil {
+		return tmpExpertAd, err
+	}
+	if result.RowsAffected == 0 {
+		current, err := e.current(ctx, expertAdID)
+		if err != nil {
+			return models.ExpertAds{}, err
+		} else if current.Status == consts.DONE_STATUS {
+			return models.ExpertAds{}, ErrStatusLocked
+		}
+		return models.ExpertAds{}, ErrAlreadyClaimed
+	}
 
-	return tmpExpertAd, result.Error
+	return tmpExpertAd, nil
 }
 
 func (e ExpertStorer) Delete(
@@ -206,7 +239,7 @@ func (e ExpertStorer) Delete(
 	if result.Error != nil {
 		return result.Error
 	} else if result.RowsAffected == 0 {
-		return errors.New("expert request not found")
+		return ErrRequestNotFound
 	}
 	return nil
 
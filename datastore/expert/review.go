@@ -0,0 +1,141 @@
+package expert
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"Airplane-Divar/utils"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReviewNotFound  = apperror.NotFound("review_not_found", "review does not exist")
+	ErrAlreadyReviewed = apperror.Conflict("already_reviewed", "you have already reviewed this expert check")
+	ErrNotReviewable   = apperror.Conflict("not_reviewable", "only done expert checks can be reviewed")
+)
+
+type ReviewStorer struct {
+	db *gorm.DB
+}
+
+func NewReviewStorer(db *gorm.DB) ReviewStorer {
+	return ReviewStorer{db: db}
+}
+
+// Create adds the review of the airline that requested the expert check, on
+// the expert who did it. Only done checks can be reviewed, once.
+func (r ReviewStorer) Create(ctx context.Context, requestID int, user models.User, rating int, comment string) (models.ExpertReview, error) {
+	var review models.ExpertReview
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var expertAd models.ExpertAds
+		err := tx.Where("id = ? AND user_id = ?", requestID, user.ID).First(&expertAd).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRequestNotFound
+		} else if err != nil {
+			return err
+		}
+		if expertAd.Status != consts.DONE_STATUS || expertAd.ExpertID == 0 {
+			return ErrNotReviewable
+		}
+
+		var count int64
+		if err := tx.Model(&models.ExpertReview{}).Where("expert_ads_id = ?", expertAd.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAlreadyReviewed
+		}
+
+		review = models.ExpertReview{
+			ExpertAdsID: expertAd.ID,
+			ExpertID:    expertAd.ExpertID,
+			UserID:      user.ID,
+			Rating:      rating,
+			Comment:     comment,
+			CreatedAt:   time.Now(),
+		}
+		if err := tx.Create(&review).Error; err != nil {
+			return err
+		}
+		return tx.Preload("User").First(&review, review.ID).Error
+	})
+	if duplicate(r.db, err) {
+		// a double submit that committed between the check and the insert
+		return models.ExpertReview{}, ErrAlreadyReviewed
+	} else if err != nil {
+		return models.ExpertReview{}, err
+	}
+	return review, nil
+}
+
+// List is a page of the reviews of the expert, the newest first. Hidden
+// reviews are only listed with withHidden.
+func (r ReviewStorer) List(ctx context.Context, expertID uint, withHidden bool, page int) ([]models.ExpertReview, error) {
+	reviews := []models.ExpertReview{}
+	query := r.db.WithContext(ctx).
+		Scopes(utils.Paginate(page)).
+		Preload("User").
+		Where("expert_id = ?", expertID)
+	if !withHidden {
+		query = query.Where("hidden = ?", false)
+	}
+	err := query.Order("created_at DESC, id DESC").Find(&reviews).Error
+	return reviews, err
+}
+
+// Rating is the average of the visible reviews of the expert.
+func (r ReviewStorer) Rating(ctx context.Context, expertID uint) (models.ExpertRating, error) {
+	rating := models.ExpertRating{ExpertID: expertID}
+	err := r.db.WithContext(ctx).
+		Model(&models.ExpertReview{}).
+		Select("COALESCE(AVG(rating), 0) AS average, COUNT(*) AS reviews").
+		Where("expert_id = ? AND hidden = ?", expertID, false).
+		Scan(&rating).Error
+	rating.ExpertID = expertID
+	return rating, err
+}
+
+// Expert loads an active expert, other users are not found.
+func (r ReviewStorer) Expert(ctx context.Context, expertID uint) (models.User, error) {
+	var expert models.User
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND role = ? AND is_active = ?", expertID, consts.ROLE_EXPERT, true).
+		First(&expert).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return models.User{}, ErrExpertNotFound
+	}
+	return expert, err
+}
+
+// SetHidden hides an abusive review or shows it again.
+func (r ReviewStorer) SetHidden(ctx context.Context, reviewID int, admin models.User, hidden bool) (models.ExpertReview, error) {
+	var review models.ExpertReview
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.First(&review, reviewID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrReviewNotFound
+		} else if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{"hidden": false, "hidden_by": nil, "hidden_at": nil}
+		if hidden {
+			updates = map[string]interface{}{"hidden": true, "hidden_by": admin.ID, "hidden_at": time.Now()}
+		}
+		if err := tx.Model(&review).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.Preload("User").First(&review, review.ID).Error
+	})
+	if duplicate(r.db, err) {
+		// a double submit that committed between the check and the insert
+		return models.ExpertReview{}, ErrAlreadyReviewed
+	} else if err != nil {
+		return models.ExpertReview{}, err
+	}
+	return review, nil
+}
@@ -0,0 +1,114 @@
+package expert
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestReviewStorer(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	createAssignmentData(t, db)
+	// request 1 was done by expert 2, request 2 is still pending
+	assert.NoError(t, db.Model(&models.ExpertAds{}).Where("id = ?", 1).
+		Updates(map[string]interface{}{"status": consts.DONE_STATUS, "expert_id": 2}).Error)
+	assert.NoError(t, db.Create(&models.User{ID: 5, Username: "admin", Password: "-", Token: "-", Role: consts.ROLE_ADMIN}).Error)
+
+	ctx := context.Background()
+	r := NewReviewStorer(db)
+	airline := models.User{ID: 1, Role: consts.ROLE_AIRLINE}
+	stranger := models.User{ID: 3, Role: consts.ROLE_AIRLINE}
+	admin := models.User{ID: 5, Role: consts.ROLE_ADMIN}
+
+	_, err = r.Create(ctx, 1, stranger, 5, "")
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+	_, err = r.Create(ctx, 2, airline, 5, "")
+	assert.ErrorIs(t, err, ErrNotReviewable)
+
+	review, err := r.Create(ctx, 1, airline, 2, "slow and sloppy")
+	assert.NoError(t, err)
+	assert.Equal(t, uint(2), review.ExpertID)
+	assert.Equal(t, "airline", review.User.Username)
+	_, err = r.Create(ctx, 1, airline, 5, "changed my mind")
+	assert.ErrorIs(t, err, ErrAlreadyReviewed)
+
+	// a second done request of expert 2
+	assert.NoError(t, db.Model(&models.ExpertAds{}).Create(map[string]interface{}{
+		"ID": 4, "AdsID": 2, "UserID": 1, "ExpertID": 2, "Status": consts.DONE_STATUS,
+	}).Error)
+	_, err = r.Create(ctx, 4, airline, 5, "")
+	assert.NoError(t, err)
+
+	rating, err := r.Rating(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ExpertRating{ExpertID: 2, Average: 3.5, Reviews: 2}, rating)
+
+	// hidden reviews leave the rating and the public list, not the admin one
+	hidden, err := r.SetHidden(ctx, int(review.ID), admin, true)
+	assert.NoError(t, err)
+	assert.True(t, hidden.Hidden)
+	assert.Equal(t, admin.ID, *hidden.HiddenBy)
+
+	rating, err = r.Rating(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ExpertRating{ExpertID: 2, Average: 5, Reviews: 1}, rating)
+	reviews, err := r.List(ctx, 2, false, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(reviews))
+	reviews, err = r.List(ctx, 2, true, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(reviews))
+
+	candidates, err := NewAssignmentStorer(db).Candidates(ctx, 2)
+	assert.NoError(t, err)
+	for _, candidate := range candidates {
+		if candidate.ExpertID == 2 {
+			assert.Equal(t, 5.0, candidate.Rating)
+			assert.Equal(t, 1, candidate.Reviews)
+		} else {
+			assert.Equal(t, 0, candidate.Reviews)
+		}
+	}
+
+	shown, err := r.SetHidden(ctx, int(review.ID), admin, false)
+	assert.NoError(t, err)
+	assert.False(t, shown.Hidden)
+	assert.Nil(t, shown.HiddenBy)
+	_, err = r.SetHidden(ctx, 99, admin, true)
+	assert.ErrorIs(t, err, ErrReviewNotFound)
+
+	_, err = r.Expert(ctx, 2)
+	assert.NoError(t, err)
+	_, err = r.Expert(ctx, 1)
+	assert.ErrorIs(t, err, ErrExpertNotFound)
+
+	// a double submit that commits between the check and the insert of the
+	// other one trips the unique index
+	assert.NoError(t, db.Model(&models.ExpertAds{}).Create(map[string]interface{}{
+		"ID": 5, "AdsID": 2, "UserID": 1, "ExpertID": 2, "Status": consts.DONE_STATUS,
+	}).Error)
+	var race *models.ExpertReview
+	racer := func(tx *gorm.DB) {
+		if race != nil && tx.Statement.Table == "expert_reviews" {
+			row := *race
+			race = nil
+			assert.NoError(t, tx.Session(&gorm.Session{NewDB: true}).Create(&row).Error)
+		}
+	}
+	assert.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:race", racer))
+	defer db.Callback().Create().Remove("test:race")
+
+	race = &models.ExpertReview{ExpertAdsID: 5, ExpertID: 2, UserID: 1, Rating: 4}
+	_, err = r.Create(ctx, 5, airline, 5, "")
+	assert.ErrorIs(t, err, ErrAlreadyReviewed)
+}
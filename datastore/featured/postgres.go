@@ -0,0 +1,145 @@
+package featured
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrNotOwner         = apperror.Forbidden("not_owner", "you can only feature your own ads")
+	ErrAdNotActive      = apperror.Conflict("ad_not_active", "only active ads can be featured")
+	ErrAlreadyActivated = apperror.Conflict("already_activated", "featured request is already activated")
+)
+
+type FeaturedStorer struct {
+	db *gorm.DB
+}
+
+func NewFeaturedStorer(db *gorm.DB) FeaturedStorer {
+	return FeaturedStorer{db: db}
+}
+
+func (f FeaturedStorer) RequestToFeature(
+	ctx context.Context, adID int, user models.User,
+) (models.FeaturedAd, error) {
+	// get ad
+	var ad models.Ad
+	if err := f.db.WithContext(ctx).First(&ad, adID).Error; err != nil {
+		return models.FeaturedAd{}, err
+	}
+	if ad.UserID != user.ID {
+		return models.FeaturedAd{}, ErrNotOwner
+	}
+	if ad.Status != string(consts.ACTIVE) {
+		return models.FeaturedAd{}, ErrAdNotActive
+	}
+
+	// get or create an unpaid featured request
+	var featuredAd models.FeaturedAd
+	err := f.db.WithContext(ctx).
+		Where("user_id = ? AND ads_id = ? AND status = ?", user.ID, adID, consts.WAIT_FOR_PAYMENT_STATUS).
+		First(&featuredAd).Error
+	if err != gorm.ErrRecordNotFound && err != nil {
+		return models.FeaturedAd{}, err
+	}
+	if featuredAd.ID != 0 {
+		return featuredAd, nil
+	}
+
+	featuredAd = models.FeaturedAd{
+		AdsID:     ad.ID,
+		UserID:    user.ID,
+		Status:    consts.WAIT_FOR_PAYMENT_STATUS,
+		CreatedAt: time.Now(),
+	}
+	if err := f.db.WithContext(ctx).Create(&featuredAd).Error; err != nil {
+		return models.FeaturedAd{}, err
+	}
+
+	return featuredAd, nil
+}
+
+func (f FeaturedStorer) GetByAd(
+	ctx context.Context,
+	adID int,
+	user models.User,
+) (models.FeaturedAd, error) {
+	var featuredAd models.FeaturedAd
+	result := f.db.WithContext(ctx).
+		Where("ads_id = ? AND user_id = ?", adID, user.ID).
+		Order("id DESC").
+		First(&featuredAd)
+
+	return featuredAd, result.Error
+}
+
+// Activate starts the featured window of a paid request. If the ad is
+// already featured the new window is appended to the running one. The
+// request is claimed by its status so a payment verified twice activates
+// it once.
+func (f FeaturedStorer) Activate(ctx context.Context, featuredAdID int) error {
+	return f.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var featuredAd models.FeaturedAd
+		if err := tx.First(&featuredAd, featuredAdID).Error; err != nil {
+			return err
+		}
+		if featuredAd.Status != consts.WAIT_FOR_PAYMENT_STATUS {
+			return ErrAlreadyActivated
+		}
+
+		// purchases of the same ad are chained one after another
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&models.Ad{}, featuredAd.AdsID).Error; err != nil {
+			return err
+		}
+
+		duration, err := f.duration(tx)
+		if err != nil {
+			return err
+		}
+
+		startsAt := time.Now()
+		var running models.FeaturedAd
+		err = tx.Where("ads_id = ? AND status = ? AND ends_at > ?", featuredAd.AdsID, consts.DONE_STATUS, startsAt).
+			Order("ends_at DESC").
+			First(&running).Error
+		if err != gorm.ErrRecordNotFound && err != nil {
+			return err
+		}
+		if running.ID != 0 {
+			startsAt = *running.EndsAt
+		}
+		endsAt := startsAt.Add(duration)
+
+		result := tx.Model(&models.FeaturedAd{}).
+			Where("id = ? AND status = ?", featuredAdID, consts.WAIT_FOR_PAYMENT_STATUS).
+			Updates(map[string]interface{}{
+				"status":    consts.DONE_STATUS,
+				"starts_at": startsAt,
+				"ends_at":   endsAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected == 0 {
+			return ErrAlreadyActivated
+		}
+		return nil
+	})
+}
+
+func (f FeaturedStorer) duration(tx *gorm.DB) (time.Duration, error) {
+	var config models.Configuration
+	err := tx.Where("name = ?", consts.CONFIG_FEATURED_DURATION).First(&config).Error
+	if err == gorm.ErrRecordNotFound {
+		return consts.DEFAULT_FEATURED_DURATION_DAYS * 24 * time.Hour, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(config.Value * float64(24*time.Hour)), nil
+}
@@ -0,0 +1,142 @@
+package featured
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestDatastore(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	cleanup := createData(t, db)
+	defer cleanup()
+
+	f := NewFeaturedStorer(db)
+	testFeaturedStorer_RequestToFeature(t, f)
+	testFeaturedStorer_Activate(t, f)
+}
+
+func testFeaturedStorer_RequestToFeature(t *testing.T, f FeaturedStorer) {
+	ctx := context.Background()
+	owner := models.User{ID: 1, Role: consts.ROLE_AIRLINE}
+	other := models.User{ID: 2, Role: consts.ROLE_AIRLINE}
+
+	_, err := f.RequestToFeature(ctx, 1, other)
+	assert.EqualError(t, err, "you can only feature your own ads")
+
+	_, err = f.RequestToFeature(ctx, 2, owner)
+	assert.EqualError(t, err, "only active ads can be featured")
+
+	first, err := f.RequestToFeature(ctx, 1, owner)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.WAIT_FOR_PAYMENT_STATUS, first.Status)
+
+	// an unpaid request is reused instead of creating a new one
+	second, err := f.RequestToFeature(ctx, 1, owner)
+	assert.NoError(t, err)
+	assert.Equal(t, first.ID, second.ID)
+}
+
+func testFeaturedStorer_Activate(t *testing.T, f FeaturedStorer) {
+	ctx := context.Background()
+	owner := models.User{ID: 1, Role: consts.ROLE_AIRLINE}
+
+	pending, err := f.GetByAd(ctx, 1, owner)
+	assert.NoError(t, err)
+
+	before := time.Now()
+	assert.NoError(t, f.Activate(ctx, int(pending.ID)))
+
+	activated, err := f.GetByAd(ctx, 1, owner)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.DONE_STATUS, activated.Status)
+	assert.WithinDuration(t, before.Add(3*24*time.Hour), *activated.EndsAt, time.Minute)
+
+	assert.Error(t, f.Activate(ctx, int(pending.ID)))
+
+	// a second purchase extends the running window
+	next, err := f.RequestToFeature(ctx, 1, owner)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Activate(ctx, int(next.ID)))
+
+	extended, err := f.GetByAd(ctx, 1, owner)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, *activated.EndsAt, *extended.StartsAt, time.Second)
+	assert.WithinDuration(t, activated.EndsAt.Add(3*24*time.Hour), *extended.EndsAt, time.Second)
+
+	// a payment verified twice at once extends the window once
+	twice, err := f.RequestToFeature(ctx, 1, owner)
+	assert.NoError(t, err)
+	var (
+		wg    sync.WaitGroup
+		start = make(chan struct{})
+		errs  = make([]error, 2)
+	)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			errs[i] = f.Activate(ctx, int(twice.ID))
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+	if errs[0] == nil {
+		assert.ErrorIs(t, errs[1], ErrAlreadyActivated)
+	} else {
+		assert.ErrorIs(t, errs[0], ErrAlreadyActivated)
+		assert.NoError(t, errs[1])
+	}
+
+	last, err := f.GetByAd(ctx, 1, owner)
+	assert.NoError(t, err)
+	assert.Equal(t, twice.ID, last.ID)
+	assert.WithinDuration(t, *extended.EndsAt, *last.StartsAt, time.Second)
+	assert.WithinDuration(t, extended.EndsAt.Add(3*24*time.Hour), *last.EndsAt, time.Second)
+}
+
+func createData(t *testing.T, db *gorm.DB) func() {
+	users := []models.User{
+		{ID: 1, Username: "owner", Password: "owner123", Role: consts.ROLE_AIRLINE},
+		{ID: 2, Username: "other", Password: "other123", Role: consts.ROLE_AIRLINE},
+	}
+	ads := []models.Ad{
+		{ID: 1, UserID: 1, Subject: "Active Ad", Price: 1000, CategoryID: 1, Status: string(consts.ACTIVE)},
+		{ID: 2, UserID: 1, Subject: "Inactive Ad", Price: 2000, CategoryID: 1, Status: string(consts.INACTIVE)},
+	}
+	config := models.Configuration{ID: 1, Name: consts.CONFIG_FEATURED_DURATION, Value: 3}
+
+	if err := db.Create(&models.Category{ID: 1, Name: "small-passenger"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&users).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&ads).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&config).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return func() {
+		db.Exec("DELETE FROM featured_ads")
+		db.Exec("DELETE FROM configuration")
+		db.Exec("DELETE FROM ads")
+		db.Exec("DELETE FROM users")
+		db.Exec("DELETE FROM categories")
+	}
+}
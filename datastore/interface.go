@@ -5,6 +5,7 @@ import (
 	"Airplane-Divar/filter"
 	"Airplane-Divar/models"
 	"context"
+	"time"
 
 	"gorm.io/gorm/clause"
 )
@@ -16,7 +17,68 @@ type (
 		ListFilterByColumn(f *filter.AdsFilter) ([]models.Ad, error)
 		ListFilterSort(f *filter.Filter) ([]models.Ad, error)
 		GetCategoryByName(name string) (models.Category, error)
+		GetCategoryAttributes(categoryID uint) ([]models.CategoryAttribute, error)
 		CreateAd(ad *models.Ad) (models.Ad, error)
+		Export(ctx context.Context, f *filter.AdsFilter, fn func(models.AdExportRow) error) error
+	}
+
+	Category interface {
+		List(ctx context.Context, includeRetired bool) ([]models.Category, error)
+		Create(ctx context.Context, name string, position int, parentID *uint) (models.Category, error)
+		Rename(ctx context.Context, id int, name string) (models.Category, error)
+		Move(ctx context.Context, id int, parentID *uint) (models.Category, error)
+		Reorder(ctx context.Context, ids []uint) error
+		Retire(ctx context.Context, id int, reassignTo int) (models.Category, error)
+		Attributes(ctx context.Context, id int) ([]models.CategoryAttribute, error)
+		CreateAttribute(ctx context.Context, attribute models.CategoryAttribute) (models.CategoryAttribute, error)
+		DeleteAttribute(ctx context.Context, categoryID int, attributeID int) error
+	}
+
+	Offer interface {
+		Create(ctx context.Context, adID int, buyer models.User, price uint64, message string, expiresAt time.Time) (models.Offer, error)
+		List(ctx context.Context, adID int, user models.User) ([]models.Offer, error)
+		Get(ctx context.Context, id int, user models.User) (models.Offer, error)
+		Accept(ctx context.Context, id int, user models.User) (models.Offer, error)
+		Reject(ctx context.Context, id int, user models.User) (models.Offer, error)
+		Counter(ctx context.Context, id int, user models.User, price uint64, message string, expiresAt time.Time) (models.Offer, error)
+		Complete(ctx context.Context, id int, user models.User) (models.Offer, error)
+	}
+
+	Auction interface {
+		Create(ctx context.Context, adID int, user models.User, auction models.Auction) (models.Auction, error)
+		GetByAd(ctx context.Context, adID int) (models.Auction, error)
+		Bids(ctx context.Context, auctionID int) ([]models.Bid, error)
+		PlaceBid(ctx context.Context, auctionID int, user models.User, amount uint64) (models.Auction, error)
+		CloseDue(ctx context.Context, now time.Time) ([]models.Auction, error)
+	}
+
+	Notification interface {
+		Create(ctx context.Context, notification models.Notification) error
+		List(ctx context.Context, userID uint, unreadOnly bool) ([]models.Notification, error)
+		MarkRead(ctx context.Context, id int, userID uint) error
+	}
+
+	Conversation interface {
+		Start(ctx context.Context, adID int, buyer models.User) (models.Conversation, error)
+		Inbox(ctx context.Context, user models.User) ([]models.Conversation, error)
+		ListByAd(ctx context.Context, adID int) ([]models.Conversation, error)
+		Messages(ctx context.Context, id int, user models.User) ([]models.Message, error)
+		Send(ctx context.Context, id int, user models.User, body string, attachment string) (models.Message, error)
+		UnreadCount(ctx context.Context, user models.User) (int64, error)
+	}
+
+	Draft interface {
+		Create(ctx context.Context, userID uint, payload map[string]interface{}) (models.AdDraft, error)
+		Save(ctx context.Context, id int, userID uint, payload map[string]interface{}) (models.AdDraft, error)
+		List(ctx context.Context, userID uint) ([]models.AdDraft, error)
+		Get(ctx context.Context, id int, userID uint) (models.AdDraft, error)
+		Delete(ctx context.Context, id int, userID uint) error
+	}
+
+	Report interface {
+		Create(ctx context.Context, adID int, user models.User, reason string, note string) (models.Report, bool, error)
+		List(ctx context.Context, status consts.ReportStatus) ([]models.Report, error)
+		Resolve(ctx context.Context, id int, admin models.User, outcome consts.ReportStatus) (models.Report, consts.AdStatus, error)
 	}
 
 	Expert interface {
@@ -45,6 +107,8 @@ type (
 			ctx context.Context, expertAdID int,
 			user models.User, body models.UpdateExpertCheckRequest,
 		) (models.ExpertAds, error)
+		Claim(ctx context.Context, requestID int, user models.User) (models.ExpertAds, error)
+		Release(ctx context.Context, requestID int, user models.User) (models.ExpertAds, error)
 		Delete(
 			ctx context.Context,
 			adID int,
@@ -52,6 +116,74 @@ type (
 		) error
 	}
 
+	ExpertAssignment interface {
+		Strategy(ctx context.Context) (int, error)
+		Profile(ctx context.Context, expertID uint) (models.ExpertProfile, error)
+		SaveProfile(ctx context.Context, expertID uint, available bool, categoryIDs []uint) (models.ExpertProfile, error)
+		Candidates(ctx context.Context, requestID int) ([]models.ExpertCandidate, error)
+		Assign(ctx context.Context, requestID int, expertID uint, reassign bool, now time.Time) (models.ExpertAds, error)
+	}
+
+	Inspection interface {
+		CreateTemplate(ctx context.Context, template models.InspectionTemplate) (models.InspectionTemplate, error)
+		ListTemplates(ctx context.Context) ([]models.InspectionTemplate, error)
+		GetTemplate(ctx context.Context, id int) (models.InspectionTemplate, error)
+		UpdateTemplate(ctx context.Context, id int, name string, items []models.InspectionTemplateItem) (models.InspectionTemplate, error)
+		DeleteTemplate(ctx context.Context, id int) error
+		TemplateFor(ctx context.Context, requestID int) (models.InspectionTemplate, error)
+		SaveChecklist(ctx context.Context, requestID int, expertID uint, items []models.InspectionItem) (models.ExpertAds, error)
+	}
+
+	ExpertAttachment interface {
+		Add(ctx context.Context, attachment models.ExpertAttachment) (models.ExpertAttachment, error)
+		List(ctx context.Context, requestID int, user models.User) ([]models.ExpertAttachment, error)
+		Get(ctx context.Context, requestID int, attachmentID int, user models.User) (models.ExpertAttachment, error)
+		Delete(ctx context.Context, requestID int, attachmentID int, expertID uint) (models.ExpertAttachment, error)
+	}
+
+	ExpertCertificate interface {
+		Issue(
+			ctx context.Context,
+			requestID int,
+			sign func(report models.ExpertAds, revision int) (models.ExpertCertificate, error),
+		) (models.ExpertCertificate, error)
+		Report(ctx context.Context, requestID int) (models.ExpertAds, error)
+		List(ctx context.Context, requestID int) ([]models.ExpertCertificate, error)
+	}
+
+	ExpertReview interface {
+		Create(ctx context.Context, requestID int, user models.User, rating int, comment string) (models.ExpertReview, error)
+		List(ctx context.Context, expertID uint, withHidden bool, page int) ([]models.ExpertReview, error)
+		Rating(ctx context.Context, expertID uint) (models.ExpertRating, error)
+		Expert(ctx context.Context, expertID uint) (models.User, error)
+		SetHidden(ctx context.Context, reviewID int, admin models.User, hidden bool) (models.ExpertReview, error)
+	}
+
+	ExpertAppointment interface {
+		CreateSlot(ctx context.Context, slot models.ExpertSlot) (models.ExpertSlot, error)
+		Slots(ctx context.Context, expertID uint, from time.Time) ([]models.ExpertSlot, error)
+		DeleteSlot(ctx context.Context, slotID int, expertID uint) (models.ExpertSlot, error)
+		FreeSlots(ctx context.Context, requestID int, user models.User, now time.Time) ([]models.ExpertSlot, error)
+		Book(ctx context.Context, requestID int, slotID uint, user models.User, now time.Time) (models.Appointment, error)
+		Reschedule(ctx context.Context, requestID int, slotID uint, user models.User, now time.Time) (models.Appointment, error)
+		Cancel(ctx context.Context, requestID int, user models.User, now time.Time) (models.Appointment, error)
+		Appointment(ctx context.Context, requestID int, user models.User) (models.Appointment, error)
+		Appointments(ctx context.Context, userID uint, from time.Time, withCancelled bool) ([]models.Appointment, error)
+	}
+
+	CalendarFeed interface {
+		Rotate(ctx context.Context, userID uint, token string) error
+		Revoke(ctx context.Context, userID uint) error
+		Resolve(ctx context.Context, token string) (models.User, error)
+	}
+
+	SLA interface {
+		Escalate(ctx context.Context, now time.Time) ([]models.SLAPeriod, error)
+		Overdue(ctx context.Context, kind string, now time.Time) ([]models.SLAPeriod, error)
+		Compliance(ctx context.Context, kind string, from time.Time, to time.Time, now time.Time) ([]models.SLACompliance, error)
+		Admins(ctx context.Context) ([]uint, error)
+	}
+
 	Repair interface {
 		RequestToRepairCheck(ctx context.Context, adID int, user models.User) error
 		GetByAd(
@@ -64,6 +196,11 @@ type (
 			requestID int,
 			user models.User,
 		) (models.RepairRequest, error)
+		Summary(
+			ctx context.Context,
+			requestID int,
+			user models.User,
+		) (models.RepairRequest, error)
 		GetAllRepairRequests(
 			ctx context.Context,
 			filterAndCondition clause.AndConditions,
@@ -85,6 +222,16 @@ type (
 		) error
 	}
 
+	Featured interface {
+		RequestToFeature(ctx context.Context, adID int, user models.User) (models.FeaturedAd, error)
+		GetByAd(
+			ctx context.Context,
+			adID int,
+			user models.User,
+		) (models.FeaturedAd, error)
+		Activate(ctx context.Context, featuredAdID int) error
+	}
+
 	User interface {
 		Get(id int) ([]models.User, error)
 		Create(username string, password string, role string) (string, models.User, error)
@@ -106,11 +253,34 @@ type (
 		GetTotalPriceByServices(prices map[string]float64) float64
 	}
 	Bookmark interface {
-		GetAdsByUserID(id int) ([]models.AdResponse, error)
+		GetAdsByUserID(id int, query models.BookmarkListQuery) ([]models.BookmarkedAdResponse, error)
 		AddBookmark(userID, adID int) (models.BookmarksResponse, error)
 		DeleteBookmark(userID, adID int) error
 	}
 
+	BookmarkCollection interface {
+		Create(ctx context.Context, userID uint, name string) (models.BookmarkCollection, error)
+		List(ctx context.Context, userID uint) ([]models.BookmarkCollection, error)
+		Rename(ctx context.Context, id int, userID uint, name string) (models.BookmarkCollection, error)
+		Delete(ctx context.Context, id int, userID uint) error
+		Add(ctx context.Context, id int, userID uint, adID int) error
+		Remove(ctx context.Context, id int, userID uint, adID int) error
+		Annotate(ctx context.Context, userID uint, adID int, note string, tags []string) (models.Bookmarks, error)
+	}
+
+	BookmarkShare interface {
+		Create(ctx context.Context, collectionID int, userID uint, token string, expiresAt time.Time) (models.BookmarkShare, error)
+		List(ctx context.Context, collectionID int, userID uint) ([]models.BookmarkShare, error)
+		Revoke(ctx context.Context, id int, userID uint, now time.Time) (models.BookmarkShare, error)
+		Resolve(ctx context.Context, token string, now time.Time) (models.BookmarkShare, models.BookmarkCollection, error)
+		Ads(ctx context.Context, share models.BookmarkShare, page int) ([]models.BookmarkedAdResponse, error)
+	}
+
+	BookmarkWatch interface {
+		Watchers(ctx context.Context, adID uint) ([]uint, error)
+		SetMuted(ctx context.Context, userID uint, adID int, muted bool) error
+	}
+
 	Logging interface {
 		AddNewLogName(id uint, title string) error
 		FindLogByTitle(title string) models.LogName
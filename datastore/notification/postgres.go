@@ -0,0 +1,47 @@
+package notification
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/models"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrNotificationNotFound = apperror.NotFound("notification_not_found", "notification not found")
+
+type NotificationStorer struct {
+	db *gorm.DB
+}
+
+func NewNotificationStorer(db *gorm.DB) NotificationStorer {
+	return NotificationStorer{db: db}
+}
+
+func (n NotificationStorer) Create(ctx context.Context, notification models.Notification) error {
+	return n.db.WithContext(ctx).Create(&notification).Error
+}
+
+// List returns the notifications of a user, newest first.
+func (n NotificationStorer) List(ctx context.Context, userID uint, unreadOnly bool) ([]models.Notification, error) {
+	notifications := []models.Notification{}
+	builder := n.db.WithContext(ctx).Where("user_id = ?", userID)
+	if unreadOnly {
+		builder = builder.Where("read_at IS NULL")
+	}
+	err := builder.Order("created_at DESC, id DESC").Find(&notifications).Error
+	return notifications, err
+}
+
+func (n NotificationStorer) MarkRead(ctx context.Context, id int, userID uint) error {
+	result := n.db.WithContext(ctx).Model(&models.Notification{}).
+		Where("id = ? AND user_id = ?", id, userID).
+		Update("read_at", gorm.Expr("COALESCE(read_at, ?)", time.Now()))
+	if result.Error != nil {
+		return result.Error
+	} else if result.RowsAffected == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
@@ -0,0 +1,276 @@
+package offer
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrAdNotFound      = apperror.NotFound("ad_not_found", "ad not found")
+	ErrAdNotAvailable  = apperror.Conflict("ad_not_available", "ad is not available for offers")
+	ErrAdInAuction     = apperror.Conflict("ad_in_auction", "ad is sold by auction, place a bid instead")
+	ErrOwnAd           = apperror.BadRequest("own_ad", "you can not make an offer on your own ad")
+	ErrOfferNotFound   = apperror.NotFound("offer_not_found", "offer not found")
+	ErrOfferPending    = apperror.Conflict("offer_pending", "there is already a pending offer on this ad")
+	ErrOfferClosed     = apperror.Conflict("offer_closed", "offer is not pending anymore")
+	ErrOfferExpired    = apperror.Conflict("offer_expired", "offer is expired")
+	ErrOfferNotAllowed = apperror.Forbidden("offer_not_allowed", "only the other party can answer this offer")
+)
+
+type OfferStorer struct {
+	db *gorm.DB
+}
+
+func NewOfferStorer(db *gorm.DB) OfferStorer {
+	return OfferStorer{db: db}
+}
+
+func (o OfferStorer) Create(
+	ctx context.Context, adID int, buyer models.User, price uint64, message string, expiresAt time.Time,
+) (models.Offer, error) {
+	if err := o.expire(ctx); err != nil {
+		return models.Offer{}, err
+	}
+
+	var ad models.Ad
+	if err := o.db.WithContext(ctx).First(&ad, adID).Error; err == gorm.ErrRecordNotFound {
+		return models.Offer{}, ErrAdNotFound
+	} else if err != nil {
+		return models.Offer{}, err
+	}
+	if ad.UserID == buyer.ID {
+		return models.Offer{}, ErrOwnAd
+	}
+	if ad.Status != string(consts.ACTIVE) {
+		return models.Offer{}, ErrAdNotAvailable
+	}
+	if ad.ListingMode == consts.LISTING_AUCTION {
+		return models.Offer{}, ErrAdInAuction
+	}
+
+	// the negotiation goes on through counter offers
+	var pending int64
+	err := o.db.WithContext(ctx).Model(&models.Offer{}).
+		Where("ads_id = ? AND buyer_id = ? AND status = ?", ad.ID, buyer.ID, consts.OFFER_PENDING).
+		Count(&pending).Error
+	if err != nil {
+		return models.Offer{}, err
+	} else if pending > 0 {
+		return models.Offer{}, ErrOfferPending
+	}
+
+	offer := models.Offer{
+		AdsID:      ad.ID,
+		BuyerID:    buyer.ID,
+		SellerID:   ad.UserID,
+		ProposedBy: buyer.ID,
+		Price:      price,
+		Message:    message,
+		Status:     consts.OFFER_PENDING,
+		ExpiresAt:  expiresAt,
+	}
+	if err := o.db.WithContext(ctx).Create(&offer).Error; err != nil {
+		return models.Offer{}, err
+	}
+	return offer, nil
+}
+
+// List returns the offers on an ad the user takes part in, oldest first.
+// The seller sees every thread, a buyer only their own.
+func (o OfferStorer) List(ctx context.Context, adID int, user models.User) ([]models.Offer, error) {
+	if err := o.expire(ctx); err != nil {
+		return nil, err
+	}
+
+	offers := []models.Offer{}
+	builder := o.db.WithContext(ctx).Where("ads_id = ?", adID)
+	if user.Role != consts.ROLE_ADMIN {
+		builder = builder.Where("buyer_id = ? OR seller_id = ?", user.ID, user.ID)
+	}
+	err := builder.Order("created_at, id").Find(&offers).Error
+	return offers, err
+}
+
+func (o OfferStorer) Get(ctx context.Context, id int, user models.User) (models.Offer, error) {
+	if err := o.expire(ctx); err != nil {
+		return models.Offer{}, err
+	}
+	return o.get(ctx, o.db, id, user)
+}
+
+// Accept closes the negotiation of the ad: the offer is accepted, the ad is
+// reserved for the buyer and every other pending offer on it is rejected.
+func (o OfferStorer) Accept(ctx context.Context, id int, user models.User) (models.Offer, error) {
+	var offer models.Offer
+	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		offer, err = o.answer(ctx, tx, id, user, consts.OFFER_ACCEPTED)
+		if err != nil {
+			return err
+		}
+
+		// offers made before the ad went up for auction can't bypass it
+		reserved := tx.Model(&models.Ad{}).
+			Where("id = ? AND status = ? AND listing_mode <> ?", offer.AdsID, consts.ACTIVE, consts.LISTING_AUCTION).
+			Update("status", consts.RESERVED)
+		if reserved.Error != nil {
+			return reserved.Error
+		} else if reserved.RowsAffected == 0 {
+			return ErrAdNotAvailable
+		}
+
+		return tx.Model(&models.Offer{}).
+			Where("ads_id = ? AND status = ? AND id <> ?", offer.AdsID, consts.OFFER_PENDING, offer.ID).
+			Update("status", consts.OFFER_REJECTED).Error
+	})
+	if err != nil {
+		return models.Offer{}, err
+	}
+	return offer, nil
+}
+
+func (o OfferStorer) Reject(ctx context.Context, id int, user models.User) (models.Offer, error) {
+	return o.answer(ctx, o.db, id, user, consts.OFFER_REJECTED)
+}
+
+// Counter answers an offer with a new price; the answered offer is closed
+// and the counter offer waits for the other party.
+func (o OfferStorer) Counter(
+	ctx context.Context, id int, user models.User, price uint64, message string, expiresAt time.Time,
+) (models.Offer, error) {
+	var counter models.Offer
+	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		offer, err := o.answer(ctx, tx, id, user, consts.OFFER_COUNTERED)
+		if err != nil {
+			return err
+		}
+
+		var ad models.Ad
+		if err := tx.First(&ad, offer.AdsID).Error; err != nil {
+			return err
+		}
+		if ad.Status != string(consts.ACTIVE) {
+			return ErrAdNotAvailable
+		}
+		if ad.ListingMode == consts.LISTING_AUCTION {
+			return ErrAdInAuction
+		}
+
+		counter = models.Offer{
+			AdsID:      offer.AdsID,
+			BuyerID:    offer.BuyerID,
+			SellerID:   offer.SellerID,
+			ProposedBy: user.ID,
+			ParentID:   &offer.ID,
+			Price:      price,
+			Message:    message,
+			Status:     consts.OFFER_PENDING,
+			ExpiresAt:  expiresAt,
+		}
+		return tx.Create(&counter).Error
+	})
+	if err != nil {
+		return models.Offer{}, err
+	}
+	return counter, nil
+}
+
+// Complete marks the reserved ad of an accepted offer as sold. Only the
+// seller can complete the deal.
+func (o OfferStorer) Complete(ctx context.Context, id int, user models.User) (models.Offer, error) {
+	var offer models.Offer
+	err := o.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		offer, err = o.get(ctx, tx, id, user)
+		if err != nil {
+			return err
+		}
+		if offer.SellerID != user.ID {
+			return ErrOfferNotAllowed
+		}
+
+		completed := tx.Model(&models.Offer{}).
+			Where("id = ? AND status = ?", offer.ID, consts.OFFER_ACCEPTED).
+			Update("status", consts.OFFER_COMPLETED)
+		if completed.Error != nil {
+			return completed.Error
+		} else if completed.RowsAffected == 0 {
+			return ErrOfferClosed
+		}
+
+		sold := tx.Model(&models.Ad{}).
+			Where("id = ? AND status = ?", offer.AdsID, consts.RESERVED).
+			Update("status", consts.SOLD)
+		if sold.Error != nil {
+			return sold.Error
+		} else if sold.RowsAffected == 0 {
+			return ErrAdNotAvailable
+		}
+
+		offer.Status = consts.OFFER_COMPLETED
+		return nil
+	})
+	if err != nil {
+		return models.Offer{}, err
+	}
+	return offer, nil
+}
+
+// answer moves a pending offer to status on behalf of the party that did not
+// propose it. The status check is part of the update so concurrent answers
+// can not both succeed.
+func (o OfferStorer) answer(
+	ctx context.Context, db *gorm.DB, id int, user models.User, status consts.OfferStatus,
+) (models.Offer, error) {
+	offer, err := o.get(ctx, db, id, user)
+	if err != nil {
+		return models.Offer{}, err
+	}
+	if offer.ProposedBy == user.ID || (offer.BuyerID != user.ID && offer.SellerID != user.ID) {
+		return models.Offer{}, ErrOfferNotAllowed
+	}
+
+	now := time.Now()
+	if offer.Status == consts.OFFER_PENDING && !offer.ExpiresAt.After(now) {
+		return models.Offer{}, ErrOfferExpired
+	}
+
+	result := db.WithContext(ctx).Model(&models.Offer{}).
+		Where("id = ? AND status = ? AND expires_at > ?", offer.ID, consts.OFFER_PENDING, now).
+		Updates(map[string]interface{}{"status": status, "updated_at": now})
+	if result.Error != nil {
+		return models.Offer{}, result.Error
+	} else if result.RowsAffected == 0 {
+		return models.Offer{}, ErrOfferClosed
+	}
+
+	offer.Status = status
+	offer.UpdatedAt = now
+	return offer, nil
+}
+
+func (o OfferStorer) get(ctx context.Context, db *gorm.DB, id int, user models.User) (models.Offer, error) {
+	var offer models.Offer
+	builder := db.WithContext(ctx).Where("id = ?", id)
+	if user.Role != consts.ROLE_ADMIN {
+		builder = builder.Where("buyer_id = ? OR seller_id = ?", user.ID, user.ID)
+	}
+	if err := builder.First(&offer).Error; err == gorm.ErrRecordNotFound {
+		return models.Offer{}, ErrOfferNotFound
+	} else if err != nil {
+		return models.Offer{}, err
+	}
+	return offer, nil
+}
+
+// expire closes the pending offers whose deadline has passed.
+func (o OfferStorer) expire(ctx context.Context) error {
+	return o.db.WithContext(ctx).Model(&models.Offer{}).
+		Where("status = ? AND expires_at <= ?", consts.OFFER_PENDING, time.Now()).
+		Update("status", consts.OFFER_EXPIRED).Error
+}
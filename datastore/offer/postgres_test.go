@@ -0,0 +1,173 @@
+package offer
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var (
+	seller = models.User{ID: 1, Username: "seller", Password: "seller123", Role: consts.ROLE_AIRLINE}
+	buyer  = models.User{ID: 2, Username: "buyer", Password: "buyer123", Role: consts.ROLE_AIRLINE}
+	other  = models.User{ID: 3, Username: "other", Password: "other123", Role: consts.ROLE_AIRLINE}
+)
+
+func TestDatastore(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	cleanup := createData(t, db)
+	defer cleanup()
+
+	o := NewOfferStorer(db)
+	testOfferStorer_Create(t, o)
+	testOfferStorer_Negotiate(t, o, db)
+	testOfferStorer_Expire(t, o, db)
+	testOfferStorer_Auction(t, o, db)
+}
+
+func testOfferStorer_Create(t *testing.T, o OfferStorer) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+
+	_, err := o.Create(ctx, 1, seller, 900, "", expiresAt)
+	assert.ErrorIs(t, err, ErrOwnAd)
+
+	_, err = o.Create(ctx, 2, buyer, 900, "", expiresAt)
+	assert.ErrorIs(t, err, ErrAdNotAvailable)
+
+	_, err = o.Create(ctx, 42, buyer, 900, "", expiresAt)
+	assert.ErrorIs(t, err, ErrAdNotFound)
+
+	created, err := o.Create(ctx, 1, buyer, 900, "is it still available?", expiresAt)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.OFFER_PENDING, created.Status)
+	assert.Equal(t, seller.ID, created.SellerID)
+
+	_, err = o.Create(ctx, 1, buyer, 950, "", expiresAt)
+	assert.ErrorIs(t, err, ErrOfferPending)
+}
+
+func testOfferStorer_Negotiate(t *testing.T, o OfferStorer, db *gorm.DB) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+
+	competing, err := o.Create(ctx, 1, other, 800, "", expiresAt)
+	assert.NoError(t, err)
+
+	// the one who made an offer can't answer it
+	_, err = o.Accept(ctx, 1, buyer)
+	assert.ErrorIs(t, err, ErrOfferNotAllowed)
+
+	counter, err := o.Counter(ctx, 1, seller, 1000, "lowest I can go", expiresAt)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1), *counter.ParentID)
+	assert.Equal(t, seller.ID, counter.ProposedBy)
+
+	_, err = o.Accept(ctx, 1, seller)
+	assert.ErrorIs(t, err, ErrOfferClosed)
+
+	accepted, err := o.Accept(ctx, int(counter.ID), buyer)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.OFFER_ACCEPTED, accepted.Status)
+
+	var ad models.Ad
+	db.First(&ad, 1)
+	assert.Equal(t, string(consts.RESERVED), ad.Status)
+
+	rejected, err := o.Get(ctx, int(competing.ID), other)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.OFFER_REJECTED, rejected.Status)
+
+	// buyers only see their own thread
+	thread, err := o.List(ctx, 1, buyer)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(thread))
+	all, err := o.List(ctx, 1, seller)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(all))
+
+	_, err = o.Get(ctx, int(counter.ID), other)
+	assert.ErrorIs(t, err, ErrOfferNotFound)
+
+	_, err = o.Complete(ctx, int(counter.ID), buyer)
+	assert.ErrorIs(t, err, ErrOfferNotAllowed)
+
+	completed, err := o.Complete(ctx, int(counter.ID), seller)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.OFFER_COMPLETED, completed.Status)
+	db.First(&ad, 1)
+	assert.Equal(t, string(consts.SOLD), ad.Status)
+}
+
+func testOfferStorer_Expire(t *testing.T, o OfferStorer, db *gorm.DB) {
+	ctx := context.Background()
+
+	db.Model(&models.Ad{}).Where("id = ?", 2).Update("status", consts.ACTIVE)
+	created, err := o.Create(ctx, 2, buyer, 500, "", time.Now().Add(time.Hour))
+	assert.NoError(t, err)
+	db.Model(&models.Offer{}).Where("id = ?", created.ID).Update("expires_at", time.Now().Add(-time.Minute))
+
+	_, err = o.Accept(ctx, int(created.ID), seller)
+	assert.ErrorIs(t, err, ErrOfferExpired)
+
+	expired, err := o.Get(ctx, int(created.ID), seller)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.OFFER_EXPIRED, expired.Status)
+}
+
+func testOfferStorer_Auction(t *testing.T, o OfferStorer, db *gorm.DB) {
+	ctx := context.Background()
+	expiresAt := time.Now().Add(time.Hour)
+
+	db.Exec("DELETE FROM offers")
+	pending, err := o.Create(ctx, 2, buyer, 500, "", expiresAt)
+	assert.NoError(t, err)
+
+	// the ad went up for auction after the offer was made
+	db.Model(&models.Ad{}).Where("id = ?", 2).Update("listing_mode", consts.LISTING_AUCTION)
+
+	_, err = o.Create(ctx, 2, other, 600, "", expiresAt)
+	assert.ErrorIs(t, err, ErrAdInAuction)
+	_, err = o.Counter(ctx, int(pending.ID), seller, 700, "", expiresAt)
+	assert.ErrorIs(t, err, ErrAdInAuction)
+	_, err = o.Accept(ctx, int(pending.ID), seller)
+	assert.ErrorIs(t, err, ErrAdNotAvailable)
+
+	var ad models.Ad
+	db.First(&ad, 2)
+	assert.Equal(t, string(consts.ACTIVE), ad.Status)
+}
+
+func createData(t *testing.T, db *gorm.DB) func() {
+	ads := []models.Ad{
+		{ID: 1, UserID: seller.ID, Subject: "Active Ad", Price: 1200, CategoryID: 1, Status: string(consts.ACTIVE)},
+		{ID: 2, UserID: seller.ID, Subject: "Inactive Ad", Price: 600, CategoryID: 1, Status: string(consts.INACTIVE)},
+	}
+
+	if err := db.Create([]models.User{seller, buyer, other}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Category{ID: 1, Name: "small-passenger"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&ads).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return func() {
+		db.Exec("DELETE FROM offers")
+		db.Exec("DELETE FROM ads")
+		db.Exec("DELETE FROM categories")
+		db.Exec("DELETE FROM users")
+	}
+}
@@ -1,16 +1,28 @@
 package repair
 
 import (
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore/sla"
 	"Airplane-Divar/models"
 	"Airplane-Divar/utils"
 	"context"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+var (
+	ErrAlreadyRepaired  = apperror.Conflict("already_repaired", "this had been already repaired by Matin")
+	ErrAlreadyRequested = apperror.Conflict("already_requested", "you had been requested for repairing")
+	ErrStatusNotAllowed = apperror.Forbidden("status_not_allowed", "not allowed")
+	ErrRequestNotFound  = apperror.NotFound("repair_request_not_found", "repair request does not exist")
+	ErrStatusLocked     = apperror.Conflict("status_locked", "you can't change the status")
+	ErrUpdateConflict   = apperror.Conflict("update_conflict", "the repair request was changed by someone else, try again")
+)
+
 type RepairStorer struct {
 	db *gorm.DB
 }
@@ -34,9 +46,12 @@ func (e RepairStorer) GetByAd(
 	} else if user.Role == consts.ROLE_MATIN {
 		query.Where("repair_request.status != ?", consts.WAIT_FOR_PAYMENT_STATUS)
 	}
-	result := query.First(&repairRequest)
+	err := query.First(&repairRequest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repairRequest, ErrRequestNotFound
+	}
 
-	return repairRequest, result.Error
+	return repairRequest, err
 }
 
 func (e RepairStorer) Get(
@@ -54,9 +69,37 @@ func (e RepairStorer) Get(
 	} else if user.Role == consts.ROLE_MATIN {
 		query.Where("repair_request.status != ?", consts.WAIT_FOR_PAYMENT_STATUS)
 	}
-	result := query.First(&repairRequest)
+	err := query.First(&repairRequest).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repairRequest, ErrRequestNotFound
+	}
 
-	return repairRequest, result.Error
+	return repairRequest, err
+}
+
+// Summary loads a repair request the user can see, with the same rules as
+// Get, along with the whole ad and the user who asked for it.
+func (e RepairStorer) Summary(
+	ctx context.Context,
+	requestID int,
+	user models.User,
+) (models.RepairRequest, error) {
+	if _, err := e.Get(ctx, requestID, user); err != nil {
+		return models.RepairRequest{}, err
+	}
+
+	var repairRequest models.RepairRequest
+	err := e.db.WithContext(ctx).
+		Preload("Ads").
+		Preload("Ads.Category").
+		Preload("Ads.Attributes").
+		Preload("Ads.Attributes.Attribute").
+		Preload("User").
+		First(&repairRequest, requestID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return repairRequest, ErrRequestNotFound
+	}
+	return repairRequest, err
 }
 
 func (e RepairStorer) RequestToRepairCheck(
@@ -68,7 +111,7 @@ func (e RepairStorer) RequestToRepairCheck(
 		return err
 	}
 	if ad.RepairCheck {
-		return errors.New("this had been already repaired by Matin")
+		return ErrAlreadyRepaired
 	}
 
 	// get or create repair request
@@ -82,7 +125,7 @@ func (e RepairStorer) RequestToRepairCheck(
 
 	if repairRequest.ID != 0 {
 		if repairRequest.Status != consts.MATIN_PENDING_STATUS {
-			return errors.New("you had been requested for repairing")
+			return ErrAlreadyRequested
 		}
 		return nil
 	}
@@ -132,10 +175,10 @@ func (e RepairStorer) GetAllRepairRequests(
 func (e RepairStorer) Update(
 	ctx context.Context, repairRequestID int, upadtedColumn map[string]interface{},
 ) error {
-	err := e.db.Model(&models.RepairRequest{}).
-		Where("id = ?", repairRequestID).
-		Updates(upadtedColumn).Error
-
+	var repairRequest models.RepairRequest
+	_, err := e.transition(ctx, &repairRequest, func(tx *gorm.DB) *gorm.DB {
+		return tx.Where("id = ?", repairRequestID).Updates(upadtedColumn)
+	})
 	return err
 }
 
@@ -147,34 +190,59 @@ func (e RepairStorer) UpdateByUser(
 	updatedMap := make(map[string]interface{})
 
 	if user.Role != consts.ROLE_MATIN {
-		return tmpRepairRequest, errors.New("not allowed")
+		return tmpRepairRequest, ErrStatusNotAllowed
 	}
 
 	if body.Status != "" {
 		if body.Status == consts.WAIT_FOR_PAYMENT_STATUS {
-			return tmpRepairRequest, errors.New("not allowed")
+			return tmpRepairRequest, ErrStatusNotAllowed
 		}
 		updatedMap["status"] = body.Status
+		updatedMap["matin_id"] = user.ID
 	}
 
 	var repairRequest models.RepairRequest
 	err := e.db.WithContext(ctx).First(&repairRequest, repairRequestID).Error
-	if err != nil {
-		return repairRequest, errors.New("repair request does not exist")
+	if err != nil || repairRequest.Status == consts.WAIT_FOR_PAYMENT_STATUS {
+		return models.RepairRequest{}, ErrRequestNotFound
 	} else if repairRequest.Status == consts.DONE_STATUS && repairRequest.Status != body.Status {
-		return repairRequest, errors.New("you can't change the status")
+		return repairRequest, ErrStatusLocked
 	}
 
-	result := e.db.WithContext(ctx).
-		Clauses(clause.Returning{}).
-		Model(&tmpRepairRequest).
-		Where(
-			"id = ? AND status != ?",
-			repairRequestID, consts.WAIT_FOR_PAYMENT_STATUS,
-		).
-		Updates(updatedMap)
+	// the update only applies to the status read above, if another user
+	// changed it in between this one loses instead of overwriting it
+	result, err := e.transition(ctx, &tmpRepairRequest, func(tx *gorm.DB) *gorm.DB {
+		return tx.
+			Where(
+				"id = ? AND status = ?",
+				repairRequestID, repairRequest.Status,
+			).
+			Updates(updatedMap)
+	})
+	if err != nil {
+		return tmpRepairRequest, err
+	}
+	if result.RowsAffected == 0 {
+		return models.RepairRequest{}, ErrUpdateConflict
+	}
 
-	return tmpRepairRequest, result.Error
+	return tmpRepairRequest, nil
+}
+
+// transition runs a status update returning the request into repairRequest
+// and moves its SLA to the new status in the same transaction.
+func (e RepairStorer) transition(
+	ctx context.Context, repairRequest *models.RepairRequest, update func(tx *gorm.DB) *gorm.DB,
+) (*gorm.DB, error) {
+	var result *gorm.DB
+	err := e.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result = update(tx.Clauses(clause.Returning{}).Model(repairRequest))
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		return sla.Transition(tx, consts.SLA_REPAIR, repairRequest.ID, repairRequest.Status, repairRequest.MatinID, time.Now())
+	})
+	return result, err
 }
 
 func (e RepairStorer) Delete(
@@ -191,7 +259,7 @@ func (e RepairStorer) Delete(
 	if result.Error != nil {
 		return result.Error
 	} else if result.RowsAffected == 0 {
-		return errors.New("repair request not found")
+		return ErrRequestNotFound
 	}
 
 	return nil
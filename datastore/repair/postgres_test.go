@@ -0,0 +1,125 @@
+package repair
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepairStorer_UpdateByUser(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	const matins = 30
+	users := []models.User{{ID: 1, Username: "airline", Password: "-", Token: "-", Role: consts.ROLE_AIRLINE, IsActive: true}}
+	for i := 0; i < matins; i++ {
+		users = append(users, models.User{
+			ID: uint(100 + i), Username: fmt.Sprintf("matin%d", i), Password: "-", Token: "-",
+			Role: consts.ROLE_MATIN, IsActive: true,
+		})
+	}
+	assert.NoError(t, db.Create(&users).Error)
+	assert.NoError(t, db.Create(&models.Category{ID: 1, Name: "Jet"}).Error)
+	assert.NoError(t, db.Create(&models.Ad{ID: 1, UserID: 1, Subject: "jet", Price: 100, CategoryID: 1}).Error)
+	for id, status := range map[int]consts.Status{1: consts.MATIN_PENDING_STATUS, 2: consts.WAIT_FOR_PAYMENT_STATUS} {
+		err := db.Model(&models.RepairRequest{}).Create(map[string]interface{}{
+			"ID": id, "AdsID": 1, "UserID": 1, "Status": status, "CreatedAt": time.Now(),
+		}).Error
+		assert.NoError(t, err)
+	}
+
+	ctx := context.Background()
+	r := NewRepairStorer(db)
+
+	// half of them finish the repair while the others start it, once it is
+	// done nobody can move it back
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		start = make(chan struct{})
+		done  int
+	)
+	for i, user := range users[1:] {
+		status := consts.IN_PROGRESS_STATUS
+		if i%2 == 0 {
+			status = consts.DONE_STATUS
+		}
+		wg.Add(1)
+		go func(user models.User, status consts.Status) {
+			defer wg.Done()
+			<-start
+			_, err := r.UpdateByUser(ctx, 1, user, models.UpdateRepairRequest{Status: status})
+			mu.Lock()
+			defer mu.Unlock()
+			if err == nil && status == consts.DONE_STATUS {
+				done++
+			} else if err != nil && !errors.Is(err, ErrUpdateConflict) && !errors.Is(err, ErrStatusLocked) {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}(user, status)
+	}
+	close(start)
+	wg.Wait()
+
+	var repairRequest models.RepairRequest
+	assert.NoError(t, db.First(&repairRequest, 1).Error)
+	if done > 0 {
+		assert.Equal(t, consts.DONE_STATUS, repairRequest.Status)
+	}
+
+	_, err = r.UpdateByUser(ctx, 2, users[1], models.UpdateRepairRequest{Status: consts.IN_PROGRESS_STATUS})
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+}
+
+func TestRepairStorer_Summary(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	users := []models.User{
+		{ID: 1, Username: "airline", Password: "-", Token: "-", Role: consts.ROLE_AIRLINE, IsActive: true},
+		{ID: 2, Username: "other", Password: "-", Token: "-", Role: consts.ROLE_AIRLINE, IsActive: true},
+		{ID: 3, Username: "matin", Password: "-", Token: "-", Role: consts.ROLE_MATIN, IsActive: true},
+	}
+	assert.NoError(t, db.Create(&users).Error)
+	assert.NoError(t, db.Create(&models.Category{ID: 1, Name: "Jet"}).Error)
+	assert.NoError(t, db.Create(&models.Ad{ID: 1, UserID: 1, Subject: "jet", Price: 100, CategoryID: 1, AirplaneModel: "A320"}).Error)
+	assert.NoError(t, db.Create(&models.CategoryAttribute{ID: 1, CategoryID: 1, Name: "registration", Type: "string"}).Error)
+	assert.NoError(t, db.Create(&models.AdAttribute{AdsID: 1, AttributeID: 1, Value: "EP-ABC"}).Error)
+	for id, status := range map[int]consts.Status{1: consts.DONE_STATUS, 2: consts.WAIT_FOR_PAYMENT_STATUS} {
+		err := db.Model(&models.RepairRequest{}).Create(map[string]interface{}{
+			"ID": id, "AdsID": 1, "UserID": 1, "Status": status, "CreatedAt": time.Now(),
+		}).Error
+		assert.NoError(t, err)
+	}
+
+	ctx := context.Background()
+	s := NewRepairStorer(db)
+
+	summary, err := s.Summary(ctx, 1, users[0])
+	assert.NoError(t, err)
+	assert.Equal(t, "A320", summary.Ads.AirplaneModel)
+	assert.Equal(t, "Jet", summary.Ads.Category.Name)
+	assert.Equal(t, "registration", summary.Ads.Attributes[0].Attribute.Name)
+	assert.Equal(t, "airline", summary.User.Username)
+
+	_, err = s.Summary(ctx, 1, users[2])
+	assert.NoError(t, err)
+	_, err = s.Summary(ctx, 1, users[1])
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+	_, err = s.Summary(ctx, 2, users[2])
+	assert.ErrorIs(t, err, ErrRequestNotFound)
+}
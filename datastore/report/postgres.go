@@ -0,0 +1,223 @@
+package report
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAdNotFound      = apperror.NotFound("ad_not_found", "ad not found")
+	ErrOwnAd           = apperror.BadRequest("own_ad", "you can not report your own ad")
+	ErrAlreadyReported = apperror.Conflict("already_reported", "you have already reported this ad")
+	ErrReportNotFound  = apperror.NotFound("report_not_found", "report not found")
+	ErrReportResolved  = apperror.Conflict("report_resolved", "report is already resolved")
+	ErrInvalidOutcome  = apperror.BadRequest("invalid_outcome", "outcome should be Dismissed or Upheld")
+)
+
+type ReportStorer struct {
+	db *gorm.DB
+}
+
+func NewReportStorer(db *gorm.DB) ReportStorer {
+	return ReportStorer{db: db}
+}
+
+// Create files a report of the user on an ad. When the distinct open reporters
+// of an active ad reach the configured threshold the ad is hidden, which is
+// reported back with hidden. The ad row is locked so concurrent reports are
+// counted one after the other.
+func (r ReportStorer) Create(ctx context.Context, adID int, user models.User, reason string, note string) (report models.Report, hidden bool, err error) {
+	threshold, err := r.threshold(ctx)
+	if err != nil {
+		return models.Report{}, false, err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ad models.Ad
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ad, adID).Error; err == gorm.ErrRecordNotFound {
+			return ErrAdNotFound
+		} else if err != nil {
+			return err
+		}
+		if ad.UserID == user.ID {
+			return ErrOwnAd
+		}
+
+		var count int64
+		if err := tx.Model(&models.Report{}).
+			Where("ads_id = ? AND user_id = ?", ad.ID, user.ID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrAlreadyReported
+		}
+
+		report = models.Report{
+			AdsID:     ad.ID,
+			UserID:    user.ID,
+			Reason:    reason,
+			Note:      note,
+			Status:    consts.REPORT_OPEN,
+			CreatedAt: time.Now(),
+		}
+		if err := tx.Create(&report).Error; err != nil {
+			return err
+		}
+
+		open, err := openReporters(tx, ad.ID)
+		if err != nil {
+			return err
+		}
+		if open < threshold {
+			return nil
+		}
+
+		// only an active ad is hidden, so a concurrent report hides it once
+		result := tx.Model(&models.Ad{}).
+			Where("id = ? AND status = ?", ad.ID, string(consts.ACTIVE)).
+			Update("status", string(consts.HIDDEN))
+		if result.Error != nil {
+			return result.Error
+		}
+		hidden = result.RowsAffected > 0
+		return nil
+	})
+	if duplicate(r.db, err) {
+		return models.Report{}, false, ErrAlreadyReported
+	} else if err != nil {
+		return models.Report{}, false, err
+	}
+	return report, hidden, nil
+}
+
+// List is the triage queue, the oldest report first. An empty status lists
+// every report.
+func (r ReportStorer) List(ctx context.Context, status consts.ReportStatus) ([]models.Report, error) {
+	reports := []models.Report{}
+	query := r.db.WithContext(ctx)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("created_at, id").Find(&reports).Error
+	return reports, err
+}
+
+// Resolve records the outcome of a report. Upholding a report deactivates the
+// ad and resolves every open report on it, the status the ad had before is
+// reported back with deactivated; dismissing one brings a hidden ad back once
+// it is under the threshold again.
+func (r ReportStorer) Resolve(
+	ctx context.Context, id int, admin models.User, outcome consts.ReportStatus,
+) (report models.Report, deactivated consts.AdStatus, err error) {
+	if outcome != consts.REPORT_DISMISSED && outcome != consts.REPORT_UPHELD {
+		return models.Report{}, "", ErrInvalidOutcome
+	}
+	threshold, err := r.threshold(ctx)
+	if err != nil {
+		return models.Report{}, "", err
+	}
+
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&report, id).Error; err == gorm.ErrRecordNotFound {
+			return ErrReportNotFound
+		} else if err != nil {
+			return err
+		}
+		if report.Status != consts.REPORT_OPEN {
+			return ErrReportResolved
+		}
+
+		now := time.Now()
+		resolve := tx.Model(&models.Report{}).Where("status = ?", consts.REPORT_OPEN)
+		if outcome == consts.REPORT_UPHELD {
+			resolve = resolve.Where("ads_id = ?", report.AdsID)
+		} else {
+			resolve = resolve.Where("id = ?", report.ID)
+		}
+		result := resolve.Updates(map[string]interface{}{
+			"status":      outcome,
+			"resolved_by": admin.ID,
+			"resolved_at": now,
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrReportResolved
+		}
+
+		if outcome == consts.REPORT_UPHELD {
+			var ad models.Ad
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ad, report.AdsID).Error; err != nil {
+				return err
+			}
+			if ad.Status != string(consts.INACTIVE) {
+				deactivated = consts.AdStatus(ad.Status)
+				if err := tx.Model(&ad).Update("status", string(consts.INACTIVE)).Error; err != nil {
+					return err
+				}
+			}
+		} else {
+			open, err := openReporters(tx, report.AdsID)
+			if err != nil {
+				return err
+			}
+			if open < threshold {
+				if err := tx.Model(&models.Ad{}).
+					Where("id = ? AND status = ?", report.AdsID, string(consts.HIDDEN)).
+					Update("status", string(consts.ACTIVE)).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return tx.First(&report, report.ID).Error
+	})
+	if err != nil {
+		return models.Report{}, "", err
+	}
+	return report, deactivated, nil
+}
+
+func (r ReportStorer) threshold(ctx context.Context) (int64, error) {
+	var config models.Configuration
+	err := r.db.WithContext(ctx).
+		Where("name = ?", consts.CONFIG_REPORT_THRESHOLD).
+		First(&config).Error
+	if err == gorm.ErrRecordNotFound {
+		return consts.DEFAULT_REPORT_THRESHOLD, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return int64(config.Value), nil
+}
+
+// duplicate tells if err is the violation of a unique index, the one on
+// reports keeps a user to one report per ad.
+func duplicate(db *gorm.DB, err error) bool {
+	if err == nil {
+		return false
+	}
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+func openReporters(tx *gorm.DB, adID uint) (int64, error) {
+	var count int64
+	err := tx.Model(&models.Report{}).
+		Where("ads_id = ? AND status = ?", adID, consts.REPORT_OPEN).
+		Distinct("user_id").
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,171 @@
+package report
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+var (
+	seller = models.User{ID: 1, Username: "seller", Password: "seller123", Role: consts.ROLE_AIRLINE}
+	first  = models.User{ID: 2, Username: "first", Password: "first123", Role: consts.ROLE_AIRLINE}
+	second = models.User{ID: 3, Username: "second", Password: "second123", Role: consts.ROLE_AIRLINE}
+	admin  = models.User{ID: 4, Username: "admin", Password: "admin123", Role: consts.ROLE_ADMIN}
+)
+
+func TestDatastore(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	cleanup := createData(t, db)
+	defer cleanup()
+
+	r := NewReportStorer(db)
+	testReportStorer_Create(t, r)
+	testReportStorer_DoubleSubmit(t, r, db)
+	testReportStorer_AutoHide(t, r, db)
+	testReportStorer_Dismiss(t, r, db)
+	testReportStorer_Uphold(t, r, db)
+}
+
+func testReportStorer_Create(t *testing.T, r ReportStorer) {
+	ctx := context.Background()
+
+	_, _, err := r.Create(ctx, 1, seller, consts.REPORT_FRAUD, "")
+	assert.ErrorIs(t, err, ErrOwnAd)
+
+	_, _, err = r.Create(ctx, 42, first, consts.REPORT_FRAUD, "")
+	assert.ErrorIs(t, err, ErrAdNotFound)
+
+	report, hidden, err := r.Create(ctx, 1, first, consts.REPORT_FRAUD, "asks for a wire transfer upfront")
+	assert.NoError(t, err)
+	assert.False(t, hidden)
+	assert.Equal(t, consts.REPORT_OPEN, report.Status)
+
+	// a user can not push the ad over the threshold alone
+	_, _, err = r.Create(ctx, 1, first, consts.REPORT_DUPLICATE, "")
+	assert.ErrorIs(t, err, ErrAlreadyReported)
+}
+
+func testReportStorer_DoubleSubmit(t *testing.T, r ReportStorer, db *gorm.DB) {
+	// a report that commits between the check and the insert of another one
+	// trips the unique index
+	var race *models.Report
+	racer := func(tx *gorm.DB) {
+		if race != nil && tx.Statement.Table == "reports" {
+			row := *race
+			race = nil
+			assert.NoError(t, tx.Session(&gorm.Session{NewDB: true}).Create(&row).Error)
+		}
+	}
+	assert.NoError(t, db.Callback().Create().Before("gorm:create").Register("test:race", racer))
+	defer db.Callback().Create().Remove("test:race")
+
+	race = &models.Report{AdsID: 2, UserID: second.ID, Reason: consts.REPORT_FRAUD, Status: consts.REPORT_OPEN}
+	_, _, err := r.Create(context.Background(), 2, second, consts.REPORT_FRAUD, "")
+	assert.ErrorIs(t, err, ErrAlreadyReported)
+
+	var count int64
+	db.Model(&models.Report{}).Where("ads_id = ?", 2).Count(&count)
+	assert.Equal(t, int64(0), count)
+}
+
+func testReportStorer_AutoHide(t *testing.T, r ReportStorer, db *gorm.DB) {
+	_, hidden, err := r.Create(context.Background(), 1, second, consts.REPORT_OFFENSIVE, "")
+	assert.NoError(t, err)
+	assert.True(t, hidden)
+
+	var ad models.Ad
+	db.First(&ad, 1)
+	assert.Equal(t, string(consts.HIDDEN), ad.Status)
+
+	reports, err := r.List(context.Background(), consts.REPORT_OPEN)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(reports))
+}
+
+func testReportStorer_Dismiss(t *testing.T, r ReportStorer, db *gorm.DB) {
+	ctx := context.Background()
+
+	_, _, err := r.Resolve(ctx, 1, admin, consts.REPORT_OPEN)
+	assert.ErrorIs(t, err, ErrInvalidOutcome)
+
+	report, deactivated, err := r.Resolve(ctx, 1, admin, consts.REPORT_DISMISSED)
+	assert.NoError(t, err)
+	assert.Empty(t, deactivated)
+	assert.Equal(t, consts.REPORT_DISMISSED, report.Status)
+	if assert.NotNil(t, report.ResolvedBy) {
+		assert.Equal(t, admin.ID, *report.ResolvedBy)
+	}
+
+	// back under the threshold, the ad is visible again
+	var ad models.Ad
+	db.First(&ad, 1)
+	assert.Equal(t, string(consts.ACTIVE), ad.Status)
+
+	_, _, err = r.Resolve(ctx, 1, admin, consts.REPORT_UPHELD)
+	assert.ErrorIs(t, err, ErrReportResolved)
+}
+
+func testReportStorer_Uphold(t *testing.T, r ReportStorer, db *gorm.DB) {
+	ctx := context.Background()
+
+	_, _, err := r.Create(ctx, 2, first, consts.REPORT_WRONG_CATEGORY, "")
+	assert.NoError(t, err)
+
+	report, deactivated, err := r.Resolve(ctx, 2, admin, consts.REPORT_UPHELD)
+	assert.NoError(t, err)
+	assert.Equal(t, consts.REPORT_UPHELD, report.Status)
+	assert.Equal(t, consts.ACTIVE, deactivated)
+
+	var ad models.Ad
+	db.First(&ad, 1)
+	assert.Equal(t, string(consts.INACTIVE), ad.Status)
+
+	// every open report on the ad is resolved with it
+	open, err := r.List(ctx, consts.REPORT_OPEN)
+	assert.NoError(t, err)
+	if assert.Equal(t, 1, len(open)) {
+		assert.Equal(t, uint(2), open[0].AdsID)
+	}
+
+	all, err := r.List(ctx, "")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(all))
+}
+
+func createData(t *testing.T, db *gorm.DB) func() {
+	if err := db.Create([]models.User{seller, first, second, admin}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&models.Category{ID: 1, Name: "small-passenger"}).Error; err != nil {
+		t.Fatal(err)
+	}
+	ads := []models.Ad{
+		{ID: 1, UserID: seller.ID, Subject: "Cessna 172", Price: 1000, CategoryID: 1, Status: string(consts.ACTIVE)},
+		{ID: 2, UserID: seller.ID, Subject: "Piper PA-28", Price: 900, CategoryID: 1, Status: string(consts.ACTIVE)},
+	}
+	if err := db.Create(&ads).Error; err != nil {
+		t.Fatal(err)
+	}
+	config := models.Configuration{ID: 1, Name: consts.CONFIG_REPORT_THRESHOLD, Value: 2}
+	if err := db.Create(&config).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	return func() {
+		db.Exec("DELETE FROM reports")
+		db.Exec("DELETE FROM configurations")
+		db.Exec("DELETE FROM ads")
+		db.Exec("DELETE FROM categories")
+		db.Exec("DELETE FROM users")
+	}
+}
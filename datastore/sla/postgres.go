@@ -0,0 +1,216 @@
+package sla
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrUnknownKind = apperror.InvalidParameter("kind")
+
+// durations are the configurations holding the SLA of each status of each
+// kind of request, in hours, with their defaults.
+var durations = map[string]map[consts.Status]struct {
+	config   string
+	fallback float64
+}{
+	consts.SLA_EXPERT: {
+		consts.EXPERT_PENDING_STATUS: {consts.CONFIG_SLA_EXPERT_PENDING, consts.DEFAULT_SLA_EXPERT_PENDING_HOURS},
+		consts.IN_PROGRESS_STATUS:    {consts.CONFIG_SLA_EXPERT_IN_PROGRESS, consts.DEFAULT_SLA_EXPERT_IN_PROGRESS_HOURS},
+	},
+	consts.SLA_REPAIR: {
+		consts.MATIN_PENDING_STATUS: {consts.CONFIG_SLA_REPAIR_PENDING, consts.DEFAULT_SLA_REPAIR_PENDING_HOURS},
+		consts.IN_PROGRESS_STATUS:   {consts.CONFIG_SLA_REPAIR_IN_PROGRESS, consts.DEFAULT_SLA_REPAIR_IN_PROGRESS_HOURS},
+	},
+}
+
+var tables = map[string]string{
+	consts.SLA_EXPERT: models.ExpertAds{}.TableName(),
+	consts.SLA_REPAIR: models.RepairRequest{}.TableName(),
+}
+
+// Transition records that a request moved to status. It ends the period of
+// the previous status and starts one for the new status with its due date,
+// statuses without an SLA, like done, only end the previous one. Moving to
+// the status the request is already in keeps its clock running. It should
+// run in the transaction that changes the status.
+func Transition(tx *gorm.DB, kind string, requestID uint, status consts.Status, assigneeID uint, now time.Time) error {
+	var open models.SLAPeriod
+	err := tx.Where("kind = ? AND request_id = ? AND ended_at IS NULL", kind, requestID).First(&open).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if err == nil {
+		if open.Status == status {
+			return Assign(tx, kind, requestID, assigneeID)
+		}
+		if err := tx.Model(&open).Update("ended_at", now).Error; err != nil {
+			return err
+		}
+	}
+
+	sla, ok := durations[kind][status]
+	if !ok {
+		return nil
+	}
+	hours, err := configured(tx, sla.config, sla.fallback)
+	if err != nil {
+		return err
+	}
+
+	var adsID uint
+	err = tx.Table(tables[kind]).Select("ads_id").Where("id = ?", requestID).Scan(&adsID).Error
+	if err != nil {
+		return err
+	}
+
+	period := models.SLAPeriod{
+		Kind:       kind,
+		RequestID:  requestID,
+		AdsID:      adsID,
+		Status:     status,
+		AssigneeID: assignee(assigneeID),
+		StartedAt:  now,
+	}
+	if hours > 0 {
+		due := now.Add(time.Duration(hours * float64(time.Hour)))
+		period.DueAt = &due
+	}
+	return tx.Create(&period).Error
+}
+
+// Assign gives the current period of a request to another assignee, 0 when
+// nobody has it anymore.
+func Assign(tx *gorm.DB, kind string, requestID uint, assigneeID uint) error {
+	return tx.Model(&models.SLAPeriod{}).
+		Where("kind = ? AND request_id = ? AND ended_at IS NULL", kind, requestID).
+		Update("assignee_id", assignee(assigneeID)).Error
+}
+
+type SLAStorer struct {
+	db *gorm.DB
+}
+
+func NewSLAStorer(db *gorm.DB) SLAStorer {
+	return SLAStorer{db: db}
+}
+
+// Escalate flags the periods that are past due and weren't escalated yet and
+// returns them. The check and the flag are one statement, so a period is
+// escalated once even when several checkers run.
+func (s SLAStorer) Escalate(ctx context.Context, now time.Time) ([]models.SLAPeriod, error) {
+	var periods []models.SLAPeriod
+	err := s.db.WithContext(ctx).
+		Model(&periods).
+		Clauses(clause.Returning{}).
+		Where("ended_at IS NULL AND escalated_at IS NULL AND due_at < ?", now).
+		Update("escalated_at", now).Error
+	return periods, err
+}
+
+// Overdue lists the requests of a kind that are past due right now, the
+// longest overdue first.
+func (s SLAStorer) Overdue(ctx context.Context, kind string, now time.Time) ([]models.SLAPeriod, error) {
+	if _, ok := durations[kind]; !ok {
+		return nil, ErrUnknownKind
+	}
+	periods := []models.SLAPeriod{}
+	err := s.db.WithContext(ctx).
+		Where("kind = ? AND ended_at IS NULL AND due_at < ?", kind, now).
+		Order("due_at, id").
+		Find(&periods).Error
+	return periods, err
+}
+
+// Compliance sums up the periods of a kind that started between from and to
+// per assignee and status.
+func (s SLAStorer) Compliance(ctx context.Context, kind string, from time.Time, to time.Time, now time.Time) ([]models.SLACompliance, error) {
+	if _, ok := durations[kind]; !ok {
+		return nil, ErrUnknownKind
+	}
+	var periods []models.SLAPeriod
+	err := s.db.WithContext(ctx).
+		Where("kind = ? AND due_at IS NOT NULL AND started_at >= ? AND started_at < ?", kind, from, to).
+		Order("assignee_id, status, id").
+		Find(&periods).Error
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		assignee uint
+		status   consts.Status
+	}
+	report := []models.SLACompliance{}
+	index := map[key]int{}
+	ended := map[key]int{}
+	hours := map[key]float64{}
+	for _, period := range periods {
+		k := key{status: period.Status}
+		if period.AssigneeID != nil {
+			k.assignee = *period.AssigneeID
+		}
+		i, ok := index[k]
+		if !ok {
+			i = len(report)
+			index[k] = i
+			report = append(report, models.SLACompliance{AssigneeID: period.AssigneeID, Status: period.Status})
+		}
+
+		row := &report[i]
+		row.Total++
+		switch {
+		case period.Breached(now):
+			row.Breached++
+		case period.EndedAt == nil:
+			row.Open++
+		default:
+			row.Met++
+		}
+		if period.EndedAt != nil {
+			ended[k]++
+			hours[k] += period.EndedAt.Sub(period.StartedAt).Hours()
+		}
+	}
+	for k, i := range index {
+		if ended[k] > 0 {
+			report[i].AverageHours = hours[k] / float64(ended[k])
+		}
+	}
+	return report, nil
+}
+
+// Admins are the users escalations go to.
+func (s SLAStorer) Admins(ctx context.Context) ([]uint, error) {
+	var ids []uint
+	err := s.db.WithContext(ctx).
+		Model(&models.User{}).
+		Where("role = ? AND is_active = ?", consts.ROLE_ADMIN, true).
+		Order("id").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
+func configured(tx *gorm.DB, name string, fallback float64) (float64, error) {
+	var config models.Configuration
+	err := tx.Session(&gorm.Session{NewDB: true}).Where("name = ?", name).First(&config).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fallback, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return config.Value, nil
+}
+
+func assignee(id uint) *uint {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
@@ -0,0 +1,132 @@
+package sla
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	"Airplane-Divar/models"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestSLAStorer(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Errorf("could not connect to sql, err: %v", err)
+	}
+
+	createSLAData(t, db)
+
+	ctx := context.Background()
+	s := NewSLAStorer(db)
+	start := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	transition := func(requestID uint, status consts.Status, assigneeID uint, now time.Time) {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			return Transition(tx, consts.SLA_EXPERT, requestID, status, assigneeID, now)
+		})
+		assert.NoError(t, err)
+	}
+
+	// pending is configured to 2 hours, in progress falls back to its default
+	transition(1, consts.EXPERT_PENDING_STATUS, 0, start)
+	transition(2, consts.EXPERT_PENDING_STATUS, 0, start)
+	transition(1, consts.EXPERT_PENDING_STATUS, 3, start.Add(time.Hour))
+	transition(1, consts.IN_PROGRESS_STATUS, 3, start.Add(time.Hour))
+
+	var periods []models.SLAPeriod
+	assert.NoError(t, db.Where("request_id = ?", 1).Order("id").Find(&periods).Error)
+	assert.Equal(t, 2, len(periods))
+	assert.Equal(t, start.Add(2*time.Hour), periods[0].DueAt.UTC())
+	assert.Equal(t, start.Add(time.Hour), periods[0].EndedAt.UTC())
+	assert.Equal(t, uint(3), *periods[0].AssigneeID)
+	assert.Equal(t, uint(1), periods[1].AdsID)
+	assert.Equal(t, start.Add(time.Hour+consts.DEFAULT_SLA_EXPERT_IN_PROGRESS_HOURS*time.Hour), periods[1].DueAt.UTC())
+
+	// only request 2 is still pending past its due date, and it's escalated once
+	now := start.Add(3 * time.Hour)
+	overdue, err := s.Overdue(ctx, consts.SLA_EXPERT, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(overdue))
+	assert.Equal(t, uint(2), overdue[0].RequestID)
+
+	escalated, err := s.Escalate(ctx, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(escalated))
+	assert.Equal(t, uint(2), escalated[0].RequestID)
+	escalated, err = s.Escalate(ctx, now.Add(time.Hour))
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(escalated))
+
+	// leaving the status ends the period, done has no SLA of its own
+	transition(2, consts.DONE_STATUS, 4, now)
+	overdue, err = s.Overdue(ctx, consts.SLA_EXPERT, now)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(overdue))
+
+	report, err := s.Compliance(ctx, consts.SLA_EXPERT, start, start.Add(24*time.Hour), now)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(report))
+	for _, row := range report {
+		switch {
+		case row.AssigneeID == nil:
+			assert.Equal(t, consts.EXPERT_PENDING_STATUS, row.Status)
+			assert.Equal(t, 1, row.Total)
+			assert.Equal(t, 1, row.Breached)
+			assert.Equal(t, 3.0, row.AverageHours)
+		case row.Status == consts.EXPERT_PENDING_STATUS:
+			assert.Equal(t, uint(3), *row.AssigneeID)
+			assert.Equal(t, 1, row.Met)
+			assert.Equal(t, 1.0, row.AverageHours)
+		default:
+			assert.Equal(t, consts.IN_PROGRESS_STATUS, row.Status)
+			assert.Equal(t, 1, row.Open)
+			assert.Equal(t, 0.0, row.AverageHours)
+		}
+	}
+
+	_, err = s.Overdue(ctx, "unknown", now)
+	assert.ErrorIs(t, err, ErrUnknownKind)
+
+	admins, err := s.Admins(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, []uint{1}, admins)
+}
+
+func createSLAData(t *testing.T, db *gorm.DB) {
+	users := []models.User{
+		{ID: 1, Username: "admin", Password: "-", Token: "-", Role: consts.ROLE_ADMIN, IsActive: true},
+		{ID: 2, Username: "airline", Password: "-", Token: "-", Role: consts.ROLE_AIRLINE, IsActive: true},
+		{ID: 3, Username: "expert3", Password: "-", Token: "-", Role: consts.ROLE_EXPERT, IsActive: true},
+		{ID: 4, Username: "expert4", Password: "-", Token: "-", Role: consts.ROLE_EXPERT, IsActive: true},
+		{ID: 5, Username: "former", Password: "-", Token: "-", Role: consts.ROLE_ADMIN},
+	}
+	ads := []models.Ad{
+		{ID: 1, UserID: 2, Subject: "jet", Price: 100, Status: string(consts.ACTIVE)},
+		{ID: 2, UserID: 2, Subject: "helicopter", Price: 100, Status: string(consts.ACTIVE)},
+	}
+	configurations := []models.Configuration{{Name: consts.CONFIG_SLA_EXPERT_PENDING, Value: 2}}
+	for _, rows := range []interface{}{&users, &ads, &configurations} {
+		if err := db.Create(rows).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// is_active defaults to true, false has to be set after creating
+	if err := db.Model(&models.User{}).Where("id = ?", 5).Update("is_active", false).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	requests := []map[string]interface{}{
+		{"ID": 1, "AdsID": 1, "UserID": 2, "Status": consts.EXPERT_PENDING_STATUS},
+		{"ID": 2, "AdsID": 2, "UserID": 2, "Status": consts.EXPERT_PENDING_STATUS},
+	}
+	for _, request := range requests {
+		if err := db.Model(&models.ExpertAds{}).Create(request).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -3,6 +3,14 @@ package filter
 import (
 	"Airplane-Divar/utils"
 	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	queryParamAttributePrefix = "attr."
+	queryParamAttributeMin    = ".min"
+	queryParamAttributeMax    = ".max"
 )
 
 type AdsFilter struct {
@@ -14,6 +22,33 @@ type AdsFilter struct {
 	CategoryID    uint   `json:"category_id"`
 	AirplaneModel string `json:"airplane_model"`
 	Status        string `json:"status"`
+
+	// ads parked in a country (ISO 3166-1 alpha-2), at an airport (ICAO),
+	// or within radius_km of the near airport
+	Country  string  `json:"country"`
+	Airport  string  `json:"airport"`
+	Near     string  `json:"near"`
+	RadiusKM float64 `json:"radius_km"`
+
+	// attr.<name>=value, attr.<name>.min=value and attr.<name>.max=value
+	Attributes []AttributeFilter `json:"-" swaggerignore:"true"`
+}
+
+type AttributeFilter struct {
+	Name  string
+	Op    string
+	Value string
+}
+
+// Param is the query parameter the filter was read from.
+func (a AttributeFilter) Param() string {
+	switch a.Op {
+	case ">=":
+		return queryParamAttributePrefix + a.Name + queryParamAttributeMin
+	case "<=":
+		return queryParamAttributePrefix + a.Name + queryParamAttributeMax
+	}
+	return queryParamAttributePrefix + a.Name
 }
 
 func NewAdsFilter(v url.Values) *AdsFilter {
@@ -26,5 +61,39 @@ func NewAdsFilter(v url.Values) *AdsFilter {
 		FlyTime:       utils.Uint64(v.Get("fly_time")),
 		CategoryID:    utils.Uint(v.Get("category_id")),
 		AirplaneModel: v.Get("airplen_model"),
+		Country:       v.Get("country"),
+		Airport:       v.Get("airport"),
+		Near:          v.Get("near"),
+		RadiusKM:      utils.Float64(v.Get("radius_km")),
+		Attributes:    newAttributeFilters(v),
+	}
+}
+
+func newAttributeFilters(v url.Values) []AttributeFilter {
+	var filters []AttributeFilter
+	for key := range v {
+		name, found := strings.CutPrefix(key, queryParamAttributePrefix)
+		if !found || name == "" {
+			continue
+		}
+
+		op := "="
+		if n, ok := strings.CutSuffix(name, queryParamAttributeMin); ok {
+			name, op = n, ">="
+		} else if n, ok := strings.CutSuffix(name, queryParamAttributeMax); ok {
+			name, op = n, "<="
+		}
+
+		filters = append(filters, AttributeFilter{Name: name, Op: op, Value: v.Get(key)})
 	}
+
+	// keep the generated query stable
+	sort.Slice(filters, func(i, j int) bool {
+		if filters[i].Name == filters[j].Name {
+			return filters[i].Op < filters[j].Op
+		}
+		return filters[i].Name < filters[j].Name
+	})
+
+	return filters
 }
@@ -0,0 +1,213 @@
+package ads
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	"Airplane-Divar/utils"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type DraftsHandler struct {
+	ads    datastore.Ad
+	drafts datastore.Draft
+}
+
+func NewDraftsHandler(ads datastore.Ad, drafts datastore.Draft) *DraftsHandler {
+	return &DraftsHandler{ads: ads, drafts: drafts}
+}
+
+// @Summary Create an ad draft
+// @Description Saves a partial ad without validating it
+// @Tags Ads
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param AdRequest body models.AdRequest true "Partial ad details"
+// @Success 201 {object} models.AdDraftResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /ads/drafts [post]
+func (d DraftsHandler) Create(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_AIRLINE {
+		return apperror.Forbidden(apperror.CodeForbidden, "Airlines Can Add an ad!")
+	}
+
+	payload, errs := decodeDraft(c)
+	if errs != nil {
+		return errs.Err()
+	}
+
+	created, err := d.drafts.Create(c.Request().Context(), user.ID, payload)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, toDraftResponse(created))
+}
+
+// @Summary Autosave an ad draft
+// @Description Merges the given fields into the draft, a null field removes it
+// @Tags Ads
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Draft ID"
+// @Param AdRequest body models.AdRequest true "Partial ad details"
+// @Success 200 {object} models.AdDraftResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /ads/drafts/{id} [put]
+func (d DraftsHandler) Save(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	payload, errs := decodeDraft(c)
+	if errs != nil {
+		return errs.Err()
+	}
+
+	saved, err := d.drafts.Save(c.Request().Context(), id, user.ID, payload)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toDraftResponse(saved))
+}
+
+// @Summary List ad drafts
+// @Description Drafts of the logged in user, the most recently saved first
+// @Tags Ads
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Success 200 {array} models.AdDraftResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /ads/drafts [get]
+func (d DraftsHandler) List(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	drafts, err := d.drafts.List(c.Request().Context(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	resp := []models.AdDraftResponse{}
+	for _, dr := range drafts {
+		resp = append(resp, toDraftResponse(dr))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Get an ad draft
+// @Tags Ads
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Draft ID"
+// @Success 200 {object} models.AdDraftResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /ads/drafts/{id} [get]
+func (d DraftsHandler) Get(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	dr, err := d.drafts.Get(c.Request().Context(), id, user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toDraftResponse(dr))
+}
+
+// @Summary Delete an ad draft
+// @Tags Ads
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Draft ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /ads/drafts/{id} [delete]
+func (d DraftsHandler) Delete(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	if err := d.drafts.Delete(c.Request().Context(), id, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, models.SuccessResponse{Success: true})
+}
+
+// @Summary Submit an ad draft
+// @Description Validates the draft like a new ad and turns it into an ad waiting for approval. The draft is removed.
+// @Tags Ads
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Draft ID"
+// @Success 200 {object} AdResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /ads/drafts/{id}/submit [post]
+func (d DraftsHandler) Submit(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := c.Get("user").(models.User)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	dr, err := d.drafts.Get(ctx, id, user.ID)
+	if err != nil {
+		return err
+	}
+	var req models.AdRequest
+	errs := utils.BindJSON(strings.NewReader(dr.Payload), &req)
+
+	adRes, err := createAd(d.ads, user, req, errs)
+	if err != nil {
+		return err
+	}
+
+	// the ad is already created, a leftover draft is not worth failing for
+	_ = d.drafts.Delete(ctx, id, user.ID)
+
+	return c.JSON(http.StatusOK, adRes)
+}
+
+// decodeDraft reads a partial ad, drafts are not validated until submitted.
+func decodeDraft(c echo.Context) (map[string]interface{}, utils.ValidationErrors) {
+	payload := map[string]interface{}{}
+	if err := json.NewDecoder(c.Request().Body).Decode(&payload); err != nil {
+		var errs utils.ValidationErrors
+		errs.Add("body", consts.VALIDATION_INVALID_JSON, "Invalid JSON")
+		return nil, errs
+	}
+	return payload, nil
+}
+
+func toDraftResponse(dr models.AdDraft) models.AdDraftResponse {
+	payload := map[string]interface{}{}
+	_ = json.Unmarshal([]byte(dr.Payload), &payload)
+	return models.AdDraftResponse{
+		ID:        dr.ID,
+		Payload:   payload,
+		CreatedAt: dr.CreatedAt,
+		UpdatedAt: dr.UpdatedAt,
+	}
+}
@@ -1,14 +1,19 @@
 package ads
 
 import (
+	"Airplane-Divar/airports"
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/consts"
 	"Airplane-Divar/datastore"
 	"Airplane-Divar/filter"
 	"Airplane-Divar/models"
 	logging_service "Airplane-Divar/service/logging"
+	watch_service "Airplane-Divar/service/watch"
 	"Airplane-Divar/utils"
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 
@@ -23,19 +28,6 @@ func New(ads datastore.Ad) *AdsHandler {
 	return &AdsHandler{datastore: ads}
 }
 
-type AdRequest struct {
-	Image         string `json:"Image"`
-	Description   string `json:"Description"`
-	Subject       string `json:"Subject"`
-	Price         uint64 `json:"Price"`
-	Category      string `json:"Category"`
-	FlyTime       uint   `json:"FlyTime"`
-	AirplaneModel string `json:"AirplaneModel"`
-	RepairCheck   bool   `json:"RepairCheck"`
-	ExpertCheck   bool   `json:"ExpertCheck"`
-	PlaneAge      uint   `json:"PlaneAge"`
-}
-
 type AdResponse struct {
 	ID            uint   `json:"ID"`
 	UserID        uint   `json:"UserID"`
@@ -50,10 +42,9 @@ type AdResponse struct {
 	RepairCheck   bool   `json:"RepairCheck"`
 	ExpertCheck   bool   `json:"ExpertCheck"`
 	PlaneAge      uint   `json:"PlaneAge"`
-}
-type ErrorAddAd struct {
-	ResponseCode int    `json:"responsecode"`
-	Message      string `json:"message"`
+
+	AirportCode string           `json:"AirportCode"`
+	Location    *models.Location `json:"Location"`
 }
 
 // Create a new ad by an airline.
@@ -64,50 +55,59 @@ type ErrorAddAd struct {
 // @Produce json
 // @Security ApiKeyAuth
 // @Param Authorization header string true "User Token"
-// @Param AdRequest body AdRequest true "Ad details"
+// @Param AdRequest body models.AdRequest true "Ad details, Attributes must match GET /categories/{id}/attributes"
 // @Success 200 {object} AdResponse
-// @Failure 422 {object} ErrorAddAd
-// @Failure 403 {object} ErrorAddAd
-// @Failure 500 {object} ErrorAddAd
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /ads/add [post]
 func (a AdsHandler) AddAdHandler(c echo.Context) error {
-	// Read Request Body
-	jsonBody := make(map[string]interface{})
-	err := json.NewDecoder(c.Request().Body).Decode(&jsonBody)
+	var req models.AdRequest
+	errs := utils.BindJSON(c.Request().Body, &req)
+
+	adRes, err := createAd(a.datastore, c.Get("user").(models.User), req, errs)
 	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: "Invalid JSON"})
+		return err
 	}
 
-	//check json format
-	jsonFormatValidationMsg, jsonFormatErr := utils.ValidateJsonFormat(jsonBody, "Price", "Category", "FlyTime", "AirplaneModel", "RepairCheck", "ExpertCheck", "PlaneAge")
-	if jsonFormatErr != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: jsonFormatValidationMsg})
+	return c.JSON(http.StatusOK, adRes)
+}
+
+// createAd validates a new ad against its category and creates it as an
+// inactive ad of the user, waiting for approval. errs are the problems found
+// while binding the request, they are reported along with the rest.
+func createAd(ds datastore.Ad, user models.User, req models.AdRequest, errs utils.ValidationErrors) (models.AdResponse, error) {
+	if errs.Has("body") {
+		return models.AdResponse{}, errs.Err()
 	}
 
-	user := c.Get("user").(models.User)
 	if user.Role != consts.ROLE_AIRLINE {
-		return c.JSON(http.StatusForbidden, models.Response{ResponseCode: 403, Message: "Airlines Can Add an ad!"})
+		return models.AdResponse{}, apperror.Forbidden(apperror.CodeForbidden, "Airlines Can Add an ad!")
 	}
 
-	//validate and initialize categoryID in ad object
-	category_name := ""
-	if cat, ok := jsonBody["Category"].(string); ok {
-		category_name = cat
-	} else {
-		msg := "Category should be string !"
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: msg})
+	//validate category and its attributes
+	var categoryObj models.Category
+	var attributes []models.CategoryAttribute
+	if !errs.Has("Category") {
+		var err error
+		categoryObj, err = ds.GetCategoryByName(req.Category)
+		if err != nil {
+			errs.Add("Category", consts.VALIDATION_INVALID, "Invalid Category Name")
+		} else if attributes, err = ds.GetCategoryAttributes(categoryObj.ID); err != nil {
+			return models.AdResponse{}, apperror.Internal("Ad Cration Failed")
+		}
 	}
-	categoryObj, err := a.datastore.GetCategoryByName(category_name)
-	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: "Invalid Category Name"})
+	if errs.Has("Category") {
+		// attributes can't be checked without their category
+		req.Attributes = nil
 	}
 
-	var ad models.Ad
-
 	//check ad properties validation
-	adFormatValidationMsg, ad, adFormatErr := utils.ValidateAd(jsonBody, categoryObj)
-	if adFormatErr != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: adFormatValidationMsg})
+	ad, adErrs := utils.ValidateAd(req, categoryObj, attributes...)
+	errs.Merge(adErrs)
+	if len(errs) > 0 {
+		return models.AdResponse{}, errs.Err()
 	}
 
 	//set user id
@@ -118,9 +118,9 @@ func (a AdsHandler) AddAdHandler(c echo.Context) error {
 	ad.Status = string(consts.INACTIVE)
 
 	//Create Ad
-	createdAd, err := a.datastore.CreateAd(&ad)
+	createdAd, err := ds.CreateAd(&ad)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.Response{ResponseCode: 500, Message: "Ad Cration Failed"})
+		return models.AdResponse{}, apperror.Internal("Ad Cration Failed")
 	}
 	adRes := models.AdResponse{
 		ID:            createdAd.ID,
@@ -136,6 +136,18 @@ func (a AdsHandler) AddAdHandler(c echo.Context) error {
 		RepairCheck:   createdAd.RepairCheck,
 		ExpertCheck:   createdAd.ExpertCheck,
 		PlaneAge:      createdAd.PlaneAge,
+		AirportCode:   createdAd.AirportCode,
+		Location:      location(createdAd.AirportCode),
+	}
+	if len(createdAd.Attributes) > 0 {
+		adRes.Attributes = map[string]string{}
+		for _, attr := range attributes {
+			for _, value := range createdAd.Attributes {
+				if value.AttributeID == attr.ID {
+					adRes.Attributes[attr.Name] = value.Value
+				}
+			}
+		}
 	}
 
 	// ____ Report Log ____
@@ -148,7 +160,7 @@ func (a AdsHandler) AddAdHandler(c echo.Context) error {
 	}
 	// ____ Report Log ____
 
-	return c.JSON(http.StatusOK, adRes)
+	return adRes, nil
 }
 
 // Status updates the status of an ad.
@@ -165,9 +177,10 @@ func (a AdsHandler) AddAdHandler(c echo.Context) error {
 // @Param id path integer true "Ad ID"
 // @Param status body models.UpdateAdsStatusRequest true "status object"
 // @Success 200 {string} string "Updated successfully"
-// @Failure 400 {string} string "Invalid parameter id"
-// @Failure 404 {string} string "Not Found"
-// @Failure 500 {string} string "Could not update ads status"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /ads/{id}/status [put]
 func (a AdsHandler) Status(c echo.Context) error {
 	userRole := c.Get("user").(models.User).Role
@@ -175,19 +188,40 @@ func (a AdsHandler) Status(c echo.Context) error {
 		id := c.Param("id")
 		index, err := strconv.Atoi(id)
 		if err != nil {
-			return c.JSON(http.StatusBadRequest, "invalid parameter id")
+			return apperror.InvalidParameter("id")
 		}
 
 		var status models.UpdateAdsStatusRequest
-		if err := c.Bind(&status); err != nil {
-			return c.JSON(http.StatusBadRequest, err.Error())
+		errs := utils.BindJSON(c.Request().Body, &status)
+		if !errs.Has("body") {
+			errs.Merge(utils.ValidateAdStatus(status))
+		}
+		if len(errs) > 0 {
+			return errs.Err()
+		}
+
+		before, err := a.datastore.Get(index, userRole)
+		if err != nil {
+			return err
+		} else if len(before) == 0 {
+			return echo.ErrNotFound
 		}
 
 		_, err = a.datastore.UpdateStatus(index, status.Status)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, "could not update ads status")
+			return err
 		}
 
+		// ____ Notify Watchers ____
+		watchService := watch_service.GetInstance()
+		if watchService != (*watch_service.Watch)(nil) {
+			err = watchService.StatusChanged(c.Request().Context(), uint(index), consts.AdStatus(before[0].Status), status.Status)
+			if err != nil {
+				log.Printf("could not notify the watchers of ad %d: %v", index, err)
+			}
+		}
+		// ____ Notify Watchers ____
+
 		// ____ Report Log ____
 		logService := logging_service.GetInstance()
 		logName := consts.LOG_ADMIN_APPROVE
@@ -204,7 +238,7 @@ func (a AdsHandler) Status(c echo.Context) error {
 
 		return c.JSON(http.StatusOK, "Updated successfuly")
 	} else {
-		return c.JSON(http.StatusNotFound, "Not Found")
+		return echo.ErrNotFound
 	}
 }
 
@@ -218,22 +252,22 @@ func (a AdsHandler) Status(c echo.Context) error {
 // @Param Authorization header string true "User Token"
 // @Param id path int true "Ad ID"
 // @Success 200 {object} models.Ad
-// @Failure 400 {string} string "Invalid parameter id"
-// @Failure 500 {string} string "Could not retrieve ads"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /ads/{id} [get]
 func (a AdsHandler) Get(c echo.Context) error {
 	id := c.Param("id")
 	index, err := strconv.Atoi(id)
 	fmt.Println("--THIS--", err)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, "invalid parameter id")
+		return apperror.InvalidParameter("id")
 	}
 
 	userRole := c.Get("user").(models.User).Role
 
 	resp, err := a.datastore.Get(index, userRole)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "could not retrieve ads")
+		return err
 	}
 
 	// ____ Get Logs of Ads ____
@@ -247,7 +281,7 @@ func (a AdsHandler) Get(c echo.Context) error {
 	}
 	// ____ Get Logs of Ads ____
 
-	return c.JSON(http.StatusOK, resp)
+	return c.JSON(http.StatusOK, withLocations(resp))
 }
 
 // ListAds retrieves a list of ads.
@@ -259,8 +293,10 @@ func (a AdsHandler) Get(c echo.Context) error {
 // @Security ApiKeyAuth
 // @Param Authorization header string true "User Token"
 // @Param filter query filter.AdsFilter true "Query parameters for filtering ads"
+// @Param attr.{name} query string false "Category attribute equals value, attr.{name}.min and attr.{name}.max compare numbers"
 // @Success 200 {object} []models.Ad "Successfully retrieved ads"
-// @Failure 500 {string} string "Internal Server Error: Failed to retrieve ads"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /ads [get]
 func (a AdsHandler) List(c echo.Context) error {
 	filter := filter.NewAdsFilter(c.QueryParams())
@@ -270,14 +306,113 @@ func (a AdsHandler) List(c echo.Context) error {
 	if len(filter.Base.Sort) != 0 {
 		resp, err := a.datastore.ListFilterSort(&filter.Base)
 		if err != nil {
-			return c.JSON(http.StatusInternalServerError, "could not retrieve ads")
+			return err
 		}
-		return c.JSON(http.StatusOK, resp)
+		return c.JSON(http.StatusOK, withLocations(resp))
 	}
 
 	resp, err := a.datastore.ListFilterByColumn(filter)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "could not retrieve ads")
+		return err
+	}
+	return c.JSON(http.StatusOK, withLocations(resp))
+}
+
+// withLocations resolves where each ad's aircraft is parked.
+func withLocations(ads []models.Ad) []models.Ad {
+	for i := range ads {
+		ads[i].Location = location(ads[i].AirportCode)
+	}
+	return ads
+}
+
+func location(code string) *models.Location {
+	airport, ok := airports.Lookup(code)
+	if !ok {
+		return nil
+	}
+	return &models.Location{
+		AirportCode: airport.Code,
+		AirportName: airport.Name,
+		Country:     airport.Country,
+		Lat:         airport.Lat,
+		Lon:         airport.Lon,
+	}
+}
+
+// Export streams the filtered ads as CSV or newline-delimited JSON.
+// @Summary Export ads
+// @Description Streams every ad matching the query filters (without paging) as CSV or newline-delimited JSON. Categories are exported by name.
+// @Tags Ads
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Security ApiKeyAuth
+// @Param Authorization header string true "User Token"
+// @Param format query string false "Export format: csv (default) or json"
+// @Param filter query filter.AdsFilter false "Query parameters for filtering ads"
+// @Success 200 {array} models.AdExportRow
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /ads/export [get]
+func (a AdsHandler) Export(c echo.Context) error {
+	filter := filter.NewAdsFilter(c.QueryParams())
+	filter.Base.UserRole = c.Get("user").(models.User).Role
+
+	format := c.QueryParam("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		return apperror.InvalidParameter("format")
+	}
+
+	res := c.Response()
+	csvWriter := csv.NewWriter(res)
+	jsonEncoder := json.NewEncoder(res)
+
+	// headers are written lazily so a failing query can still answer with a 500
+	started := false
+	start := func() error {
+		started = true
+		if format == "csv" {
+			res.Header().Set(echo.HeaderContentType, "text/csv")
+			res.Header().Set(echo.HeaderContentDisposition, "attachment; filename=ads.csv")
+			res.WriteHeader(http.StatusOK)
+			return csvWriter.Write(models.AdExportCSVHeader())
+		}
+		res.Header().Set(echo.HeaderContentType, "application/x-ndjson")
+		res.Header().Set(echo.HeaderContentDisposition, "attachment; filename=ads.json")
+		res.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	err := a.datastore.Export(c.Request().Context(), filter, func(row models.AdExportRow) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if format == "csv" {
+			if err := csvWriter.Write(row.CSVRecord()); err != nil {
+				return err
+			}
+			csvWriter.Flush()
+			return csvWriter.Error()
+		}
+		return jsonEncoder.Encode(row)
+	})
+	if err != nil {
+		// once the response is on the wire the export is just cut short
+		return err
 	}
-	return c.JSON(http.StatusOK, resp)
+
+	if !started {
+		if err := start(); err != nil {
+			return err
+		}
+	}
+	csvWriter.Flush()
+	res.Flush()
+
+	return csvWriter.Error()
 }
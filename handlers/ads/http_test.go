@@ -1,10 +1,14 @@
 package ads
 
 import (
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore/draft"
 	"Airplane-Divar/filter"
+	"Airplane-Divar/middlewares"
 	"Airplane-Divar/models"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -28,7 +32,7 @@ func TestAdsHandler_Get(t *testing.T) {
 		{
 			id:            "2",
 			expectedCode:  http.StatusInternalServerError,
-			expectedError: "could not retrieve ads",
+			expectedError: "internal server error",
 		},
 		{
 			id:            "1a",
@@ -59,7 +63,7 @@ func TestAdsHandler_Get(t *testing.T) {
 		c.SetParamValues(v.id)
 
 		a := New(mockDatastore{})
-		a.Get(c)
+		serve(c, a.Get)
 
 		if v.expectedCode == http.StatusOK {
 			var adRes []models.Ad
@@ -70,12 +74,12 @@ func TestAdsHandler_Get(t *testing.T) {
 				t.Errorf("[TEST%d]Failed. Got %v\tExpected %v\n", i+1, adRes, v.response)
 			}
 		} else {
-			var errorRes string
+			var errorRes models.ErrorResponse
 			err := json.Unmarshal(w.Body.Bytes(), &errorRes)
 			assert.NoError(t, err)
 
-			if !reflect.DeepEqual(errorRes, v.expectedError) {
-				t.Errorf("[http Get() TEST%d]Failed. Got %v\tExpected %v\n", i+1, errorRes, v.expectedError)
+			if errorRes.Error.Message != v.expectedError {
+				t.Errorf("[http Get() TEST%d]Failed. Got %v\tExpected %v\n", i+1, errorRes.Error.Message, v.expectedError)
 			}
 		}
 	}
@@ -119,7 +123,7 @@ func TestAdsHandler_ListFilter(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		a.List(c)
+		serve(c, a.List)
 
 		if v.expectedCode == http.StatusOK {
 			var adRes []models.Ad
@@ -130,12 +134,12 @@ func TestAdsHandler_ListFilter(t *testing.T) {
 				t.Errorf("[TEST%d]Failed. Got %v\tExpected %v\n", i+1, adRes, v.response)
 			}
 		} else {
-			var errorRes string
+			var errorRes models.ErrorResponse
 			err := json.Unmarshal(w.Body.Bytes(), &errorRes)
 			assert.NoError(t, err)
 
-			if !reflect.DeepEqual(errorRes, v.expectedError) {
-				t.Errorf("[TEST%d]Failed. Got %v\tExpected %v\n", i+1, errorRes, v.expectedError)
+			if errorRes.Error.Message != v.expectedError {
+				t.Errorf("[TEST%d]Failed. Got %v\tExpected %v\n", i+1, errorRes.Error.Message, v.expectedError)
 			}
 		}
 	}
@@ -170,7 +174,7 @@ func TestAdsHandler_ListFilterSort(t *testing.T) {
 		},
 		{
 			query:         "sort=plane_age,asc&sort=favourite_colour,desc",
-			expectedError: "could not retrieve ads",
+			expectedError: "internal server error",
 			expectedCode:  http.StatusInternalServerError,
 		},
 	}
@@ -184,7 +188,7 @@ func TestAdsHandler_ListFilterSort(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		a.List(c)
+		serve(c, a.List)
 
 		if v.expectedCode == http.StatusOK {
 			var adRes []models.Ad
@@ -195,17 +199,95 @@ func TestAdsHandler_ListFilterSort(t *testing.T) {
 				t.Errorf("[TEST%d]Failed. Got %v\tExpected %v\n", i+1, adRes, v.response)
 			}
 		} else {
-			var errorRes string
+			var errorRes models.ErrorResponse
 			err := json.Unmarshal(w.Body.Bytes(), &errorRes)
 			assert.NoError(t, err)
 
-			if !reflect.DeepEqual(errorRes, v.expectedError) {
-				t.Errorf("[TEST%d]Failed. Got %v\tExpected %v\n", i+1, errorRes, v.expectedError)
+			if errorRes.Error.Message != v.expectedError {
+				t.Errorf("[TEST%d]Failed. Got %v\tExpected %v\n", i+1, errorRes.Error.Message, v.expectedError)
 			}
 		}
 	}
 }
 
+func TestAdsHandler_Export(t *testing.T) {
+	t.Run("csv", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ads/export?format=csv", nil)
+		rec := httptest.NewRecorder()
+
+		c := echo.New().NewContext(req, rec)
+		c.Set("user", mockUserData[0])
+
+		a := New(mockDatastore{})
+		err := a.Export(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv", rec.Header().Get(echo.HeaderContentType))
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		assert.Equal(t, 3, len(lines))
+		assert.Equal(t, strings.Join(models.AdExportCSVHeader(), ","), lines[0])
+		assert.Contains(t, lines[1], "big-passenger")
+		assert.Contains(t, lines[2], "small-passenger")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ads/export?format=json&category_id=1", nil)
+		rec := httptest.NewRecorder()
+
+		c := echo.New().NewContext(req, rec)
+		c.Set("user", mockUserData[0])
+
+		a := New(mockDatastore{})
+		err := a.Export(c)
+
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/x-ndjson", rec.Header().Get(echo.HeaderContentType))
+
+		lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+		assert.Equal(t, 1, len(lines))
+
+		var row models.AdExportRow
+		err = json.Unmarshal([]byte(lines[0]), &row)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(2), row.ID)
+		assert.Equal(t, "small-passenger", row.CategoryName)
+	})
+
+	t.Run("invalid format", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ads/export?format=xml", nil)
+		rec := httptest.NewRecorder()
+
+		c := echo.New().NewContext(req, rec)
+		c.Set("user", mockUserData[0])
+
+		a := New(mockDatastore{})
+		serve(c, a.Export)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("datastore error", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/ads/export?plane_age=99", nil)
+		rec := httptest.NewRecorder()
+
+		c := echo.New().NewContext(req, rec)
+		c.Set("user", mockUserData[0])
+
+		a := New(mockDatastore{})
+		serve(c, a.Export)
+
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+		var response models.ErrorResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, apperror.CodeInternal, response.Error.Code)
+	})
+}
+
 func TestAdHandler_AddAd(t *testing.T) {
 	e := echo.New()
 
@@ -219,16 +301,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err := a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var response models.ErrorResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Invalid JSON", response.Message)
+		assertFieldError(t, response, "body", consts.VALIDATION_INVALID_JSON)
 	})
 
 	t.Run("JSON Without Price", func(t *testing.T) {
@@ -254,16 +334,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Input Json doesn't include Price", response.Message)
+		assertFieldError(t, response, "Price", consts.VALIDATION_REQUIRED)
 	})
 
 	t.Run("JSON Without Category", func(t *testing.T) {
@@ -289,16 +367,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Input Json doesn't include Category", response.Message)
+		assertFieldError(t, response, "Category", consts.VALIDATION_REQUIRED)
 	})
 
 	t.Run("non-string category", func(t *testing.T) {
@@ -325,16 +401,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Category should be string !", response.Message)
+		assertFieldError(t, response, "Category", consts.VALIDATION_TYPE)
 	})
 
 	t.Run("invalid category name", func(t *testing.T) {
@@ -361,16 +435,119 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
+		serve(c, a.AddAdHandler)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		var response models.ErrorResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+
+		assertFieldError(t, response, "Category", consts.VALIDATION_INVALID)
+	})
+
+	t.Run("missing required attribute", func(t *testing.T) {
+		addAdReqBody := map[string]interface{}{
+			"Image":         "example1.jpg",
+			"Description":   "This is example ad 1.",
+			"Subject":       "Example Ad 1",
+			"FlyTime":       1000,
+			"AirplaneModel": "XYZ123",
+			"Price":         500000,
+			"Category":      "big-passenger",
+			"RepairCheck":   true,
+			"ExpertCheck":   false,
+			"PlaneAge":      7,
+			"Attributes":    map[string]interface{}{"side_door": true},
+		}
+		jsonData, err := json.Marshal(addAdReqBody)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/ads/add", bytes.NewReader([]byte(jsonData)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.Set("user", mockUserData[0])
+
+		a := New(mockDatastore{})
+		serve(c, a.AddAdHandler)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		var response models.ErrorResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+
+		assertFieldError(t, response, "Attributes.cargo_door_width", consts.VALIDATION_REQUIRED)
+	})
 
+	t.Run("wrong attribute type", func(t *testing.T) {
+		addAdReqBody := map[string]interface{}{
+			"Image":         "example1.jpg",
+			"Description":   "This is example ad 1.",
+			"Subject":       "Example Ad 1",
+			"FlyTime":       1000,
+			"AirplaneModel": "XYZ123",
+			"Price":         500000,
+			"Category":      "big-passenger",
+			"RepairCheck":   true,
+			"ExpertCheck":   false,
+			"PlaneAge":      7,
+			"Attributes":    map[string]interface{}{"cargo_door_width": "wide"},
+		}
+		jsonData, err := json.Marshal(addAdReqBody)
 		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/ads/add", bytes.NewReader([]byte(jsonData)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.Set("user", mockUserData[0])
+
+		a := New(mockDatastore{})
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Invalid Category Name", response.Message)
+		assertFieldError(t, response, "Attributes.cargo_door_width", consts.VALIDATION_TYPE)
+	})
+
+	t.Run("unknown attribute", func(t *testing.T) {
+		addAdReqBody := map[string]interface{}{
+			"Image":         "example1.jpg",
+			"Description":   "This is example ad 1.",
+			"Subject":       "Example Ad 1",
+			"FlyTime":       1000,
+			"AirplaneModel": "XYZ123",
+			"Price":         500000,
+			"Category":      "big-passenger",
+			"RepairCheck":   true,
+			"ExpertCheck":   false,
+			"PlaneAge":      7,
+			"Attributes":    map[string]interface{}{"cargo_door_width": 350, "wingspan": 40},
+		}
+		jsonData, err := json.Marshal(addAdReqBody)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/ads/add", bytes.NewReader([]byte(jsonData)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.Set("user", mockUserData[0])
+
+		a := New(mockDatastore{})
+		serve(c, a.AddAdHandler)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		var response models.ErrorResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+
+		assertFieldError(t, response, "Attributes.wingspan", consts.VALIDATION_UNKNOWN)
 	})
 
 	t.Run("non-airline user", func(t *testing.T) {
@@ -397,16 +574,15 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[1])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusForbidden, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Airlines Can Add an ad!", response.Message)
+		assert.Equal(t, apperror.CodeForbidden, response.Error.Code)
+		assert.Equal(t, "Airlines Can Add an ad!", response.Error.Message)
 	})
 
 	t.Run("non-string model", func(t *testing.T) {
@@ -433,16 +609,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Plane Model should be string !", response.Message)
+		assertFieldError(t, response, "AirplaneModel", consts.VALIDATION_TYPE)
 	})
 
 	t.Run("non-number price", func(t *testing.T) {
@@ -469,16 +643,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Price should be a number !", response.Message)
+		assertFieldError(t, response, "Price", consts.VALIDATION_TYPE)
 	})
 
 	t.Run("non-integer price", func(t *testing.T) {
@@ -505,16 +677,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Price should be an integer !", response.Message)
+		assertFieldError(t, response, "Price", consts.VALIDATION_TYPE)
 	})
 
 	t.Run("non-integer fly time", func(t *testing.T) {
@@ -541,16 +711,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "fly_time should be an integer !", response.Message)
+		assertFieldError(t, response, "FlyTime", consts.VALIDATION_TYPE)
 	})
 
 	t.Run("non-boolean repair_check", func(t *testing.T) {
@@ -577,16 +745,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Repair Check should be boolean !", response.Message)
+		assertFieldError(t, response, "RepairCheck", consts.VALIDATION_TYPE)
 	})
 
 	t.Run("non-boolean ExpertCheck", func(t *testing.T) {
@@ -613,16 +779,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Expert Check should be boolean !", response.Message)
+		assertFieldError(t, response, "ExpertCheck", consts.VALIDATION_TYPE)
 	})
 
 	t.Run("non-integer age", func(t *testing.T) {
@@ -649,16 +813,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Age should be an integer !", response.Message)
+		assertFieldError(t, response, "PlaneAge", consts.VALIDATION_TYPE)
 	})
 	t.Run("invalid age", func(t *testing.T) {
 		addAdReqBody := map[string]interface{}{
@@ -671,7 +833,7 @@ func TestAdHandler_AddAd(t *testing.T) {
 			"Category":      "small-passenger",
 			"RepairCheck":   true,
 			"ExpertCheck":   false,
-			"PlaneAge":      123,
+			"PlaneAge":      1000,
 		}
 		jsonData, err := json.Marshal(addAdReqBody)
 		assert.NoError(t, err)
@@ -684,16 +846,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "The year of the invention of the airplane was 1903 !", response.Message)
+		assertFieldError(t, response, "PlaneAge", consts.VALIDATION_OUT_OF_RANGE)
 	})
 
 	t.Run("non-string image", func(t *testing.T) {
@@ -720,16 +880,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Image should be an url !", response.Message)
+		assertFieldError(t, response, "Image", consts.VALIDATION_TYPE)
 	})
 
 	t.Run("non-string subject", func(t *testing.T) {
@@ -756,16 +914,14 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "subject should be string !", response.Message)
+		assertFieldError(t, response, "Subject", consts.VALIDATION_TYPE)
 	})
 
 	t.Run("non-string description", func(t *testing.T) {
@@ -792,16 +948,116 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
+		serve(c, a.AddAdHandler)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		var response models.ErrorResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
 
+		assertFieldError(t, response, "Description", consts.VALIDATION_TYPE)
+	})
+
+	t.Run("unknown airport code", func(t *testing.T) {
+		addAdReqBody := map[string]interface{}{
+			"FlyTime":       78,
+			"AirplaneModel": "something",
+			"Price":         500000,
+			"Category":      "small-passenger",
+			"RepairCheck":   true,
+			"ExpertCheck":   false,
+			"PlaneAge":      23,
+			"AirportCode":   "ZZZZ",
+		}
+		jsonData, err := json.Marshal(addAdReqBody)
 		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/ads/add", bytes.NewReader([]byte(jsonData)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.Set("user", mockUserData[0])
+
+		a := New(mockDatastore{})
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
+		var response models.ErrorResponse
 		err = json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "description should be string !", response.Message)
+		assertFieldError(t, response, "AirportCode", consts.VALIDATION_INVALID)
+	})
+
+	t.Run("every error at once", func(t *testing.T) {
+		addAdReqBody := map[string]interface{}{
+			"FlyTime":       "long",
+			"AirplaneModel": "something",
+			"Price":         1.5,
+			"RepairCheck":   true,
+			"PlaneAge":      23,
+			"AirportCode":   "XXXX",
+		}
+		jsonData, err := json.Marshal(addAdReqBody)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/ads/add", bytes.NewReader([]byte(jsonData)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.Set("user", mockUserData[0])
+
+		a := New(mockDatastore{})
+		serve(c, a.AddAdHandler)
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+		var response models.ErrorResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 5, len(response.Error.Details))
+		assertFieldError(t, response, "Price", consts.VALIDATION_TYPE)
+		assertFieldError(t, response, "Category", consts.VALIDATION_REQUIRED)
+		assertFieldError(t, response, "FlyTime", consts.VALIDATION_TYPE)
+		assertFieldError(t, response, "ExpertCheck", consts.VALIDATION_REQUIRED)
+		assertFieldError(t, response, "AirportCode", consts.VALIDATION_INVALID)
+	})
+
+	t.Run("valid request with airport", func(t *testing.T) {
+		addAdReqBody := map[string]interface{}{
+			"FlyTime":       78,
+			"AirplaneModel": "something",
+			"Price":         500000,
+			"Category":      "small-passenger",
+			"RepairCheck":   true,
+			"ExpertCheck":   false,
+			"PlaneAge":      23,
+			"AirportCode":   "oiii",
+		}
+		jsonData, err := json.Marshal(addAdReqBody)
+		assert.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/ads/add", bytes.NewReader([]byte(jsonData)))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.Set("user", mockUserData[0])
+
+		a := New(mockDatastore{})
+		serve(c, a.AddAdHandler)
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var response models.AdResponse
+		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "OIII", response.AirportCode)
+		if assert.NotNil(t, response.Location) {
+			assert.Equal(t, "IR", response.Location.Country)
+		}
 	})
 
 	t.Run("valid request", func(t *testing.T) {
@@ -828,9 +1084,7 @@ func TestAdHandler_AddAd(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err = a.AddAdHandler(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddAdHandler)
 		assert.Equal(t, http.StatusOK, rec.Code)
 
 		var response models.Ad
@@ -857,6 +1111,23 @@ var (
 		},
 	}
 
+	mockAttributeData = []models.CategoryAttribute{
+		{
+			ID:         1,
+			CategoryID: 2,
+			Name:       "cargo_door_width",
+			Type:       consts.ATTRIBUTE_NUMBER,
+			Unit:       "cm",
+			Required:   true,
+		},
+		{
+			ID:         2,
+			CategoryID: 2,
+			Name:       "side_door",
+			Type:       consts.ATTRIBUTE_BOOLEAN,
+		},
+	}
+
 	mockAdData = []models.Ad{
 		{
 			ID:            1,
@@ -984,3 +1255,184 @@ func (m mockDatastore) UpdateStatus(id int, status consts.AdStatus) (models.Ad,
 	}
 	return mockAdData[id], nil
 }
+
+func (m mockDatastore) Export(ctx context.Context, f *filter.AdsFilter, fn func(models.AdExportRow) error) error {
+	if f.PlaneAge == 99 {
+		return errors.New("db error")
+	}
+
+	for _, ad := range mockAdData {
+		if f.CategoryID != 0 && ad.CategoryID != f.CategoryID {
+			continue
+		}
+		row := models.AdExportRow{
+			ID:            ad.ID,
+			UserID:        ad.UserID,
+			Subject:       ad.Subject,
+			Price:         ad.Price,
+			CategoryName:  mockCategoryData[ad.CategoryID-1].Name,
+			Status:        ad.Status,
+			AirplaneModel: ad.AirplaneModel,
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m mockDatastore) GetCategoryAttributes(categoryID uint) ([]models.CategoryAttribute, error) {
+	if categoryID == 2 {
+		return mockAttributeData, nil
+	}
+	return nil, nil
+}
+
+// assertFieldError checks the field failed validation with the code.
+func assertFieldError(t *testing.T, response models.ErrorResponse, field string, code string) {
+	t.Helper()
+	assert.Equal(t, apperror.CodeValidation, response.Error.Code)
+	for _, err := range response.Error.Details {
+		if err.Field == field {
+			assert.Equal(t, code, err.Code, field)
+			return
+		}
+	}
+	t.Errorf("no error for %s in %v", field, response.Error.Details)
+}
+
+// serve runs the handler and renders its error the way the server does.
+func serve(c echo.Context, h echo.HandlerFunc) {
+	if err := h(c); err != nil {
+		middlewares.ErrorHandler(err, c)
+	}
+}
+
+func TestAdsHandler_Status(t *testing.T) {
+	e := echo.New()
+
+	update := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/ads/1/status", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues("1")
+		c.Set("user", mockUserData[1])
+
+		serve(c, New(mockDatastore{}).Status)
+		return rec
+	}
+
+	testcases := []struct {
+		name string
+		body string
+		code string
+	}{
+		{"invalid json", `{"status":`, consts.VALIDATION_INVALID_JSON},
+		{"missing status", `{}`, consts.VALIDATION_REQUIRED},
+		{"mistyped status", `{"status":1}`, consts.VALIDATION_TYPE},
+		{"unknown status", `{"status":"Archived"}`, consts.VALIDATION_INVALID},
+		{"status set by offers", `{"status":"Sold"}`, consts.VALIDATION_NOT_ALLOWED},
+		{"status set by reports", `{"status":"Hidden"}`, consts.VALIDATION_NOT_ALLOWED},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := update(tc.body)
+			assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+
+			var response models.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			field := "status"
+			if tc.code == consts.VALIDATION_INVALID_JSON {
+				field = "body"
+			}
+			assertFieldError(t, response, field, tc.code)
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		rec := update(`{"status":"Active"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	})
+}
+
+func TestDraftsHandler_Submit(t *testing.T) {
+	e := echo.New()
+
+	submit := func(drafts *mockDrafts, id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/ads/drafts/"+id+"/submit", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+		c.Set("user", mockUserData[0])
+
+		d := NewDraftsHandler(mockDatastore{}, drafts)
+		serve(c, d.Submit)
+		return rec
+	}
+
+	t.Run("incomplete draft", func(t *testing.T) {
+		drafts := &mockDrafts{data: map[int]models.AdDraft{
+			1: {ID: 1, UserID: mockUserData[0].ID, Payload: `{"Subject":"Cessna 172","Price":1000}`},
+		}}
+		rec := submit(drafts, "1")
+
+		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		var response models.ErrorResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assertFieldError(t, response, "Category", consts.VALIDATION_REQUIRED)
+		assert.Equal(t, 1, len(drafts.data))
+	})
+
+	t.Run("unknown draft", func(t *testing.T) {
+		rec := submit(&mockDrafts{data: map[int]models.AdDraft{}}, "7")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("complete draft", func(t *testing.T) {
+		drafts := &mockDrafts{data: map[int]models.AdDraft{
+			1: {ID: 1, UserID: mockUserData[0].ID, Payload: `{"FlyTime":78,"AirplaneModel":"something","Price":500000,` +
+				`"Category":"small-passenger","RepairCheck":true,"ExpertCheck":false,"PlaneAge":23}`},
+		}}
+		rec := submit(drafts, "1")
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		var response models.AdResponse
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, string(consts.INACTIVE), response.Status)
+		assert.Equal(t, uint64(500000), response.Price)
+		assert.Empty(t, drafts.data)
+	})
+}
+
+type mockDrafts struct {
+	data map[int]models.AdDraft
+}
+
+func (m *mockDrafts) Create(ctx context.Context, userID uint, payload map[string]interface{}) (models.AdDraft, error) {
+	return models.AdDraft{}, errors.New("not implemented")
+}
+
+func (m *mockDrafts) Save(ctx context.Context, id int, userID uint, payload map[string]interface{}) (models.AdDraft, error) {
+	return models.AdDraft{}, errors.New("not implemented")
+}
+
+func (m *mockDrafts) List(ctx context.Context, userID uint) ([]models.AdDraft, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockDrafts) Get(ctx context.Context, id int, userID uint) (models.AdDraft, error) {
+	dr, ok := m.data[id]
+	if !ok || dr.UserID != userID {
+		return models.AdDraft{}, draft.ErrDraftNotFound
+	}
+	return dr, nil
+}
+
+func (m *mockDrafts) Delete(ctx context.Context, id int, userID uint) error {
+	delete(m.data, id)
+	return nil
+}
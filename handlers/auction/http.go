@@ -0,0 +1,210 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/datastore/auction"
+	"Airplane-Divar/models"
+	logging_service "Airplane-Divar/service/logging"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AuctionHandler struct {
+	AuctionDatastore datastore.Auction
+}
+
+func NewAuctionHandler(auctionDS datastore.Auction) *AuctionHandler {
+	return &AuctionHandler{
+		AuctionDatastore: auctionDS,
+	}
+}
+
+// @Summary Auction an ad
+// @Description Lists an ad as a timed auction. A bid in the last extension_seconds (300 by default) pushes the end back, and the highest bid only wins if it meets the reserve price.
+// @Tags auctions
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Ad ID"
+// @Param body body models.CreateAuctionRequest true "Auction"
+// @Success 201 {object} models.AuctionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /ads/{id}/auction [post]
+func (a *AuctionHandler) CreateAuction(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	if user.Role != consts.ROLE_AIRLINE {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only airline user can auction an ad")
+	}
+
+	var body models.CreateAuctionRequest
+	if err := c.Bind(&body); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	startsAt := now
+	if body.StartsAt != nil {
+		startsAt = *body.StartsAt
+	}
+	extension := uint(consts.DEFAULT_AUCTION_EXTENSION_SECS)
+	if body.ExtensionSeconds != nil {
+		extension = *body.ExtensionSeconds
+	}
+	if body.MinIncrement == 0 {
+		return apperror.InvalidBody("min_increment should be positive")
+	}
+	if !body.EndsAt.After(startsAt) || !body.EndsAt.After(now) {
+		return apperror.InvalidBody("ends_at should be in the future and after starts_at")
+	}
+
+	created, err := a.AuctionDatastore.Create(c.Request().Context(), adID, user, models.Auction{
+		ReservePrice:     body.ReservePrice,
+		MinIncrement:     body.MinIncrement,
+		StartsAt:         startsAt,
+		EndsAt:           body.EndsAt,
+		ExtensionSeconds: extension,
+	})
+	if err != nil {
+		return err
+	}
+
+	reportAuction(user, created.AdsID, consts.LOG_AUCTION_CREATE)
+
+	return c.JSON(http.StatusCreated, toAuctionResponse(created))
+}
+
+// @Summary Get the auction of an ad
+// @Tags auctions
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Ad ID"
+// @Success 200 {object} models.AuctionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /ads/{id}/auction [get]
+func (a *AuctionHandler) GetAuction(c echo.Context) error {
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	found, err := a.AuctionDatastore.GetByAd(c.Request().Context(), adID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toAuctionResponse(found))
+}
+
+// @Summary List bids of an auction
+// @Tags auctions
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Auction ID"
+// @Success 200 {array} models.BidResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /auctions/{id}/bids [get]
+func (a *AuctionHandler) ListBids(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	bids, err := a.AuctionDatastore.Bids(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	resp := []models.BidResponse{}
+	for _, bid := range bids {
+		resp = append(resp, models.BidResponse{
+			ID:        bid.ID,
+			UserID:    bid.UserID,
+			Amount:    bid.Amount,
+			CreatedAt: bid.CreatedAt,
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Place a bid
+// @Description Bid on an open auction. The bid must be at least the auction's minimumBid.
+// @Tags auctions
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Auction ID"
+// @Param body body models.BidRequest true "Bid"
+// @Success 201 {object} models.AuctionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /auctions/{id}/bids [post]
+func (a *AuctionHandler) PlaceBid(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	if user.Role != consts.ROLE_AIRLINE {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only airline user can bid")
+	}
+
+	var body models.BidRequest
+	if err := c.Bind(&body); err != nil {
+		return err
+	}
+
+	updated, err := a.AuctionDatastore.PlaceBid(c.Request().Context(), id, user, body.Amount)
+	if err != nil {
+		return err
+	}
+
+	reportAuction(user, updated.AdsID, consts.LOG_AUCTION_BID)
+
+	return c.JSON(http.StatusCreated, toAuctionResponse(updated))
+}
+
+func reportAuction(user models.User, adID uint, logName string) {
+	// ____ Report Log ____
+	logService := logging_service.GetInstance()
+	if logService != (*logging_service.Logging)(nil) {
+		err := logService.ReportActivity(user.Role, user.ID, "Ads", adID, logName, "")
+		if err != nil {
+			_ = fmt.Errorf("cannot log activity %v", logName)
+		}
+	}
+	// ____ Report Log ____
+}
+
+func toAuctionResponse(a models.Auction) models.AuctionResponse {
+	return models.AuctionResponse{
+		ID:               a.ID,
+		AdID:             a.AdsID,
+		MinIncrement:     a.MinIncrement,
+		StartsAt:         a.StartsAt,
+		EndsAt:           a.EndsAt,
+		ExtensionSeconds: a.ExtensionSeconds,
+		HighestBid:       a.HighestBid,
+		BidCount:         a.BidCount,
+		MinimumBid:       auction.MinimumBid(a),
+		ReserveMet:       a.BidCount > 0 && a.HighestBid >= a.ReservePrice,
+		Status:           string(a.Status),
+		WinnerID:         a.WinnerID,
+	}
+}
@@ -0,0 +1,303 @@
+package bookmarks
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	bookmarkDatastore "Airplane-Divar/datastore/bookmarks"
+	"Airplane-Divar/models"
+	"Airplane-Divar/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+const maxCollectionName = 100
+
+type CollectionsHandler struct {
+	collections datastore.BookmarkCollection
+}
+
+func NewCollectionsHandler(collections datastore.BookmarkCollection) *CollectionsHandler {
+	return &CollectionsHandler{collections: collections}
+}
+
+// @Summary Create a bookmark collection
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param body body models.BookmarkCollectionRequest true "Collection"
+// @Success 201 {object} models.BookmarkCollectionResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /bookmarks/collections [post]
+func (h CollectionsHandler) Create(c echo.Context) error {
+	user, err := airline(c)
+	if err != nil {
+		return err
+	}
+
+	name, err := bindCollectionName(c)
+	if err != nil {
+		return err
+	}
+
+	created, err := h.collections.Create(c.Request().Context(), user.ID, name)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, toCollectionResponse(created))
+}
+
+// @Summary List bookmark collections
+// @Tags bookmarks
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Success 200 {array} models.BookmarkCollectionResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /bookmarks/collections [get]
+func (h CollectionsHandler) List(c echo.Context) error {
+	user, err := airline(c)
+	if err != nil {
+		return err
+	}
+
+	collections, err := h.collections.List(c.Request().Context(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]models.BookmarkCollectionResponse, 0, len(collections))
+	for _, collection := range collections {
+		resp = append(resp, toCollectionResponse(collection))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Rename a bookmark collection
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Collection ID"
+// @Param body body models.BookmarkCollectionRequest true "Collection"
+// @Success 200 {object} models.BookmarkCollectionResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /bookmarks/collections/{id} [put]
+func (h CollectionsHandler) Rename(c echo.Context) error {
+	user, err := airline(c)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	name, err := bindCollectionName(c)
+	if err != nil {
+		return err
+	}
+
+	renamed, err := h.collections.Rename(c.Request().Context(), id, user.ID, name)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toCollectionResponse(renamed))
+}
+
+// @Summary Delete a bookmark collection
+// @Description Deletes the collection, the bookmarks in it are kept
+// @Tags bookmarks
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Collection ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /bookmarks/collections/{id} [delete]
+func (h CollectionsHandler) Delete(c echo.Context) error {
+	user, err := airline(c)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	if err := h.collections.Delete(c.Request().Context(), id, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, models.SuccessResponse{Success: true})
+}
+
+// @Summary Add a bookmark to a collection
+// @Tags bookmarks
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Collection ID"
+// @Param adID path int true "Bookmarked ad ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /bookmarks/collections/{id}/ads/{adID} [put]
+func (h CollectionsHandler) AddBookmark(c echo.Context) error {
+	user, id, adID, err := collectionItem(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.collections.Add(c.Request().Context(), id, user.ID, adID); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, models.SuccessResponse{Success: true})
+}
+
+// @Summary Remove a bookmark from a collection
+// @Tags bookmarks
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Collection ID"
+// @Param adID path int true "Bookmarked ad ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /bookmarks/collections/{id}/ads/{adID} [delete]
+func (h CollectionsHandler) RemoveBookmark(c echo.Context) error {
+	user, id, adID, err := collectionItem(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.collections.Remove(c.Request().Context(), id, user.ID, adID); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, models.SuccessResponse{Success: true})
+}
+
+// @Summary Note a bookmark
+// @Description Replaces the private note and tags of a bookmark
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Bookmarked ad ID"
+// @Param body body models.BookmarkNoteRequest true "Note and tags"
+// @Success 200 {object} models.BookmarkNoteResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /bookmarks/note/{id} [put]
+func (h CollectionsHandler) Annotate(c echo.Context) error {
+	user, err := airline(c)
+	if err != nil {
+		return err
+	}
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	var body models.BookmarkNoteRequest
+	errs := utils.BindJSON(c.Request().Body, &body)
+	tags := []string{}
+	if !errs.Has("body") && !errs.Has("tags") {
+		tags = normalizeTags(body.Tags, &errs)
+	}
+	if len(errs) > 0 {
+		return errs.Err()
+	}
+
+	bookmark, err := h.collections.Annotate(c.Request().Context(), user.ID, adID, strings.TrimSpace(body.Note), tags)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, models.BookmarkNoteResponse{
+		AdID: bookmark.AdsID,
+		Note: bookmark.Note,
+		Tags: bookmarkDatastore.SplitTags(bookmark.Tags),
+	})
+}
+
+// airline is the logged in user, only airlines keep bookmarks.
+func airline(c echo.Context) (models.User, error) {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_AIRLINE {
+		return models.User{}, apperror.Forbidden(apperror.CodeForbidden, "Airlines Can manage bookmarks!")
+	}
+	return user, nil
+}
+
+func collectionItem(c echo.Context) (models.User, int, int, error) {
+	user, err := airline(c)
+	if err != nil {
+		return models.User{}, 0, 0, err
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return models.User{}, 0, 0, apperror.InvalidParameter("id")
+	}
+	adID, err := strconv.Atoi(c.Param("adID"))
+	if err != nil {
+		return models.User{}, 0, 0, apperror.InvalidParameter("adID")
+	}
+	return user, id, adID, nil
+}
+
+func bindCollectionName(c echo.Context) (string, error) {
+	var body models.BookmarkCollectionRequest
+	errs := utils.BindJSON(c.Request().Body, &body)
+	name := strings.TrimSpace(body.Name)
+	if !errs.Has("body") && !errs.Has("name") {
+		if name == "" {
+			errs.Add("name", consts.VALIDATION_REQUIRED, "name is required !")
+		} else if len(name) > maxCollectionName {
+			errs.Add("name", consts.VALIDATION_OUT_OF_RANGE, fmt.Sprintf("name should be at most %d characters !", maxCollectionName))
+		}
+	}
+	return name, errs.Err()
+}
+
+// normalizeTags trims the tags and drops empty and repeated ones. Tags are
+// stored comma separated so they can't contain commas.
+func normalizeTags(tags []string, errs *utils.ValidationErrors) []string {
+	normalized := []string{}
+	seen := map[string]bool{}
+	for i, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if strings.Contains(tag, ",") {
+			errs.Add(fmt.Sprintf("tags[%d]", i), consts.VALIDATION_INVALID, "tags can't contain commas !")
+			continue
+		}
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+func toCollectionResponse(collection models.BookmarkCollection) models.BookmarkCollectionResponse {
+	return models.BookmarkCollectionResponse{
+		ID:        collection.ID,
+		Name:      collection.Name,
+		Size:      collection.Size,
+		CreatedAt: collection.CreatedAt,
+	}
+}
@@ -1,6 +1,7 @@
 package bookmarks
 
 import (
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/consts"
 	"Airplane-Divar/datastore"
 	"Airplane-Divar/models"
@@ -20,31 +21,36 @@ func New(bookmarks datastore.Bookmark) *BookmarkssHandler {
 	return &BookmarkssHandler{datastore: bookmarks}
 }
 
-type ErrorAddAd struct {
-	ResponseCode int    `json:"responsecode"`
-	Message      string `json:"message"`
-}
-
 // Get list of all bookmarks.
 // @Summary bookmarks list
-// @Description Retrieves all bookmarks of this user
+// @Description Retrieves a page of the bookmarks of this user, ads that are not available anymore are marked instead of dropped
 // @Tags bookmarks
 // @Accept json
 // @Produce json
 // @Security ApiKeyAuth
 // @Param Authorization header string true "User Token"
-// @Success 200 {object} []models.AdResponse
-// @Failure 403 {object} ErrorAddAd
-// @Failure 500 {object} ErrorAddAd
+// @Param collection query int false "Only bookmarks in this collection"
+// @Param page query int false "Page number"
+// @Param sort query string false "Sort by" Enums(bookmarked_at, price)
+// @Param order query string false "Sort order" Enums(asc, desc)
+// @Success 200 {object} []models.BookmarkedAdResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /bookmarks/list [get]
 func (b BookmarkssHandler) ListBookmarks(c echo.Context) error {
 	user := c.Get("user").(models.User)
 	if user.Role != consts.ROLE_AIRLINE {
-		return c.JSON(http.StatusForbidden, models.Response{ResponseCode: 403, Message: "Airlines Can see bookmarks!"})
+		return apperror.Forbidden(apperror.CodeForbidden, "Airlines Can see bookmarks!")
+	}
+	query, err := listQuery(c)
+	if err != nil {
+		return err
 	}
-	ads, err := b.datastore.GetAdsByUserID(int(user.ID))
+	ads, err := b.datastore.GetAdsByUserID(int(user.ID), query)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.Response{ResponseCode: 500, Message: err.Error()})
+		return err
 	}
 	if len(ads) == 0 {
 		return c.JSON(http.StatusOK, models.Response{ResponseCode: 200, Message: "You don't have any bookmark"})
@@ -52,6 +58,39 @@ func (b BookmarkssHandler) ListBookmarks(c echo.Context) error {
 	return c.JSON(http.StatusOK, ads)
 }
 
+// listQuery reads the listing query parameters, newest bookmarks come first
+// by default.
+func listQuery(c echo.Context) (models.BookmarkListQuery, error) {
+	query := models.BookmarkListQuery{Sort: consts.BOOKMARK_SORT_DATE, Desc: true}
+
+	var err error
+	if collection := c.QueryParam("collection"); collection != "" {
+		if query.CollectionID, err = strconv.Atoi(collection); err != nil {
+			return query, apperror.InvalidParameter("collection")
+		}
+	}
+	if page := c.QueryParam("page"); page != "" {
+		if query.Page, err = strconv.Atoi(page); err != nil || query.Page < 1 {
+			return query, apperror.InvalidParameter("page")
+		}
+	}
+	switch sort := c.QueryParam("sort"); sort {
+	case "":
+	case consts.BOOKMARK_SORT_DATE, consts.BOOKMARK_SORT_PRICE:
+		query.Sort = sort
+	default:
+		return query, apperror.InvalidParameter("sort")
+	}
+	switch order := c.QueryParam("order"); order {
+	case "":
+	case "asc", "desc":
+		query.Desc = order == "desc"
+	default:
+		return query, apperror.InvalidParameter("order")
+	}
+	return query, nil
+}
+
 // add a new bookmark.
 // @Summary add bookmark
 // @Description add bookmark using given ad id
@@ -62,23 +101,25 @@ func (b BookmarkssHandler) ListBookmarks(c echo.Context) error {
 // @Param Authorization header string true "User Token"
 // @Param id path int true "Ad ID"
 // @Success 200 {object} models.BookmarksResponse
-// @Failure 403 {object} ErrorAddAd
-// @Failure 422 {object} ErrorAddAd
-// @Failure 500 {object} ErrorAddAd
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /bookmarks/add/{id} [put]
 func (b BookmarkssHandler) AddBookmark(c echo.Context) error {
 	user := c.Get("user").(models.User)
 	if user.Role != consts.ROLE_AIRLINE {
-		return c.JSON(http.StatusForbidden, models.Response{ResponseCode: 403, Message: "Airlines Can add bookmark!"})
+		return apperror.Forbidden(apperror.CodeForbidden, "Airlines Can add bookmark!")
 	}
 	ad_id := c.Param("id")
 	ad_id_int, err := strconv.Atoi(ad_id)
 	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: "Id should be integer!"})
+		return apperror.InvalidParameter("id")
 	}
 	bookmark, err := b.datastore.AddBookmark(int(user.ID), ad_id_int)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.Response{ResponseCode: 500, Message: err.Error()})
+		return err
 	}
 
 	// ------ Report Log ------
@@ -106,23 +147,23 @@ func (b BookmarkssHandler) AddBookmark(c echo.Context) error {
 // @Param Authorization header string true "User Token"
 // @Param id path int true "Ad ID"
 // @Success 200 {string} string "Bookmark Deleted Successfully"
-// @Failure 403 {object} ErrorAddAd
-// @Failure 422 {object} ErrorAddAd
-// @Failure 500 {object} ErrorAddAd
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /bookmarks/delete/{id} [delete]
 func (b BookmarkssHandler) DeleteBookmark(c echo.Context) error {
 	user := c.Get("user").(models.User)
 	if user.Role != consts.ROLE_AIRLINE {
-		return c.JSON(http.StatusForbidden, models.Response{ResponseCode: 403, Message: "Airlines Can delete bookmark!"})
+		return apperror.Forbidden(apperror.CodeForbidden, "Airlines Can delete bookmark!")
 	}
 	ad_id := c.Param("id")
 	ad_id_int, err := strconv.Atoi(ad_id)
 	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: "Id should be integer!"})
+		return apperror.InvalidParameter("id")
 	}
-	err_delete := b.datastore.DeleteBookmark(int(user.ID), ad_id_int)
-	if err_delete != nil {
-		return c.JSON(http.StatusInternalServerError, models.Response{ResponseCode: 500, Message: err_delete.Error()})
+	if err := b.datastore.DeleteBookmark(int(user.ID), ad_id_int); err != nil {
+		return err
 	}
 
 	// ------ Report Log ------
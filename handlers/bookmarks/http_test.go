@@ -1,10 +1,12 @@
 package bookmarks
 
 import (
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/consts"
+	bookmarkDatastore "Airplane-Divar/datastore/bookmarks"
+	"Airplane-Divar/middlewares"
 	"Airplane-Divar/models"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -13,6 +15,13 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+// serve runs the handler and renders its error the way the server does.
+func serve(c echo.Context, h echo.HandlerFunc) {
+	if err := h(c); err != nil {
+		middlewares.ErrorHandler(err, c)
+	}
+}
+
 func TestBookmarkHandler_ListBookmarks(t *testing.T) {
 	e := echo.New()
 
@@ -25,16 +34,15 @@ func TestBookmarkHandler_ListBookmarks(t *testing.T) {
 		c.Set("user", mockUserData[2])
 
 		a := New(mockDatastore{})
-		err := a.ListBookmarks(c)
-
-		assert.NoError(t, err)
+		serve(c, a.ListBookmarks)
 		assert.Equal(t, http.StatusForbidden, rec.Code)
 
-		var response models.Response
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var response models.ErrorResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Airlines Can see bookmarks!", response.Message)
+		assert.Equal(t, apperror.CodeForbidden, response.Error.Code)
+		assert.Equal(t, "Airlines Can see bookmarks!", response.Error.Message)
 	})
 
 	t.Run("zero bookmark", func(t *testing.T) {
@@ -46,13 +54,11 @@ func TestBookmarkHandler_ListBookmarks(t *testing.T) {
 		c.Set("user", mockUserData[3])
 
 		a := New(mockDatastore{})
-		err := a.ListBookmarks(c)
-
-		assert.NoError(t, err)
+		serve(c, a.ListBookmarks)
 		assert.Equal(t, http.StatusOK, rec.Code)
 
 		var response models.Response
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
 		assert.Equal(t, "You don't have any bookmark", response.Message)
@@ -67,19 +73,101 @@ func TestBookmarkHandler_ListBookmarks(t *testing.T) {
 		c.Set("user", mockUserData[0])
 
 		a := New(mockDatastore{})
-		err := a.ListBookmarks(c)
+		serve(c, a.ListBookmarks)
+		assert.Equal(t, http.StatusOK, rec.Code)
 
+		var response []models.AdResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
+
+		assert.Equal(t, 1, len(response))
+	})
+
+	t.Run("by collection", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/bookmarks/list?collection=1", nil)
+
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.Set("user", mockUserData[1])
+
+		a := New(mockDatastore{})
+		serve(c, a.ListBookmarks)
 		assert.Equal(t, http.StatusOK, rec.Code)
 
-		var response []models.AdResponse
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var response []models.BookmarkedAdResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
 		assert.Equal(t, 1, len(response))
+		assert.Equal(t, mockAdData[0].ID, response[0].ID)
+	})
+
+	t.Run("unknown collection", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/bookmarks/list?collection=9", nil)
+
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.Set("user", mockUserData[1])
+
+		a := New(mockDatastore{})
+		serve(c, a.ListBookmarks)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+
+		var response models.ErrorResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "collection_not_found", response.Error.Code)
+	})
+
+	t.Run("non-integer collection", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/bookmarks/list?collection=fleet", nil)
+
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.Set("user", mockUserData[1])
+
+		a := New(mockDatastore{})
+		serve(c, a.ListBookmarks)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("unknown sort", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/bookmarks/list?sort=subject", nil)
+
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.Set("user", mockUserData[1])
+
+		a := New(mockDatastore{})
+		serve(c, a.ListBookmarks)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+		var response models.ErrorResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
+		assert.NoError(t, err)
+
+		assert.Equal(t, apperror.CodeInvalidParameter, response.Error.Code)
 	})
 
+	t.Run("invalid page", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/bookmarks/list?page=0", nil)
+
+		rec := httptest.NewRecorder()
+
+		c := e.NewContext(req, rec)
+		c.Set("user", mockUserData[1])
+
+		a := New(mockDatastore{})
+		serve(c, a.ListBookmarks)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
 }
+
 func TestBookmarkHandler_AddBookmark(t *testing.T) {
 	e := echo.New()
 	t.Run("non-airline user", func(t *testing.T) {
@@ -91,16 +179,15 @@ func TestBookmarkHandler_AddBookmark(t *testing.T) {
 		c.Set("user", mockUserData[2])
 
 		a := New(mockDatastore{})
-		err := a.AddBookmark(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddBookmark)
 		assert.Equal(t, http.StatusForbidden, rec.Code)
 
-		var response models.Response
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var response models.ErrorResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Airlines Can add bookmark!", response.Message)
+		assert.Equal(t, apperror.CodeForbidden, response.Error.Code)
+		assert.Equal(t, "Airlines Can add bookmark!", response.Error.Message)
 	})
 
 	t.Run("non-integer id", func(t *testing.T) {
@@ -114,16 +201,15 @@ func TestBookmarkHandler_AddBookmark(t *testing.T) {
 		c.SetParamValues("salam")
 
 		a := New(mockDatastore{})
-		err := a.AddBookmark(c)
-
-		assert.NoError(t, err)
-		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+		serve(c, a.AddBookmark)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
 
-		var response models.Response
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var response models.ErrorResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Id should be integer!", response.Message)
+		assert.Equal(t, apperror.CodeInvalidParameter, response.Error.Code)
+		assert.Equal(t, "invalid parameter id", response.Error.Message)
 	})
 
 	t.Run("invalid id", func(t *testing.T) {
@@ -137,14 +223,14 @@ func TestBookmarkHandler_AddBookmark(t *testing.T) {
 		c.SetParamValues("123")
 
 		a := New(mockDatastore{})
-		err := a.AddBookmark(c)
+		serve(c, a.AddBookmark)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
 
+		var response models.ErrorResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 
-		var response models.Response
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
-		assert.NoError(t, err)
+		assert.Equal(t, "ad_not_found", response.Error.Code)
 	})
 
 	t.Run("inactive ad", func(t *testing.T) {
@@ -158,14 +244,14 @@ func TestBookmarkHandler_AddBookmark(t *testing.T) {
 		c.SetParamValues("3")
 
 		a := New(mockDatastore{})
-		err := a.AddBookmark(c)
+		serve(c, a.AddBookmark)
+		assert.Equal(t, http.StatusConflict, rec.Code)
 
+		var response models.ErrorResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 
-		var response models.Response
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
-		assert.NoError(t, err)
+		assert.Equal(t, "ad_not_active", response.Error.Code)
 	})
 
 	t.Run("valid", func(t *testing.T) {
@@ -179,13 +265,11 @@ func TestBookmarkHandler_AddBookmark(t *testing.T) {
 		c.SetParamValues("2")
 
 		a := New(mockDatastore{})
-		err := a.AddBookmark(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddBookmark)
 		assert.Equal(t, http.StatusOK, rec.Code)
 
 		var response models.Response
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 	})
 }
@@ -204,16 +288,15 @@ func TestBookmarkHandler_DeleteBookmark(t *testing.T) {
 		c.SetParamValues("1")
 
 		a := New(mockDatastore{})
-		err := a.DeleteBookmark(c)
-
-		assert.NoError(t, err)
+		serve(c, a.DeleteBookmark)
 		assert.Equal(t, http.StatusForbidden, rec.Code)
 
-		var response models.Response
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		var response models.ErrorResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 
-		assert.Equal(t, "Airlines Can delete bookmark!", response.Message)
+		assert.Equal(t, apperror.CodeForbidden, response.Error.Code)
+		assert.Equal(t, "Airlines Can delete bookmark!", response.Error.Message)
 	})
 
 	t.Run("non-integer id", func(t *testing.T) {
@@ -227,16 +310,15 @@ func TestBookmarkHandler_DeleteBookmark(t *testing.T) {
 		c.SetParamValues("salam")
 
 		a := New(mockDatastore{})
-		err := a.DeleteBookmark(c)
+		serve(c, a.DeleteBookmark)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
 
+		var response models.ErrorResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
 
-		var response models.Response
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
-		assert.NoError(t, err)
-
-		assert.Equal(t, "Id should be integer!", response.Message)
+		assert.Equal(t, apperror.CodeInvalidParameter, response.Error.Code)
+		assert.Equal(t, "invalid parameter id", response.Error.Message)
 	})
 
 	t.Run("invalid id", func(t *testing.T) {
@@ -250,14 +332,14 @@ func TestBookmarkHandler_DeleteBookmark(t *testing.T) {
 		c.SetParamValues("100")
 
 		a := New(mockDatastore{})
-		err := a.AddBookmark(c)
+		serve(c, a.AddBookmark)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
 
+		var response models.ErrorResponse
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.Equal(t, http.StatusInternalServerError, rec.Code)
 
-		var response models.Response
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
-		assert.NoError(t, err)
+		assert.Equal(t, "ad_not_found", response.Error.Code)
 	})
 
 	t.Run("valid", func(t *testing.T) {
@@ -271,13 +353,11 @@ func TestBookmarkHandler_DeleteBookmark(t *testing.T) {
 		c.SetParamValues("1")
 
 		a := New(mockDatastore{})
-		err := a.AddBookmark(c)
-
-		assert.NoError(t, err)
+		serve(c, a.AddBookmark)
 		assert.Equal(t, http.StatusOK, rec.Code)
 
 		var response models.Response
-		err = json.Unmarshal(rec.Body.Bytes(), &response)
+		err := json.Unmarshal(rec.Body.Bytes(), &response)
 		assert.NoError(t, err)
 	})
 
@@ -388,20 +468,29 @@ var (
 	}
 )
 
-func (m mockDatastore) GetAdsByUserID(id int) ([]models.AdResponse, error) {
-	if id == 1 {
-		return []models.AdResponse{mockAdData[0]}, nil
+func (m mockDatastore) GetAdsByUserID(id int, query models.BookmarkListQuery) ([]models.BookmarkedAdResponse, error) {
+	if query.CollectionID != 0 && query.CollectionID != 1 {
+		return nil, bookmarkDatastore.ErrCollectionNotFound
+	}
+	ads := mockAdData
+	if id == 1 || query.CollectionID == 1 {
+		ads = mockAdData[:1]
 	} else if id == 2 {
-		return mockAdData[0:2], nil
+		ads = mockAdData[0:2]
 	} else if id == 4 {
-		return []models.AdResponse{}, nil
+		ads = nil
+	}
+
+	bookmarked := []models.BookmarkedAdResponse{}
+	for _, ad := range ads {
+		bookmarked = append(bookmarked, models.BookmarkedAdResponse{AdResponse: ad})
 	}
-	return mockAdData, nil
+	return bookmarked, nil
 }
 
 func (m mockDatastore) AddBookmark(userID, adID int) (models.BookmarksResponse, error) {
-	if adID < 0 || adID > 2 {
-		return models.BookmarksResponse{}, errors.New("")
+	if adID < 0 || adID > len(mockAdData) {
+		return models.BookmarksResponse{}, bookmarkDatastore.ErrAdNotFound
 	}
 	found := false
 	for _, v := range m.bookmarks_data {
@@ -411,7 +500,7 @@ func (m mockDatastore) AddBookmark(userID, adID int) (models.BookmarksResponse,
 		}
 	}
 	if found {
-		return models.BookmarksResponse{}, errors.New("")
+		return models.BookmarksResponse{}, bookmarkDatastore.ErrAlreadyBookmarked
 	}
 	isActive := true
 	for _, v := range mockAdData {
@@ -423,7 +512,7 @@ func (m mockDatastore) AddBookmark(userID, adID int) (models.BookmarksResponse,
 		}
 	}
 	if !isActive {
-		return models.BookmarksResponse{}, errors.New("")
+		return models.BookmarksResponse{}, bookmarkDatastore.ErrAdNotActive
 	}
 	b := models.BookmarksResponse{
 		UserID: uint(userID),
@@ -446,5 +535,5 @@ func (m mockDatastore) DeleteBookmark(userID, adID int) error {
 	if len(m.bookmarks_data)+1 == ex_size {
 		return nil
 	}
-	return errors.New("")
+	return bookmarkDatastore.ErrNotBookmarked
 }
@@ -0,0 +1,240 @@
+package bookmarks
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	logging_service "Airplane-Divar/service/logging"
+	"Airplane-Divar/utils"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultShareHours = 7 * 24
+	maxShareHours     = 30 * 24
+
+	// guestCauser is who the views of a shared collection are logged as.
+	guestCauser = "Guest"
+)
+
+type SharesHandler struct {
+	shares datastore.BookmarkShare
+}
+
+func NewSharesHandler(shares datastore.BookmarkShare) *SharesHandler {
+	return &SharesHandler{shares: shares}
+}
+
+// @Summary Share a bookmark collection
+// @Description Creates a link anyone can use to read the collection until it expires or is revoked. The token is only returned here.
+// @Tags bookmarks
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Collection ID"
+// @Param body body models.BookmarkShareRequest false "Expiry, 7 days by default"
+// @Success 201 {object} models.BookmarkShareResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /bookmarks/collections/{id}/shares [post]
+func (h SharesHandler) Create(c echo.Context) error {
+	user, err := airline(c)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	body := models.BookmarkShareRequest{ExpiresInHours: defaultShareHours}
+	if c.Request().ContentLength != 0 {
+		errs := utils.BindJSON(c.Request().Body, &body)
+		if !errs.Has("body") && !errs.Has("expires_in_hours") && (body.ExpiresInHours < 1 || body.ExpiresInHours > maxShareHours) {
+			errs.Add("expires_in_hours", consts.VALIDATION_OUT_OF_RANGE, fmt.Sprintf("expires_in_hours should be between 1 and %d !", maxShareHours))
+		}
+		if err := errs.Err(); err != nil {
+			return err
+		}
+	}
+
+	token, err := newShareToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(time.Duration(body.ExpiresInHours) * time.Hour)
+	share, err := h.shares.Create(c.Request().Context(), id, user.ID, token, expiresAt)
+	if err != nil {
+		return err
+	}
+	logShareActivity(user.Role, user.ID, share.ID, consts.LOG_BOOKMARK_SHARE_CREATE, fmt.Sprintf("collection %d", share.CollectionID))
+
+	resp := toShareResponse(share)
+	resp.Token = token
+	return c.JSON(http.StatusCreated, resp)
+}
+
+// @Summary List the shares of a bookmark collection
+// @Tags bookmarks
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Collection ID"
+// @Success 200 {array} models.BookmarkShareResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /bookmarks/collections/{id}/shares [get]
+func (h SharesHandler) List(c echo.Context) error {
+	user, err := airline(c)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	shares, err := h.shares.List(c.Request().Context(), id, user.ID)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]models.BookmarkShareResponse, 0, len(shares))
+	for _, share := range shares {
+		resp = append(resp, toShareResponse(share))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Revoke a share of a bookmark collection
+// @Tags bookmarks
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Share ID"
+// @Success 200 {object} models.BookmarkShareResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /bookmarks/shares/{id} [delete]
+func (h SharesHandler) Revoke(c echo.Context) error {
+	user, err := airline(c)
+	if err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	share, err := h.shares.Revoke(c.Request().Context(), id, user.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	logShareActivity(user.Role, user.ID, share.ID, consts.LOG_BOOKMARK_SHARE_REVOKE, "")
+
+	return c.JSON(http.StatusOK, toShareResponse(share))
+}
+
+// @Summary Read a shared bookmark collection
+// @Description Read-only view of a shared collection, no account needed. Every view is logged.
+// @Tags bookmarks
+// @Produce json
+// @Param token path string true "Share token"
+// @Param page query int false "Page number"
+// @Success 200 {object} models.SharedBookmarksResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /shared/bookmarks/{token} [get]
+func (h SharesHandler) View(c echo.Context) error {
+	page := 1
+	if p := c.QueryParam("page"); p != "" {
+		var err error
+		if page, err = strconv.Atoi(p); err != nil || page < 1 {
+			return apperror.InvalidParameter("page")
+		}
+	}
+
+	ctx := c.Request().Context()
+	share, collection, err := h.shares.Resolve(ctx, c.Param("token"), time.Now())
+	if err != nil {
+		return err
+	}
+	logShareActivity(guestCauser, 0, share.ID, consts.LOG_BOOKMARK_SHARE_VIEW, c.RealIP())
+
+	ads, err := h.shares.Ads(ctx, share, page)
+	if err != nil {
+		return err
+	}
+
+	resp := models.SharedBookmarksResponse{
+		Name:      collection.Name,
+		ExpiresAt: share.ExpiresAt,
+		Ads:       make([]models.SharedAdResponse, 0, len(ads)),
+	}
+	for _, ad := range ads {
+		resp.Ads = append(resp.Ads, toSharedAd(ad))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func newShareToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func logShareActivity(causerType string, causerID uint, shareID uint, logName string, description string) {
+	// ____ Report Log ____
+	logService := logging_service.GetInstance()
+	if logService != (*logging_service.Logging)(nil) {
+		err := logService.ReportActivity(causerType, causerID, "BookmarkShare", shareID, logName, description)
+		if err != nil {
+			_ = fmt.Errorf("cannot log activity %v", logName)
+		}
+	}
+	// ____ Report Log ____
+}
+
+func toShareResponse(share models.BookmarkShare) models.BookmarkShareResponse {
+	return models.BookmarkShareResponse{
+		ID:           share.ID,
+		CollectionID: share.CollectionID,
+		ExpiresAt:    share.ExpiresAt,
+		RevokedAt:    share.RevokedAt,
+		CreatedAt:    share.CreatedAt,
+	}
+}
+
+// toSharedAd leaves out the seller and the notes of the owner. Ads that are
+// gone or hidden by moderation only keep their ID.
+func toSharedAd(ad models.BookmarkedAdResponse) models.SharedAdResponse {
+	if ad.Availability == consts.BOOKMARK_REMOVED || consts.AdStatus(ad.Status) == consts.HIDDEN {
+		return models.SharedAdResponse{ID: ad.ID, Availability: consts.BOOKMARK_UNAVAILABLE}
+	}
+	return models.SharedAdResponse{
+		ID:            ad.ID,
+		Image:         ad.Image,
+		Description:   ad.Description,
+		Subject:       ad.Subject,
+		Price:         ad.Price,
+		CategoryID:    ad.CategoryID,
+		FlyTime:       ad.FlyTime,
+		AirplaneModel: ad.AirplaneModel,
+		RepairCheck:   ad.RepairCheck,
+		ExpertCheck:   ad.ExpertCheck,
+		PlaneAge:      ad.PlaneAge,
+		Availability:  ad.Availability,
+	}
+}
@@ -0,0 +1,65 @@
+package bookmarks
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type WatchHandler struct {
+	watch datastore.BookmarkWatch
+}
+
+func NewWatchHandler(watch datastore.BookmarkWatch) *WatchHandler {
+	return &WatchHandler{watch: watch}
+}
+
+// @Summary Mute a bookmark
+// @Description Stops the notifications about changes of a bookmarked ad
+// @Tags bookmarks
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Bookmarked ad ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /bookmarks/mute/{id} [put]
+func (h WatchHandler) Mute(c echo.Context) error {
+	return h.setMuted(c, true)
+}
+
+// @Summary Unmute a bookmark
+// @Description Resumes the notifications about changes of a bookmarked ad
+// @Tags bookmarks
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Bookmarked ad ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /bookmarks/mute/{id} [delete]
+func (h WatchHandler) Unmute(c echo.Context) error {
+	return h.setMuted(c, false)
+}
+
+func (h WatchHandler) setMuted(c echo.Context, muted bool) error {
+	user, err := airline(c)
+	if err != nil {
+		return err
+	}
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	if err := h.watch.SetMuted(c.Request().Context(), user.ID, adID, muted); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, models.SuccessResponse{Success: true})
+}
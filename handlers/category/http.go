@@ -0,0 +1,399 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CategoryHandler struct {
+	CategoryDatastore datastore.Category
+}
+
+func NewCategoryHandler(categoryDS datastore.Category) *CategoryHandler {
+	return &CategoryHandler{
+		CategoryDatastore: categoryDS,
+	}
+}
+
+// @Summary List categories
+// @Description Lists the categories that new ads can use, in display order
+// @Tags categories
+// @Produce json
+// @Success 200 {array} models.CategoryResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /categories [get]
+func (h *CategoryHandler) ListCategories(c echo.Context) error {
+	categories, err := h.CategoryDatastore.List(c.Request().Context(), false)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toCategoryResponses(categories))
+}
+
+// @Summary List categories for admin
+// @Description Lists all categories including retired ones
+// @Tags categories
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Success 200 {array} models.CategoryResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/categories [get]
+func (h *CategoryHandler) AdminListCategories(c echo.Context) error {
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
+	categories, err := h.CategoryDatastore.List(c.Request().Context(), true)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toCategoryResponses(categories))
+}
+
+// @Summary Create category
+// @Description Create a new category, optionally under a parent category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param body body models.CreateCategoryRequest true "Category"
+// @Success 201 {object} models.CategoryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/categories [post]
+func (h *CategoryHandler) CreateCategory(c echo.Context) error {
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
+	var body models.CreateCategoryRequest
+	if err := c.Bind(&body); err != nil {
+		return err
+	}
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Name == "" {
+		return apperror.InvalidBody("name is required")
+	}
+
+	created, err := h.CategoryDatastore.Create(c.Request().Context(), body.Name, body.Position, body.ParentID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, toCategoryResponse(created))
+}
+
+// @Summary Rename category
+// @Description Rename an existing category
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Category ID"
+// @Param body body models.RenameCategoryRequest true "New name"
+// @Success 200 {object} models.CategoryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/categories/{id} [put]
+func (h *CategoryHandler) RenameCategory(c echo.Context) error {
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	var body models.RenameCategoryRequest
+	if err := c.Bind(&body); err != nil {
+		return err
+	}
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Name == "" {
+		return apperror.InvalidBody("name is required")
+	}
+
+	renamed, err := h.CategoryDatastore.Rename(c.Request().Context(), id, body.Name)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toCategoryResponse(renamed))
+}
+
+// @Summary Move category
+// @Description Put a category under another parent, or at the top level when parent_id is null
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Category ID"
+// @Param body body models.MoveCategoryRequest true "New parent"
+// @Success 200 {object} models.CategoryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/categories/{id}/parent [put]
+func (h *CategoryHandler) MoveCategory(c echo.Context) error {
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	var body models.MoveCategoryRequest
+	if err := c.Bind(&body); err != nil {
+		return err
+	}
+
+	moved, err := h.CategoryDatastore.Move(c.Request().Context(), id, body.ParentID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toCategoryResponse(moved))
+}
+
+// @Summary List category attributes
+// @Description Lists the attributes ads of a category can carry, including the ones inherited from parent categories
+// @Tags categories
+// @Produce json
+// @Param id path int true "Category ID"
+// @Success 200 {array} models.CategoryAttributeResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /categories/{id}/attributes [get]
+func (h *CategoryHandler) ListAttributes(c echo.Context) error {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	attributes, err := h.CategoryDatastore.Attributes(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+
+	resp := []models.CategoryAttributeResponse{}
+	for _, attr := range attributes {
+		resp = append(resp, toAttributeResponse(attr))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Create category attribute
+// @Description Declare a typed attribute for ads of a category and its sub categories
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Category ID"
+// @Param body body models.CreateCategoryAttributeRequest true "Attribute"
+// @Success 201 {object} models.CategoryAttributeResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/categories/{id}/attributes [post]
+func (h *CategoryHandler) CreateAttribute(c echo.Context) error {
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	var body models.CreateCategoryAttributeRequest
+	if err := c.Bind(&body); err != nil {
+		return err
+	}
+	body.Name = strings.TrimSpace(body.Name)
+	if body.Name == "" {
+		return apperror.InvalidBody("name is required")
+	}
+	if body.Type != consts.ATTRIBUTE_STRING && body.Type != consts.ATTRIBUTE_NUMBER && body.Type != consts.ATTRIBUTE_BOOLEAN {
+		return apperror.InvalidBody("type should be string, number or boolean")
+	}
+
+	created, err := h.CategoryDatastore.CreateAttribute(c.Request().Context(), models.CategoryAttribute{
+		CategoryID: uint(id),
+		Name:       body.Name,
+		Type:       body.Type,
+		Unit:       body.Unit,
+		Required:   body.Required,
+	})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, toAttributeResponse(created))
+}
+
+// @Summary Delete category attribute
+// @Description Delete an attribute that no ad uses
+// @Tags categories
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Category ID"
+// @Param attributeID path int true "Attribute ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/categories/{id}/attributes/{attributeID} [delete]
+func (h *CategoryHandler) DeleteAttribute(c echo.Context) error {
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+	attributeID, err := strconv.Atoi(c.Param("attributeID"))
+	if err != nil {
+		return apperror.InvalidParameter("attributeID")
+	}
+
+	if err := h.CategoryDatastore.DeleteAttribute(c.Request().Context(), id, attributeID); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{Success: true})
+}
+
+// @Summary Reorder categories
+// @Description Set the display order of categories, the first ID gets position 0
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param body body models.ReorderCategoriesRequest true "Ordered category IDs"
+// @Success 200 {array} models.CategoryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /admin/categories/order [put]
+func (h *CategoryHandler) ReorderCategories(c echo.Context) error {
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
+	var body models.ReorderCategoriesRequest
+	if err := c.Bind(&body); err != nil {
+		return err
+	}
+	if len(body.IDs) == 0 {
+		return apperror.InvalidBody("ids is required")
+	}
+
+	ctx := c.Request().Context()
+	if err := h.CategoryDatastore.Reorder(ctx, body.IDs); err != nil {
+		return err
+	}
+
+	categories, err := h.CategoryDatastore.List(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toCategoryResponses(categories))
+}
+
+// @Summary Retire category
+// @Description Retire a category so new ads can't use it. Fails while active ads use it unless reassign_to names a live category to move its ads to.
+// @Tags categories
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Category ID"
+// @Param reassign_to query int false "Category ID that takes over the ads"
+// @Success 200 {object} models.CategoryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/categories/{id} [delete]
+func (h *CategoryHandler) RetireCategory(c echo.Context) error {
+	if !isAdmin(c) {
+		return forbidden(c)
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+	reassignTo := 0
+	if v := c.QueryParam("reassign_to"); v != "" {
+		reassignTo, err = strconv.Atoi(v)
+		if err != nil {
+			return apperror.InvalidParameter("reassign_to")
+		}
+	}
+
+	retired, err := h.CategoryDatastore.Retire(c.Request().Context(), id, reassignTo)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toCategoryResponse(retired))
+}
+
+func isAdmin(c echo.Context) bool {
+	return c.Get("user").(models.User).Role == consts.ROLE_ADMIN
+}
+
+func forbidden(c echo.Context) error {
+	return apperror.Forbidden(apperror.CodeForbidden, "Only admins can manage categories")
+}
+
+func toCategoryResponse(cat models.Category) models.CategoryResponse {
+	return models.CategoryResponse{
+		ID:       cat.ID,
+		Name:     cat.Name,
+		ParentID: cat.ParentID,
+		Position: cat.Position,
+		Retired:  cat.Retired,
+	}
+}
+
+func toCategoryResponses(categories []models.Category) []models.CategoryResponse {
+	resp := []models.CategoryResponse{}
+	for _, cat := range categories {
+		resp = append(resp, toCategoryResponse(cat))
+	}
+	return resp
+}
+
+func toAttributeResponse(attr models.CategoryAttribute) models.CategoryAttributeResponse {
+	return models.CategoryAttributeResponse{
+		ID:         attr.ID,
+		CategoryID: attr.CategoryID,
+		Name:       attr.Name,
+		Type:       attr.Type,
+		Unit:       attr.Unit,
+		Required:   attr.Required,
+	}
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"Airplane-Divar/consts"
+	database "Airplane-Divar/database"
+	categoryDatastore "Airplane-Divar/datastore/category"
+	"Airplane-Divar/middlewares"
+	"Airplane-Divar/models"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// serve runs the handler and renders its error the way the server does.
+func serve(c echo.Context, h echo.HandlerFunc) {
+	if err := h(c); err != nil {
+		middlewares.ErrorHandler(err, c)
+	}
+}
+
+func TestCategoryHandler_Errors(t *testing.T) {
+	db, err := database.CreateTestDatabase()
+	defer database.CloseTestDatabase(db)
+	if err != nil {
+		t.Fatalf("could not connect to sql, err: %v", err)
+	}
+
+	ctx := context.Background()
+	storer := categoryDatastore.NewCategoryStorer(db)
+	retired, err := storer.Create(ctx, "retired", 0, nil)
+	assert.NoError(t, err)
+	_, err = storer.Retire(ctx, int(retired.ID), 0)
+	assert.NoError(t, err)
+	cargo, err := storer.Create(ctx, "cargo", 0, nil)
+	assert.NoError(t, err)
+	heavy, err := storer.Create(ctx, "heavy-cargo", 0, &cargo.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(3), heavy.ID)
+	_, err = storer.CreateAttribute(ctx, models.CategoryAttribute{CategoryID: cargo.ID, Name: "door_width", Type: consts.ATTRIBUTE_NUMBER})
+	assert.NoError(t, err)
+
+	h := NewCategoryHandler(storer)
+	e := echo.New()
+
+	testcases := []struct {
+		name    string
+		method  string
+		body    string
+		query   string
+		id      string
+		handler echo.HandlerFunc
+		status  int
+		code    string
+	}{
+		{
+			name:    "create under a retired parent",
+			method:  http.MethodPost,
+			body:    `{"name":"orphan","parent_id":1}`,
+			handler: h.CreateCategory,
+			status:  http.StatusBadRequest,
+			code:    "parent_retired",
+		},
+		{
+			name:    "move under a retired parent",
+			method:  http.MethodPut,
+			body:    `{"parent_id":1}`,
+			id:      "3",
+			handler: h.MoveCategory,
+			status:  http.StatusBadRequest,
+			code:    "parent_retired",
+		},
+		{
+			name:    "attribute name taken in the branch",
+			method:  http.MethodPost,
+			body:    `{"name":"door_width","type":"string"}`,
+			id:      "3",
+			handler: h.CreateAttribute,
+			status:  http.StatusConflict,
+			code:    "duplicate_attribute",
+		},
+		{
+			name:    "reassign ads to a retired category",
+			method:  http.MethodDelete,
+			query:   "?reassign_to=1",
+			id:      "3",
+			handler: h.RetireCategory,
+			status:  http.StatusBadRequest,
+			code:    "invalid_reassign_target",
+		},
+		{
+			name:    "reassign ads to the same category",
+			method:  http.MethodDelete,
+			query:   "?reassign_to=3",
+			id:      "3",
+			handler: h.RetireCategory,
+			status:  http.StatusBadRequest,
+			code:    "invalid_reassign_target",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, "/admin/categories"+tc.query, strings.NewReader(tc.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			if tc.id != "" {
+				c.SetParamNames("id")
+				c.SetParamValues(tc.id)
+			}
+			c.Set("user", models.User{ID: 1, Role: consts.ROLE_ADMIN})
+
+			serve(c, tc.handler)
+			assert.Equal(t, tc.status, rec.Code)
+
+			var response models.ErrorResponse
+			assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			assert.Equal(t, tc.code, response.Error.Code)
+		})
+	}
+}
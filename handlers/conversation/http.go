@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	"Airplane-Divar/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ConversationHandler struct {
+	ConversationDatastore datastore.Conversation
+}
+
+func NewConversationHandler(conversationDS datastore.Conversation) *ConversationHandler {
+	return &ConversationHandler{
+		ConversationDatastore: conversationDS,
+	}
+}
+
+// @Summary Contact the seller
+// @Description Sends a message to the seller of an ad, starting the conversation on the first contact. Only active ads can be contacted for the first time.
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Ad ID"
+// @Param body body models.SendMessageRequest true "Message"
+// @Success 201 {object} models.ChatMessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /ads/{id}/conversations [post]
+func (h *ConversationHandler) ContactSeller(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := c.Get("user").(models.User)
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	body, err := bindMessage(c)
+	if err != nil {
+		return err
+	}
+
+	conv, err := h.ConversationDatastore.Start(ctx, adID, user)
+	if err != nil {
+		return err
+	}
+
+	message, err := h.ConversationDatastore.Send(ctx, int(conv.ID), user, body.Body, body.Attachment)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, toMessageResponse(message))
+}
+
+// @Summary Inbox
+// @Description Conversations of the logged in user with their unread message counts, the most recently active first
+// @Tags conversations
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Success 200 {object} models.InboxResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /conversations [get]
+func (h *ConversationHandler) Inbox(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	conversations, err := h.ConversationDatastore.Inbox(c.Request().Context(), user)
+	if err != nil {
+		return err
+	}
+
+	resp := models.InboxResponse{Conversations: []models.ConversationResponse{}}
+	for _, conv := range conversations {
+		resp.Unread += conv.Unread
+		resp.Conversations = append(resp.Conversations, toConversationResponse(conv))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Unread messages count
+// @Tags conversations
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Success 200 {object} models.InboxResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /conversations/unread [get]
+func (h *ConversationHandler) UnreadCount(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	count, err := h.ConversationDatastore.UnreadCount(c.Request().Context(), user)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, models.InboxResponse{Unread: count})
+}
+
+// @Summary Read a conversation
+// @Description Messages of a conversation, oldest first. The messages of the other party are marked read. Admins can read any conversation.
+// @Tags conversations
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Conversation ID"
+// @Success 200 {array} models.ChatMessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /conversations/{id}/messages [get]
+func (h *ConversationHandler) Messages(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	messages, err := h.ConversationDatastore.Messages(c.Request().Context(), id, user)
+	if err != nil {
+		return err
+	}
+
+	resp := []models.ChatMessageResponse{}
+	for _, message := range messages {
+		resp = append(resp, toMessageResponse(message))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Send a message
+// @Tags conversations
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Conversation ID"
+// @Param body body models.SendMessageRequest true "Message"
+// @Success 201 {object} models.ChatMessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /conversations/{id}/messages [post]
+func (h *ConversationHandler) Send(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	body, err := bindMessage(c)
+	if err != nil {
+		return err
+	}
+
+	message, err := h.ConversationDatastore.Send(c.Request().Context(), id, user, body.Body, body.Attachment)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, toMessageResponse(message))
+}
+
+// @Summary Conversations of an ad
+// @Description Every conversation on an ad, for dispute review
+// @Tags conversations
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param ad_id query int true "Ad ID"
+// @Success 200 {array} models.ConversationResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/conversations [get]
+func (h *ConversationHandler) AdminListConversations(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_ADMIN {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only admin can review conversations")
+	}
+
+	adID, err := strconv.Atoi(c.QueryParam("ad_id"))
+	if err != nil {
+		return apperror.InvalidParameter("ad_id")
+	}
+
+	conversations, err := h.ConversationDatastore.ListByAd(c.Request().Context(), adID)
+	if err != nil {
+		return err
+	}
+
+	resp := []models.ConversationResponse{}
+	for _, conv := range conversations {
+		resp = append(resp, toConversationResponse(conv))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func bindMessage(c echo.Context) (models.SendMessageRequest, error) {
+	var body models.SendMessageRequest
+	if err := c.Bind(&body); err != nil {
+		return body, err
+	}
+	body.Body = strings.TrimSpace(body.Body)
+	if body.Body == "" && body.Attachment == "" {
+		return body, apperror.InvalidBody("message should have a body or an attachment")
+	}
+	if body.Attachment != "" && !utils.ValidateImageURL(body.Attachment) {
+		return body, apperror.InvalidBody("attachment should be an image url")
+	}
+	return body, nil
+}
+
+func toConversationResponse(conv models.Conversation) models.ConversationResponse {
+	return models.ConversationResponse{
+		ID:            conv.ID,
+		AdID:          conv.AdsID,
+		BuyerID:       conv.BuyerID,
+		SellerID:      conv.SellerID,
+		LastMessageAt: conv.LastMessageAt,
+		Unread:        conv.Unread,
+	}
+}
+
+func toMessageResponse(message models.Message) models.ChatMessageResponse {
+	return models.ChatMessageResponse{
+		ID:             message.ID,
+		ConversationID: message.ConversationID,
+		SenderID:       message.SenderID,
+		Body:           message.Body,
+		Attachment:     message.Attachment,
+		ReadAt:         message.ReadAt,
+		CreatedAt:      message.CreatedAt,
+	}
+}
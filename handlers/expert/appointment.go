@@ -0,0 +1,339 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	logging_service "Airplane-Divar/service/logging"
+	"Airplane-Divar/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AppointmentHandler struct {
+	appointments datastore.ExpertAppointment
+}
+
+func NewAppointmentHandler(appointments datastore.ExpertAppointment) *AppointmentHandler {
+	return &AppointmentHandler{appointments: appointments}
+}
+
+// @Summary Publish an availability slot
+// @Description Experts publish when and where they can inspect airplanes, the slots of an expert don't overlap
+// @Tags expert
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param body body models.ExpertSlotRequest true "Slot"
+// @Success 201 {object} models.ExpertSlotResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /expert/slots [post]
+func (h *AppointmentHandler) CreateSlot(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_EXPERT {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only experts publish slots.")
+	}
+
+	var body models.ExpertSlotRequest
+	if errs := utils.BindJSON(c.Request().Body, &body); len(errs) > 0 {
+		return errs.Err()
+	}
+	slot, errs := utils.ValidateSlot(body, time.Now())
+	if err := errs.Err(); err != nil {
+		return err
+	}
+	slot.ExpertID = user.ID
+
+	slot, err := h.appointments.CreateSlot(c.Request().Context(), slot)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, toSlotResponse(slot))
+}
+
+// @Summary List the slots of the expert
+// @Description The slots of the expert that didn't end yet, booked or not
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Success 200 {array} models.ExpertSlotResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /expert/slots [get]
+func (h *AppointmentHandler) ListSlots(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_EXPERT {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only experts have slots.")
+	}
+
+	slots, err := h.appointments.Slots(c.Request().Context(), user.ID, time.Now())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toSlotsResponse(slots))
+}
+
+// @Summary Delete a slot
+// @Description Experts delete slots nobody booked
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Slot ID"
+// @Success 200 {object} models.MessageResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /expert/slots/{id} [delete]
+func (h *AppointmentHandler) DeleteSlot(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_EXPERT {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only experts have slots.")
+	}
+	slotID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	if _, err := h.appointments.DeleteSlot(c.Request().Context(), slotID, user.ID); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, models.MessageResponse{Message: "Slot deleted successfully"})
+}
+
+// @Summary Free slots for a request
+// @Description The slots of the expert of a paid request the airline can book
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Success 200 {array} models.ExpertSlotResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/slots [get]
+func (h *AppointmentHandler) FreeSlots(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	requestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return apperror.InvalidParameter("expertRequestID")
+	}
+
+	slots, err := h.appointments.FreeSlots(c.Request().Context(), requestID, user, time.Now())
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toSlotsResponse(slots))
+}
+
+// @Summary Get the appointment of a request
+// @Description The scheduled inspection of a request, for its airline, its expert and admins
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Success 200 {object} models.AppointmentResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/appointment [get]
+func (h *AppointmentHandler) GetAppointment(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	requestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return apperror.InvalidParameter("expertRequestID")
+	}
+
+	appointment, err := h.appointments.Appointment(c.Request().Context(), requestID, user)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toAppointmentResponse(appointment))
+}
+
+// @Summary Book an inspection
+// @Description The airline picks a free slot of the expert of its paid request
+// @Tags expert
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Param body body models.AppointmentRequest true "Slot"
+// @Success 201 {object} models.AppointmentResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/appointment [post]
+func (h *AppointmentHandler) Book(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	requestID, slotID, err := appointmentRequest(c)
+	if err != nil {
+		return err
+	}
+
+	appointment, err := h.appointments.Book(c.Request().Context(), requestID, slotID, user, time.Now())
+	if err != nil {
+		return err
+	}
+	logAppointment(user, appointment, consts.LOG_APPOINTMENT_SCHEDULED)
+
+	return c.JSON(http.StatusCreated, toAppointmentResponse(appointment))
+}
+
+// @Summary Reschedule an inspection
+// @Description The airline moves its appointment to another free slot of the expert, until the configured notice before it starts
+// @Tags expert
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Param body body models.AppointmentRequest true "Slot"
+// @Success 200 {object} models.AppointmentResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/appointment [put]
+func (h *AppointmentHandler) Reschedule(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	requestID, slotID, err := appointmentRequest(c)
+	if err != nil {
+		return err
+	}
+
+	appointment, err := h.appointments.Reschedule(c.Request().Context(), requestID, slotID, user, time.Now())
+	if err != nil {
+		return err
+	}
+	logAppointment(user, appointment, consts.LOG_APPOINTMENT_RESCHEDULED)
+
+	return c.JSON(http.StatusOK, toAppointmentResponse(appointment))
+}
+
+// @Summary Cancel an inspection
+// @Description The airline or the expert cancel the appointment until the configured notice before it starts, admins at any time
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Success 200 {object} models.AppointmentResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/appointment [delete]
+func (h *AppointmentHandler) Cancel(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	requestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return apperror.InvalidParameter("expertRequestID")
+	}
+
+	appointment, err := h.appointments.Cancel(c.Request().Context(), requestID, user, time.Now())
+	if err != nil {
+		return err
+	}
+	logAppointment(user, appointment, consts.LOG_APPOINTMENT_CANCELLED)
+
+	return c.JSON(http.StatusOK, toAppointmentResponse(appointment))
+}
+
+// @Summary List my appointments
+// @Description The upcoming inspections of the user, as the expert or as the airline
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Success 200 {array} models.AppointmentResponse
+// @Router /expert/appointments [get]
+func (h *AppointmentHandler) ListAppointments(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	appointments, err := h.appointments.Appointments(c.Request().Context(), user.ID, time.Now(), false)
+	if err != nil {
+		return err
+	}
+
+	resp := []models.AppointmentResponse{}
+	for _, appointment := range appointments {
+		resp = append(resp, toAppointmentResponse(appointment))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func appointmentRequest(c echo.Context) (int, uint, error) {
+	requestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return 0, 0, apperror.InvalidParameter("expertRequestID")
+	}
+
+	var body models.AppointmentRequest
+	errs := utils.BindJSON(c.Request().Body, &body)
+	if !errs.Has("body") && !errs.Has("slot_id") && body.SlotID == 0 {
+		errs.Add("slot_id", consts.VALIDATION_REQUIRED, "slot_id is required !")
+	}
+	if err := errs.Err(); err != nil {
+		return 0, 0, err
+	}
+	return requestID, body.SlotID, nil
+}
+
+func logAppointment(user models.User, appointment models.Appointment, logName string) {
+	// ____ Report Log ____
+	logService := logging_service.GetInstance()
+	if logService != (*logging_service.Logging)(nil) {
+		description := fmt.Sprintf(
+			"appointment %d of request %d at %s, %s",
+			appointment.ID, appointment.ExpertAdsID, appointment.StartsAt.UTC().Format(time.RFC3339), appointment.Location,
+		)
+		err := logService.ReportActivity(user.Role, user.ID, "ExpertAds", appointment.ExpertAdsID, logName, description)
+		if err != nil {
+			_ = fmt.Errorf("cannot log activity %v", logName)
+		}
+	}
+	// ____ Report Log ____
+}
+
+func toSlotsResponse(slots []models.ExpertSlot) []models.ExpertSlotResponse {
+	resp := make([]models.ExpertSlotResponse, 0, len(slots))
+	for _, slot := range slots {
+		resp = append(resp, toSlotResponse(slot))
+	}
+	return resp
+}
+
+func toSlotResponse(slot models.ExpertSlot) models.ExpertSlotResponse {
+	return models.ExpertSlotResponse{
+		ID:       slot.ID,
+		ExpertID: slot.ExpertID,
+		StartsAt: slot.StartsAt,
+		EndsAt:   slot.EndsAt,
+		Location: slot.Location,
+		Booked:   slot.Booked,
+	}
+}
+
+func toAppointmentResponse(appointment models.Appointment) models.AppointmentResponse {
+	return models.AppointmentResponse{
+		ID:          appointment.ID,
+		RequestID:   appointment.ExpertAdsID,
+		SlotID:      appointment.SlotID,
+		AdID:        appointment.AdsID,
+		AdSubject:   appointment.Ads.Subject,
+		ExpertID:    appointment.ExpertID,
+		ExpertName:  appointment.Expert.Username,
+		UserID:      appointment.UserID,
+		StartsAt:    appointment.StartsAt,
+		EndsAt:      appointment.EndsAt,
+		Location:    appointment.Location,
+		Status:      string(appointment.Status),
+		CancelledBy: appointment.CancelledBy,
+		CancelledAt: appointment.CancelledAt,
+		CreatedAt:   appointment.CreatedAt,
+		UpdatedAt:   appointment.UpdatedAt,
+	}
+}
@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	assignment_service "Airplane-Divar/service/assignment"
+	"Airplane-Divar/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AssignmentHandler struct {
+	assignments datastore.ExpertAssignment
+}
+
+func NewAssignmentHandler(assignments datastore.ExpertAssignment) *AssignmentHandler {
+	return &AssignmentHandler{assignments: assignments}
+}
+
+// @Summary Get the profile of the expert
+// @Description Availability and specialties used to assign expert requests
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Success 200 {object} models.ExpertProfileResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /expert/profile [get]
+func (h *AssignmentHandler) GetProfile(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_EXPERT {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only experts have a profile.")
+	}
+
+	profile, err := h.assignments.Profile(c.Request().Context(), user.ID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toProfileResponse(profile))
+}
+
+// @Summary Update the profile of the expert
+// @Description Experts who are not available don't get new requests, experts with specialties only get requests of those categories
+// @Tags expert
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param body body models.ExpertProfileRequest true "Profile"
+// @Success 200 {object} models.ExpertProfileResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /expert/profile [put]
+func (h *AssignmentHandler) UpdateProfile(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_EXPERT {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only experts have a profile.")
+	}
+
+	var body models.ExpertProfileRequest
+	errs := utils.BindJSON(c.Request().Body, &body)
+	if !errs.Has("body") && !errs.Has("available") && body.Available == nil {
+		errs.Add("available", consts.VALIDATION_REQUIRED, "available is required !")
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	specialties := []uint{}
+	seen := map[uint]bool{}
+	for _, categoryID := range body.Specialties {
+		if !seen[categoryID] {
+			seen[categoryID] = true
+			specialties = append(specialties, categoryID)
+		}
+	}
+
+	profile, err := h.assignments.SaveProfile(c.Request().Context(), user.ID, *body.Available, specialties)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toProfileResponse(profile))
+}
+
+// @Summary Assign an expert request
+// @Description Admins give a paid expert request to an expert, requests in progress move to the new expert
+// @Tags expert
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Param body body models.AssignExpertRequest true "Expert"
+// @Success 200 {object} models.ExpertRequestResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/assign [put]
+func (h *AssignmentHandler) Reassign(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_ADMIN {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only admins can assign expert requests.")
+	}
+	requestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return apperror.InvalidParameter("expertRequestID")
+	}
+
+	var body models.AssignExpertRequest
+	errs := utils.BindJSON(c.Request().Body, &body)
+	if !errs.Has("body") && !errs.Has("expert_id") && body.ExpertID == 0 {
+		errs.Add("expert_id", consts.VALIDATION_REQUIRED, "expert_id is required !")
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	expertAd, err := h.assignments.Assign(c.Request().Context(), requestID, body.ExpertID, true, time.Now())
+	if err != nil {
+		return err
+	}
+	assignment_service.LogAssignment(user.Role, user.ID, expertAd, "admin")
+
+	return c.JSON(http.StatusOK, models.ExpertRequestResponse{
+		ID:        int(expertAd.ID),
+		UserID:    int(expertAd.UserID),
+		AdID:      int(expertAd.AdsID),
+		ExpertID:  int(expertAd.ExpertID),
+		Status:    string(expertAd.Status),
+		Report:    expertAd.Report,
+		CreatedAt: expertAd.CreatedAt,
+	})
+}
+
+func toProfileResponse(profile models.ExpertProfile) models.ExpertProfileResponse {
+	specialties := make([]uint, 0, len(profile.Specialties))
+	for _, specialty := range profile.Specialties {
+		specialties = append(specialties, specialty.CategoryID)
+	}
+	return models.ExpertProfileResponse{
+		ExpertID:       profile.UserID,
+		Available:      profile.Available,
+		Specialties:    specialties,
+		LastAssignedAt: profile.LastAssignedAt,
+	}
+}
@@ -0,0 +1,292 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	"Airplane-Divar/service"
+	logging_service "Airplane-Divar/service/logging"
+	"Airplane-Divar/utils"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type AttachmentHandler struct {
+	attachments datastore.ExpertAttachment
+	storage     service.Storage
+}
+
+func NewAttachmentHandler(attachments datastore.ExpertAttachment, storage service.Storage) *AttachmentHandler {
+	return &AttachmentHandler{attachments: attachments, storage: storage}
+}
+
+// @Summary Attach a file to an expert check request
+// @Description The expert working on the request uploads a photo or a scanned document, optionally as evidence for an item of the checklist
+// @Tags expert
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Param file formData file true "JPEG, PNG or PDF, up to 10MB"
+// @Param item_id formData int false "Checklist item the file is evidence for"
+// @Success 201 {object} models.ExpertAttachmentResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/attachments [post]
+func (h *AttachmentHandler) Upload(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_EXPERT {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only experts attach files to reports.")
+	}
+	requestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return apperror.InvalidParameter("expertRequestID")
+	}
+
+	errs := utils.ValidationErrors{}
+	var itemID *uint
+	if value := c.FormValue("item_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 {
+			errs.Add("item_id", consts.VALIDATION_TYPE, "item_id must be a positive integer !")
+		} else {
+			item := uint(id)
+			itemID = &item
+		}
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		errs.Add("file", consts.VALIDATION_REQUIRED, "file is required !")
+	} else if header.Size > consts.ATTACHMENT_MAX_SIZE {
+		errs.Add("file", consts.VALIDATION_OUT_OF_RANGE, fmt.Sprintf("file can't be larger than %d MB !", consts.ATTACHMENT_MAX_SIZE>>20))
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	// the content type is sniffed, what the client claims isn't trusted
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return err
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	if !attachmentType(contentType) {
+		errs.Add("file", consts.VALIDATION_NOT_ALLOWED, "Only JPEG, PNG and PDF files can be attached !")
+		return errs.Err()
+	}
+
+	key, err := attachmentKey(requestID)
+	if err != nil {
+		return err
+	}
+	size, err := h.storage.Save(ctx, key, io.MultiReader(bytes.NewReader(head[:n]), file))
+	if err != nil {
+		return err
+	}
+
+	attachment, err := h.attachments.Add(ctx, models.ExpertAttachment{
+		ExpertAdsID:    uint(requestID),
+		TemplateItemID: itemID,
+		UploaderID:     user.ID,
+		FileName:       attachmentName(header.Filename),
+		ContentType:    contentType,
+		Size:           size,
+		StorageKey:     key,
+	})
+	if err != nil {
+		if deleteErr := h.storage.Delete(ctx, key); deleteErr != nil {
+			log.Printf("cannot delete attachment file %s: %v", key, deleteErr)
+		}
+		return err
+	}
+
+	logAttachment(user, attachment, consts.LOG_EXPERT_ATTACHMENT_ADD)
+
+	return c.JSON(http.StatusCreated, toAttachmentResponse(attachment))
+}
+
+// @Summary List the attachments of an expert check request
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Success 200 {array} models.ExpertAttachmentResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/attachments [get]
+func (h *AttachmentHandler) List(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	requestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return apperror.InvalidParameter("expertRequestID")
+	}
+
+	attachments, err := h.attachments.List(c.Request().Context(), requestID, user)
+	if err != nil {
+		return err
+	}
+
+	resp := make([]models.ExpertAttachmentResponse, 0, len(attachments))
+	for _, attachment := range attachments {
+		resp = append(resp, toAttachmentResponse(attachment))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Download an attachment of an expert check request
+// @Tags expert
+// @Produce octet-stream
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Param id path int true "Attachment ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/attachments/{id} [get]
+func (h *AttachmentHandler) Download(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := c.Get("user").(models.User)
+	requestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return apperror.InvalidParameter("expertRequestID")
+	}
+	attachmentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	attachment, err := h.attachments.Get(ctx, requestID, attachmentID, user)
+	if err != nil {
+		return err
+	}
+	file, err := h.storage.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName}))
+	c.Response().Header().Set(echo.HeaderXContentTypeOptions, "nosniff")
+	c.Response().Header().Set(echo.HeaderContentLength, strconv.FormatInt(attachment.Size, 10))
+	return c.Stream(http.StatusOK, attachment.ContentType, file)
+}
+
+// @Summary Delete an attachment of an expert check request
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Param id path int true "Attachment ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/attachments/{id} [delete]
+func (h *AttachmentHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_EXPERT {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only experts remove files from reports.")
+	}
+	requestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return apperror.InvalidParameter("expertRequestID")
+	}
+	attachmentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	attachment, err := h.attachments.Delete(ctx, requestID, attachmentID, user.ID)
+	if err != nil {
+		return err
+	}
+	if err := h.storage.Delete(ctx, attachment.StorageKey); err != nil {
+		log.Printf("cannot delete attachment file %s: %v", attachment.StorageKey, err)
+	}
+
+	logAttachment(user, attachment, consts.LOG_EXPERT_ATTACHMENT_DELETE)
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{Success: true})
+}
+
+// attachmentKey is a random key under the folder of the request, the name
+// the expert gave the file is only kept in the database.
+func attachmentKey(requestID int) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("expert/%d/%s", requestID, hex.EncodeToString(b)), nil
+}
+
+func attachmentName(name string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		name = "attachment"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		name = string(runes[len(runes)-255:])
+	}
+	return name
+}
+
+func attachmentType(contentType string) bool {
+	for _, allowed := range consts.ATTACHMENT_TYPES {
+		if contentType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func logAttachment(user models.User, attachment models.ExpertAttachment, logName string) {
+	// ____ Report Log ____
+	logService := logging_service.GetInstance()
+	if logService != (*logging_service.Logging)(nil) {
+		description := fmt.Sprintf("attachment %d (%s) of request %d", attachment.ID, attachment.FileName, attachment.ExpertAdsID)
+		err := logService.ReportActivity(user.Role, user.ID, "ExpertAds", attachment.ExpertAdsID, logName, description)
+		if err != nil {
+			_ = fmt.Errorf("cannot log activity %v", logName)
+		}
+	}
+	// ____ Report Log ____
+}
+
+func toAttachmentResponse(attachment models.ExpertAttachment) models.ExpertAttachmentResponse {
+	return models.ExpertAttachmentResponse{
+		ID:          attachment.ID,
+		ItemID:      attachment.TemplateItemID,
+		FileName:    attachment.FileName,
+		ContentType: attachment.ContentType,
+		Size:        attachment.Size,
+		CreatedAt:   attachment.CreatedAt,
+	}
+}
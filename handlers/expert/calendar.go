@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	"Airplane-Divar/utils/ical"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type CalendarHandler struct {
+	feeds        datastore.CalendarFeed
+	appointments datastore.ExpertAppointment
+}
+
+func NewCalendarHandler(feeds datastore.CalendarFeed, appointments datastore.ExpertAppointment) *CalendarHandler {
+	return &CalendarHandler{feeds: feeds, appointments: appointments}
+}
+
+// @Summary Create a calendar feed
+// @Description A secret iCalendar link of the inspections of the user to subscribe to in Outlook and other calendars. Creating a new one stops the previous link, the link is only shown once.
+// @Tags calendar
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Success 201 {object} models.CalendarFeedResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /calendar/feed [post]
+func (h *CalendarHandler) Create(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return apperror.Internal("could not create the calendar link")
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	if err := h.feeds.Rotate(c.Request().Context(), user.ID, token); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, models.CalendarFeedResponse{
+		URL: fmt.Sprintf("%s://%s/calendar/%s.ics", c.Scheme(), c.Request().Host, token),
+	})
+}
+
+// @Summary Revoke the calendar feed
+// @Description The calendar link of the user stops working
+// @Tags calendar
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Success 200 {object} models.MessageResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /calendar/feed [delete]
+func (h *CalendarHandler) Revoke(c echo.Context) error {
+	user := c.Get("user").(models.User)
+
+	if err := h.feeds.Revoke(c.Request().Context(), user.ID); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, models.MessageResponse{Message: "Calendar link revoked successfully"})
+}
+
+// @Summary Calendar feed
+// @Description The inspections of the owner of the link as an iCalendar feed, cancelled ones included so calendars remove them
+// @Tags calendar
+// @Produce text/calendar
+// @Param token path string true "Feed token, with or without .ics"
+// @Success 200 {string} string
+// @Failure 404 {object} models.ErrorResponse
+// @Router /calendar/{token} [get]
+func (h *CalendarHandler) Feed(c echo.Context) error {
+	ctx := c.Request().Context()
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	user, err := h.feeds.Resolve(ctx, token)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	appointments, err := h.appointments.Appointments(ctx, user.ID, now.AddDate(0, 0, -consts.CALENDAR_PAST_DAYS), true)
+	if err != nil {
+		return err
+	}
+
+	events := make([]ical.Event, 0, len(appointments))
+	for _, appointment := range appointments {
+		events = append(events, toEvent(appointment, user))
+	}
+
+	c.Response().Header().Set(echo.HeaderCacheControl, "private, max-age=300")
+	return c.Blob(http.StatusOK, "text/calendar; charset=utf-8", ical.Render(user.Username+" inspections", events))
+}
+
+// toEvent describes the appointment for the one who attends it, the airline
+// sees who inspects and the expert whose airplane it is.
+func toEvent(appointment models.Appointment, user models.User) ical.Event {
+	summary := fmt.Sprintf("Inspection of %s", appointment.Ads.Subject)
+	description := fmt.Sprintf("Expert check request #%d by %s", appointment.ExpertAdsID, appointment.User.Username)
+	if appointment.ExpertID != user.ID {
+		description = fmt.Sprintf("Expert check request #%d with expert %s", appointment.ExpertAdsID, appointment.Expert.Username)
+	}
+	return ical.Event{
+		UID:         fmt.Sprintf("appointment-%d@airplane-divar", appointment.ID),
+		Sequence:    appointment.Sequence,
+		Stamp:       appointment.UpdatedAt,
+		Start:       appointment.StartsAt,
+		End:         appointment.EndsAt,
+		Summary:     summary,
+		Description: description,
+		Location:    appointment.Location,
+		Cancelled:   appointment.Status == consts.APPOINTMENT_CANCELLED,
+	}
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/models"
+	certificate_service "Airplane-Divar/service/certificate"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+var ErrCertificatesDisabled = apperror.Internal("expert certificates are not configured")
+
+type CertificateHandler struct{}
+
+func NewCertificateHandler() *CertificateHandler {
+	return &CertificateHandler{}
+}
+
+// @Summary Verify an expert report
+// @Description Checks the signature of the certificate of a finished expert report and whether the report was revised after it was issued, no login needed
+// @Tags expert
+// @Produce json
+// @Param id path int true "expert request ID"
+// @Param revision query int false "Revision of the certificate, the latest by default"
+// @Success 200 {object} models.ExpertCertificateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /verify/expert-report/{id} [get]
+func (h *CertificateHandler) Verify(c echo.Context) error {
+	certificateService := certificate_service.GetInstance()
+	if certificateService == (*certificate_service.Signer)(nil) {
+		return ErrCertificatesDisabled
+	}
+	requestID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+	revision := 0
+	if value := c.QueryParam("revision"); value != "" {
+		revision, err = strconv.Atoi(value)
+		if err != nil || revision < 1 {
+			return apperror.InvalidParameter("revision")
+		}
+	}
+
+	verification, err := certificateService.Verify(c.Request().Context(), requestID, revision)
+	if err != nil {
+		return err
+	}
+
+	certificate := verification.Certificate
+	resp := models.ExpertCertificateResponse{
+		RequestID:      certificate.ExpertAdsID,
+		Revision:       certificate.Revision,
+		LatestRevision: verification.LatestRevision,
+		Valid:          verification.Valid,
+		Revised:        verification.Revised,
+		IssuedAt:       certificate.IssuedAt,
+		Payload:        certificate.Payload,
+		Signature:      certificate.Signature,
+		Code:           certificate_service.Code(certificate.Signature),
+		KeyID:          certificate.KeyID,
+		PublicKey:      base64.StdEncoding.EncodeToString(verification.PublicKey),
+	}
+	var payload models.CertificatePayload
+	if json.Unmarshal([]byte(certificate.Payload), &payload) == nil {
+		resp.Certificate = &payload
+		resp.IssuedAt = payload.IssuedAt
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -1,12 +1,19 @@
 package handlers
 
 import (
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/consts"
 	"Airplane-Divar/datastore"
 	"Airplane-Divar/datastore/expert"
 	"Airplane-Divar/models"
+	assignment_service "Airplane-Divar/service/assignment"
+	certificate_service "Airplane-Divar/service/certificate"
 	logging_service "Airplane-Divar/service/logging"
+	watch_service "Airplane-Divar/service/watch"
+	"Airplane-Divar/utils"
+	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"strconv"
 
@@ -34,8 +41,9 @@ func NewExpertHandler(expertDS datastore.Expert, userDS datastore.User) *ExpertH
 // @Param Authorization header string true "User Token"
 // @Param adID path int true "Ad ID"
 // @Success 200 {object} models.SuccessResponse
-// @Failure 204 {object} models.ErrorResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /expert/ads/{adID}/check-request [post]
 func (e *ExpertHandler) RequestToExpertCheck(c echo.Context) error {
@@ -44,22 +52,16 @@ func (e *ExpertHandler) RequestToExpertCheck(c echo.Context) error {
 	user := c.Get("user").(models.User)
 
 	if user.Role != consts.ROLE_AIRLINE {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "Only airline user can send request for expert check",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "Only airline user can send request for expert check")
 	}
 
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return apperror.InvalidParameter("adID")
 	}
 
 	err = e.ExpertDatastore.RequestToExpertCheck(ctx, adID, user)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
-
+		return err
 	}
 
 	resp := models.SuccessResponse{
@@ -89,6 +91,7 @@ func (e *ExpertHandler) RequestToExpertCheck(c echo.Context) error {
 // @Success 200 {array} models.ExpertRequestResponse
 // @Success 200 {object} models.MessageResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /expert/check-requests [get]
 func (e *ExpertHandler) GetAllExpertRequest(c echo.Context) error {
@@ -97,12 +100,7 @@ func (e *ExpertHandler) GetAllExpertRequest(c echo.Context) error {
 	user := c.Get("user").(models.User)
 
 	if user.Role == consts.ROLE_MATIN {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "You don't have access to expert requests.",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "You don't have access to expert requests.")
 	}
 
 	// params
@@ -143,7 +141,7 @@ func (e *ExpertHandler) GetAllExpertRequest(c echo.Context) error {
 		ctx, queryAndCondition, queryOrConditions, queryNotCondition, page,
 	)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return err
 	}
 
 	if len(expertAds) == 0 {
@@ -179,8 +177,11 @@ func (e *ExpertHandler) GetAllExpertRequest(c echo.Context) error {
 // @Param expertRequestID path int true "expert request ID"
 // @Param expertCheckRequest body models.UpdateExpertCheckRequest true "Expert check object"
 // @Success 200 {object} models.ExpertRequestResponse
-// @Failure 204 {object} models.ErrorResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /expert/check-request/{expertRequestID} [put]
 func (e *ExpertHandler) UpdateCheckExpert(c echo.Context) error {
@@ -188,29 +189,50 @@ func (e *ExpertHandler) UpdateCheckExpert(c echo.Context) error {
 	user := c.Get("user").(models.User)
 
 	if user.Role != consts.ROLE_EXPERT {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "You can't update expert requests.",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "You can't update expert requests.")
 	}
 
 	expertRequestID, _ := strconv.Atoi(c.Param("expertRequestID"))
 
 	var updatedExpertCheck models.UpdateExpertCheckRequest
-	if err := c.Bind(&updatedExpertCheck); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	errs := utils.BindJSON(c.Request().Body, &updatedExpertCheck)
+	if !errs.Has("body") {
+		errs.Merge(utils.ValidateExpertCheck(updatedExpertCheck))
+	}
+	if len(errs) > 0 {
+		return errs.Err()
 	}
 
 	expertAd, err := e.ExpertDatastore.UpdateByExpert(ctx, expertRequestID, user, updatedExpertCheck)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return err
 	} else if expertAd.ID == 0 {
-		return c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error: "expert check request does not exist!",
-		})
+		return expert.ErrRequestNotFound
+	}
+
+	// ____ Notify Watchers ____
+	if expertAd.Status == consts.DONE_STATUS {
+		watchService := watch_service.GetInstance()
+		if watchService != (*watch_service.Watch)(nil) {
+			err = watchService.ExpertReportCompleted(ctx, expertAd.AdsID)
+			if err != nil {
+				log.Printf("could not notify the watchers of ad %d: %v", expertAd.AdsID, err)
+			}
+		}
 	}
+	// ____ Notify Watchers ____
+
+	// ____ Sign Certificate ____
+	if expertAd.Status == consts.DONE_STATUS {
+		certificateService := certificate_service.GetInstance()
+		if certificateService != (*certificate_service.Signer)(nil) {
+			_, err = certificateService.Issue(ctx, int(expertAd.ID))
+			if err != nil {
+				log.Printf("could not issue the certificate of expert request %d: %v", expertAd.ID, err)
+			}
+		}
+	}
+	// ____ Sign Certificate ____
 
 	resp := models.ExpertRequestResponse{
 		ID:        int(expertAd.ID),
@@ -235,14 +257,78 @@ func (e *ExpertHandler) UpdateCheckExpert(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// @Summary Claim an expert check request
+// @Description The expert starts working on the request, only one of the experts claiming it at the same time gets it
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Success 200 {object} models.ExpertRequestResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/claim [put]
+func (e *ExpertHandler) ClaimCheckExpert(c echo.Context) error {
+	return e.claim(c, e.ExpertDatastore.Claim, "expert claim")
+}
+
+// @Summary Release an expert check request
+// @Description Gives a claimed request back to the other experts
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Success 200 {object} models.ExpertRequestResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/claim [delete]
+func (e *ExpertHandler) ReleaseCheckExpert(c echo.Context) error {
+	return e.claim(c, e.ExpertDatastore.Release, "expert release")
+}
+
+func (e *ExpertHandler) claim(
+	c echo.Context,
+	action func(ctx context.Context, requestID int, user models.User) (models.ExpertAds, error),
+	by string,
+) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_EXPERT {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only experts can claim expert requests.")
+	}
+	expertRequestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return apperror.InvalidParameter("expertRequestID")
+	}
+
+	expertAd, err := action(c.Request().Context(), expertRequestID, user)
+	if err != nil {
+		return err
+	}
+	assignment_service.LogAssignment(user.Role, user.ID, expertAd, by)
+
+	return c.JSON(http.StatusOK, models.ExpertRequestResponse{
+		ID:        int(expertAd.ID),
+		UserID:    int(expertAd.UserID),
+		AdID:      int(expertAd.AdsID),
+		ExpertID:  int(expertAd.ExpertID),
+		Status:    string(expertAd.Status),
+		Report:    expertAd.Report,
+		CreatedAt: expertAd.CreatedAt,
+	})
+}
+
 // @Summary retrieve expert check request by ad for expert or user
 // @Description retrieve expert check request by ad for expert or user
 // @Tags expert
 // @Param Authorization header string true "User Token"
 // @Param adID path int true "ad ID"
 // @Success 200 {object} models.GetExpertRequestResponse
-// @Failure 204 {object} models.ErrorResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /expert/ads/{adID} [get]
 func (e *ExpertHandler) GetExpertRequestByAd(c echo.Context) error {
@@ -251,19 +337,12 @@ func (e *ExpertHandler) GetExpertRequestByAd(c echo.Context) error {
 	adID, _ := strconv.Atoi(c.Param("adID"))
 
 	if user.Role == consts.ROLE_MATIN {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "You don't have access to expert requests.",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "You don't have access to expert requests.")
 	}
 
 	expertAd, err := e.ExpertDatastore.GetByAd(ctx, adID, user)
 	if err != nil {
-		return c.JSON(
-			http.StatusNotFound, models.ErrorResponse{Error: "Expert request not found!"},
-		)
+		return err
 	}
 
 	resp := models.GetExpertRequestResponse{
@@ -273,6 +352,9 @@ func (e *ExpertHandler) GetExpertRequestByAd(c echo.Context) error {
 		ExpertID:  int(expertAd.ExpertID),
 		Status:    string(expertAd.Status),
 		Report:    expertAd.Report,
+		Grade:     expertAd.Grade,
+		Score:     expertAd.Score,
+		Checklist: toChecklistResponse(expertAd.Checklist),
 		CreatedAt: expertAd.CreatedAt,
 	}
 
@@ -286,8 +368,9 @@ func (e *ExpertHandler) GetExpertRequestByAd(c echo.Context) error {
 // @Param Authorization header string true "User Token"
 // @Param requestID path int true "request ID"
 // @Success 200 {object} models.GetExpertRequestResponse
-// @Failure 204 {object} models.ErrorResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /expert/check-request/{requestID} [get]
 func (e *ExpertHandler) GetExpertRequest(c echo.Context) error {
@@ -296,19 +379,12 @@ func (e *ExpertHandler) GetExpertRequest(c echo.Context) error {
 	requestID, _ := strconv.Atoi(c.Param("requestID"))
 
 	if user.Role == consts.ROLE_MATIN {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "You don't have access to expert requests.",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "You don't have access to expert requests.")
 	}
 
 	expertAd, err := e.ExpertDatastore.Get(ctx, requestID, user)
 	if err != nil {
-		return c.JSON(
-			http.StatusNotFound, models.ErrorResponse{Error: "Expert request not found!"},
-		)
+		return err
 	}
 
 	resp := models.GetExpertRequestResponse{
@@ -318,6 +394,9 @@ func (e *ExpertHandler) GetExpertRequest(c echo.Context) error {
 		ExpertID:  int(expertAd.ExpertID),
 		Status:    string(expertAd.Status),
 		Report:    expertAd.Report,
+		Grade:     expertAd.Grade,
+		Score:     expertAd.Score,
+		Checklist: toChecklistResponse(expertAd.Checklist),
 		CreatedAt: expertAd.CreatedAt,
 	}
 
@@ -330,9 +409,10 @@ func (e *ExpertHandler) GetExpertRequest(c echo.Context) error {
 // @Tags expert
 // @Param Authorization header string true "User Token"
 // @Param adID path int true "ad ID"
-// @Success 200 {object} models.SuccessResponse
-// @Failure 204 {object} models.ErrorResponse
+// @Success 204
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /expert/ads/{adID} [delete]
 func (e *ExpertHandler) DeleteExpertRequest(c echo.Context) error {
@@ -341,21 +421,14 @@ func (e *ExpertHandler) DeleteExpertRequest(c echo.Context) error {
 	adID, _ := strconv.Atoi(c.Param("adID"))
 
 	if user.Role != consts.ROLE_AIRLINE {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "You can't delete this expert request.",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "You can't delete this expert request.")
 	}
 
 	err := e.ExpertDatastore.Delete(ctx, adID, user)
 	if err != nil {
-		return c.JSON(
-			http.StatusNotFound, models.ErrorResponse{Error: "Expert request not found!"},
-		)
+		return err
 	}
 
-	return c.JSON(http.StatusNoContent, models.SuccessResponse{Success: true})
+	return c.NoContent(http.StatusNoContent)
 
 }
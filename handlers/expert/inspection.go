@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	"Airplane-Divar/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type InspectionHandler struct {
+	inspections datastore.Inspection
+}
+
+func NewInspectionHandler(inspections datastore.Inspection) *InspectionHandler {
+	return &InspectionHandler{inspections: inspections}
+}
+
+// @Summary Create an inspection template
+// @Description Admins define the checklist experts fill for the ads of a category
+// @Tags expert
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param body body models.InspectionTemplateRequest true "Template"
+// @Success 201 {object} models.InspectionTemplateResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /expert/templates [post]
+func (h *InspectionHandler) CreateTemplate(c echo.Context) error {
+	if err := admin(c); err != nil {
+		return err
+	}
+
+	var body models.InspectionTemplateRequest
+	errs := utils.BindJSON(c.Request().Body, &body)
+	var items []models.InspectionTemplateItem
+	if !errs.Has("body") {
+		if body.CategoryID == 0 && !errs.Has("category_id") {
+			errs.Add("category_id", consts.VALIDATION_REQUIRED, "category_id is required !")
+		}
+		var itemErrs utils.ValidationErrors
+		items, itemErrs = utils.ValidateInspectionTemplate(body)
+		errs.Merge(itemErrs)
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	template, err := h.inspections.CreateTemplate(c.Request().Context(), models.InspectionTemplate{
+		CategoryID: body.CategoryID,
+		Name:       strings.TrimSpace(body.Name),
+		Items:      items,
+	})
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusCreated, toTemplateResponse(template))
+}
+
+// @Summary List inspection templates
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Success 200 {array} models.InspectionTemplateResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /expert/templates [get]
+func (h *InspectionHandler) ListTemplates(c echo.Context) error {
+	if err := admin(c); err != nil {
+		return err
+	}
+
+	templates, err := h.inspections.ListTemplates(c.Request().Context())
+	if err != nil {
+		return err
+	}
+
+	resp := make([]models.InspectionTemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		resp = append(resp, toTemplateResponse(template))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Get an inspection template
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Template ID"
+// @Success 200 {object} models.InspectionTemplateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /expert/templates/{id} [get]
+func (h *InspectionHandler) GetTemplate(c echo.Context) error {
+	if err := admin(c); err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	template, err := h.inspections.GetTemplate(c.Request().Context(), id)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toTemplateResponse(template))
+}
+
+// @Summary Update an inspection template
+// @Description Replaces the name and the items of the template, reports that were already filled keep their items
+// @Tags expert
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Template ID"
+// @Param body body models.InspectionTemplateRequest true "Template, the category can't change"
+// @Success 200 {object} models.InspectionTemplateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /expert/templates/{id} [put]
+func (h *InspectionHandler) UpdateTemplate(c echo.Context) error {
+	if err := admin(c); err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	var body models.InspectionTemplateRequest
+	errs := utils.BindJSON(c.Request().Body, &body)
+	var items []models.InspectionTemplateItem
+	if !errs.Has("body") {
+		var itemErrs utils.ValidationErrors
+		items, itemErrs = utils.ValidateInspectionTemplate(body)
+		errs.Merge(itemErrs)
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	template, err := h.inspections.UpdateTemplate(c.Request().Context(), id, strings.TrimSpace(body.Name), items)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toTemplateResponse(template))
+}
+
+// @Summary Delete an inspection template
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Template ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /expert/templates/{id} [delete]
+func (h *InspectionHandler) DeleteTemplate(c echo.Context) error {
+	if err := admin(c); err != nil {
+		return err
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	if err := h.inspections.DeleteTemplate(c.Request().Context(), id); err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, models.SuccessResponse{Success: true})
+}
+
+// @Summary Get the checklist of an expert check request
+// @Description The template the expert fills for the request
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Success 200 {object} models.InspectionTemplateResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/checklist [get]
+func (h *InspectionHandler) GetChecklist(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_EXPERT && user.Role != consts.ROLE_ADMIN {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only experts fill checklists.")
+	}
+	requestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return apperror.InvalidParameter("expertRequestID")
+	}
+
+	template, err := h.inspections.TemplateFor(c.Request().Context(), requestID)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toTemplateResponse(template))
+}
+
+// @Summary Fill the checklist of an expert check request
+// @Description The expert working on the request rates every item of its template, the overall grade is computed from the ratings and findings
+// @Tags expert
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Param body body models.InspectionChecklistRequest true "Checklist"
+// @Success 200 {object} models.GetExpertRequestResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/checklist [put]
+func (h *InspectionHandler) SaveChecklist(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_EXPERT {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only experts fill checklists.")
+	}
+	requestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return apperror.InvalidParameter("expertRequestID")
+	}
+
+	var body models.InspectionChecklistRequest
+	if errs := utils.BindJSON(c.Request().Body, &body); len(errs) > 0 {
+		return errs.Err()
+	}
+
+	template, err := h.inspections.TemplateFor(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	items, errs := utils.ValidateChecklist(template, body)
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	expertAd, err := h.inspections.SaveChecklist(ctx, requestID, user.ID, items)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, models.GetExpertRequestResponse{
+		ID:        int(expertAd.ID),
+		UserID:    int(expertAd.UserID),
+		ExpertID:  int(expertAd.ExpertID),
+		Status:    string(expertAd.Status),
+		Report:    expertAd.Report,
+		Grade:     expertAd.Grade,
+		Score:     expertAd.Score,
+		Checklist: toChecklistResponse(expertAd.Checklist),
+		CreatedAt: expertAd.CreatedAt,
+	})
+}
+
+func admin(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_ADMIN {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only admins can manage inspection templates.")
+	}
+	return nil
+}
+
+func toTemplateResponse(template models.InspectionTemplate) models.InspectionTemplateResponse {
+	items := make([]models.InspectionTemplateItemResponse, 0, len(template.Items))
+	for _, item := range template.Items {
+		items = append(items, models.InspectionTemplateItemResponse{
+			ID:      item.ID,
+			Section: item.Section,
+			Label:   item.Label,
+		})
+	}
+	return models.InspectionTemplateResponse{
+		ID:         template.ID,
+		CategoryID: template.CategoryID,
+		Name:       template.Name,
+		Items:      items,
+	}
+}
+
+// toChecklistResponse groups the items of a checklist by section, in the
+// order of consts.INSPECTION_SECTIONS.
+func toChecklistResponse(items []models.InspectionItem) []models.InspectionSectionResponse {
+	sections := []models.InspectionSectionResponse{}
+	for _, section := range consts.INSPECTION_SECTIONS {
+		resp := models.InspectionSectionResponse{Section: section, Items: []models.InspectionItemResponse{}}
+		for _, item := range items {
+			if item.Section == section {
+				resp.Items = append(resp.Items, models.InspectionItemResponse{
+					ItemID:   item.TemplateItemID,
+					Label:    item.Label,
+					Rating:   item.Rating,
+					Finding:  item.Finding,
+					Severity: item.Severity,
+				})
+			}
+		}
+		if len(resp.Items) > 0 {
+			sections = append(sections, resp)
+		}
+	}
+	return sections
+}
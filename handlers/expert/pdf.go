@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/datastore/expert"
+	"Airplane-Divar/models"
+	"Airplane-Divar/service"
+	certificate_service "Airplane-Divar/service/certificate"
+	"Airplane-Divar/utils/pdf"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+var ErrReportNotDone = apperror.Conflict("report_not_done", "only finished expert reports can be printed")
+
+// thumbnailSize is the largest side of an evidence image on a printed
+// report, in pixels.
+const thumbnailSize = 480
+
+type ReportHandler struct {
+	experts      datastore.Expert
+	certificates datastore.ExpertCertificate
+	attachments  datastore.ExpertAttachment
+	storage      service.Storage
+}
+
+func NewReportHandler(
+	experts datastore.Expert,
+	certificates datastore.ExpertCertificate,
+	attachments datastore.ExpertAttachment,
+	storage service.Storage,
+) *ReportHandler {
+	return &ReportHandler{experts: experts, certificates: certificates, attachments: attachments, storage: storage}
+}
+
+// @Summary Print a finished expert report
+// @Description The report as a PDF with the airplane, the checklist, thumbnails of the evidence, the expert and the verification code of its certificate
+// @Tags expert
+// @Produce application/pdf
+// @Param Authorization header string true "User Token"
+// @Param requestID path int true "request ID"
+// @Success 200 {file} file
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /expert/check-request/{requestID}/pdf [get]
+func (h *ReportHandler) PDF(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := c.Get("user").(models.User)
+	requestID, err := strconv.Atoi(c.Param("requestID"))
+	if err != nil {
+		return apperror.InvalidParameter("requestID")
+	}
+
+	if user.Role == consts.ROLE_MATIN {
+		return apperror.Forbidden(apperror.CodeForbidden, "You don't have access to expert requests.")
+	}
+
+	expertAd, err := h.experts.Get(ctx, requestID, user)
+	if err != nil {
+		return err
+	}
+	if expertAd.Status != consts.DONE_STATUS {
+		return ErrReportNotDone
+	}
+
+	report, err := h.certificates.Report(ctx, requestID)
+	if err != nil {
+		return err
+	}
+	data := pdf.ExpertReport{Report: report}
+
+	certificates, err := h.certificates.List(ctx, requestID)
+	if err != nil && !errors.Is(err, expert.ErrCertificateNotFound) {
+		return err
+	}
+	if len(certificates) > 0 {
+		latest := certificates[len(certificates)-1]
+		data.Certificate = &latest
+		data.Code = certificate_service.Code(latest.Signature)
+		data.VerifyURL = fmt.Sprintf("%s://%s/verify/expert-report/%d?revision=%d", c.Scheme(), c.Request().Host, report.ID, latest.Revision)
+	}
+
+	attachments, err := h.attachments.List(ctx, requestID, user)
+	if err != nil {
+		return err
+	}
+	items := map[uint]string{}
+	for _, item := range report.Checklist {
+		items[item.TemplateItemID] = item.Label
+	}
+	for _, attachment := range attachments {
+		evidence := pdf.Evidence{Attachment: attachment}
+		if attachment.TemplateItemID != nil {
+			evidence.Item = items[*attachment.TemplateItemID]
+		}
+		if attachment.ContentType != "application/pdf" {
+			evidence.Thumbnail = h.thumbnail(ctx, attachment)
+		}
+		data.Evidence = append(data.Evidence, evidence)
+	}
+
+	document, err := pdf.RenderExpertReport(data)
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("expert-report-%d.pdf", report.ID)
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+	return c.Blob(http.StatusOK, "application/pdf", document)
+}
+
+// thumbnail is nil when the image can't be read, the report then lists the
+// file instead of showing it.
+func (h *ReportHandler) thumbnail(ctx context.Context, attachment models.ExpertAttachment) image.Image {
+	file, err := h.storage.Open(ctx, attachment.StorageKey)
+	if err != nil {
+		log.Printf("cannot open attachment %d: %v", attachment.ID, err)
+		return nil
+	}
+	defer file.Close()
+
+	thumbnail, err := pdf.Thumbnail(file, thumbnailSize)
+	if err != nil {
+		log.Printf("cannot preview attachment %d: %v", attachment.ID, err)
+		return nil
+	}
+	return thumbnail
+}
@@ -0,0 +1,221 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	logging_service "Airplane-Divar/service/logging"
+	"Airplane-Divar/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ReviewHandler struct {
+	reviews     datastore.ExpertReview
+	assignments datastore.ExpertAssignment
+}
+
+func NewReviewHandler(reviews datastore.ExpertReview, assignments datastore.ExpertAssignment) *ReviewHandler {
+	return &ReviewHandler{reviews: reviews, assignments: assignments}
+}
+
+// @Summary Review the expert of a check
+// @Description The airline that requested a done expert check rates the expert from 1 to 5, once per request
+// @Tags expert
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param expertRequestID path int true "expert request ID"
+// @Param body body models.ExpertReviewRequest true "Review"
+// @Success 201 {object} models.ExpertReviewResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /expert/check-request/{expertRequestID}/review [post]
+func (h *ReviewHandler) Create(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	requestID, err := strconv.Atoi(c.Param("expertRequestID"))
+	if err != nil {
+		return apperror.InvalidParameter("expertRequestID")
+	}
+
+	var body models.ExpertReviewRequest
+	errs := utils.BindJSON(c.Request().Body, &body)
+	if !errs.Has("body") {
+		errs.Merge(utils.ValidateExpertReview(body))
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	review, err := h.reviews.Create(c.Request().Context(), requestID, user, body.Rating, strings.TrimSpace(body.Comment))
+	if err != nil {
+		return err
+	}
+	logReview(user, review, consts.LOG_EXPERT_REVIEWED, fmt.Sprintf("expert %d rated %d", review.ExpertID, review.Rating))
+
+	return c.JSON(http.StatusCreated, toReviewResponse(review))
+}
+
+// @Summary Public profile of an expert
+// @Description The specialties and rating of an expert with their latest reviews
+// @Tags expert
+// @Produce json
+// @Param id path int true "Expert ID"
+// @Param page query int false "Page of the reviews"
+// @Success 200 {object} models.ExpertPublicProfileResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /experts/{id} [get]
+func (h *ReviewHandler) Profile(c echo.Context) error {
+	ctx := c.Request().Context()
+	expertID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || expertID <= 0 {
+		return apperror.InvalidParameter("id")
+	}
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+
+	expert, err := h.reviews.Expert(ctx, uint(expertID))
+	if err != nil {
+		return err
+	}
+	profile, err := h.assignments.Profile(ctx, expert.ID)
+	if err != nil {
+		return err
+	}
+	rating, err := h.reviews.Rating(ctx, expert.ID)
+	if err != nil {
+		return err
+	}
+	reviews, err := h.reviews.List(ctx, expert.ID, false, page)
+	if err != nil {
+		return err
+	}
+
+	resp := models.ExpertPublicProfileResponse{
+		ExpertID:    expert.ID,
+		Username:    expert.Username,
+		Available:   profile.Available,
+		Specialties: toProfileResponse(profile).Specialties,
+		Rating:      rating.Average,
+		Reviews:     rating.Reviews,
+		Latest:      []models.ExpertReviewResponse{},
+	}
+	for _, review := range reviews {
+		resp.Latest = append(resp.Latest, toReviewResponse(review))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Reviews of an expert
+// @Description All the reviews of an expert including the hidden ones, the newest first
+// @Tags expert
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Expert ID"
+// @Param page query int false "Page"
+// @Success 200 {array} models.ExpertReviewResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/experts/{id}/reviews [get]
+func (h *ReviewHandler) List(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_ADMIN {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only admins can see hidden reviews.")
+	}
+	expertID, err := strconv.Atoi(c.Param("id"))
+	if err != nil || expertID <= 0 {
+		return apperror.InvalidParameter("id")
+	}
+	page, _ := strconv.Atoi(c.QueryParam("page"))
+
+	reviews, err := h.reviews.List(c.Request().Context(), uint(expertID), true, page)
+	if err != nil {
+		return err
+	}
+
+	resp := []models.ExpertReviewResponse{}
+	for _, review := range reviews {
+		resp = append(resp, toReviewResponse(review))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Hide a review
+// @Description Admins hide abusive reviews from the profile and rating of the expert, or show them again
+// @Tags expert
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Review ID"
+// @Param body body models.HideReviewRequest true "Visibility"
+// @Success 200 {object} models.ExpertReviewResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
+// @Router /admin/expert-reviews/{id}/hide [put]
+func (h *ReviewHandler) Hide(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_ADMIN {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only admins can hide reviews.")
+	}
+	reviewID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	var body models.HideReviewRequest
+	errs := utils.BindJSON(c.Request().Body, &body)
+	if !errs.Has("body") && !errs.Has("hidden") && body.Hidden == nil {
+		errs.Add("hidden", consts.VALIDATION_REQUIRED, "hidden is required !")
+	}
+	if err := errs.Err(); err != nil {
+		return err
+	}
+
+	review, err := h.reviews.SetHidden(c.Request().Context(), reviewID, user, *body.Hidden)
+	if err != nil {
+		return err
+	}
+	description := fmt.Sprintf("review %d of expert %d hidden", review.ID, review.ExpertID)
+	if !review.Hidden {
+		description = fmt.Sprintf("review %d of expert %d shown again", review.ID, review.ExpertID)
+	}
+	logReview(user, review, consts.LOG_EXPERT_REVIEW_HIDDEN, description)
+
+	return c.JSON(http.StatusOK, toReviewResponse(review))
+}
+
+func logReview(user models.User, review models.ExpertReview, logName string, description string) {
+	// ____ Report Log ____
+	logService := logging_service.GetInstance()
+	if logService != (*logging_service.Logging)(nil) {
+		err := logService.ReportActivity(user.Role, user.ID, "ExpertAds", review.ExpertAdsID, logName, description)
+		if err != nil {
+			_ = fmt.Errorf("cannot log activity %v", logName)
+		}
+	}
+	// ____ Report Log ____
+}
+
+func toReviewResponse(review models.ExpertReview) models.ExpertReviewResponse {
+	return models.ExpertReviewResponse{
+		ID:        review.ID,
+		RequestID: review.ExpertAdsID,
+		ExpertID:  review.ExpertID,
+		UserID:    review.UserID,
+		Username:  review.User.Username,
+		Rating:    review.Rating,
+		Comment:   review.Comment,
+		Hidden:    review.Hidden,
+		HiddenAt:  review.HiddenAt,
+		CreatedAt: review.CreatedAt,
+	}
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	logging_service "Airplane-Divar/service/logging"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type FeaturedHandler struct {
+	FeaturedDatastore datastore.Featured
+}
+
+func NewFeaturedHandler(featuredDS datastore.Featured) *FeaturedHandler {
+	return &FeaturedHandler{
+		FeaturedDatastore: featuredDS,
+	}
+}
+
+// @Summary Request to feature an ad
+// @Description Creates an unpaid featured request for an active ad. Pay it through /users/payment/request with the featured_ads transaction type.
+// @Tags featured
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Ad ID"
+// @Success 201 {object} models.FeaturedAdResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /ads/{id}/feature [post]
+func (f *FeaturedHandler) RequestToFeature(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := c.Get("user").(models.User)
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	if user.Role != consts.ROLE_AIRLINE {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only airline user can feature an ad")
+	}
+
+	featuredAd, err := f.FeaturedDatastore.RequestToFeature(ctx, adID, user)
+	if err != nil {
+		return err
+	}
+
+	// ____ Report Log ____
+	logService := logging_service.GetInstance()
+	if logService != (*logging_service.Logging)(nil) {
+		err = logService.ReportActivity(user.Role, user.ID, "Ads", uint(adID), consts.LOG_FEATURED, "")
+		if err != nil {
+			_ = fmt.Errorf("cannot log activity %v", consts.LOG_FEATURED)
+		}
+	}
+	// ____ Report Log ____
+
+	return c.JSON(http.StatusCreated, models.FeaturedAdResponse{
+		ID:        int(featuredAd.ID),
+		AdID:      int(featuredAd.AdsID),
+		Status:    string(featuredAd.Status),
+		StartsAt:  featuredAd.StartsAt,
+		EndsAt:    featuredAd.EndsAt,
+		CreatedAt: featuredAd.CreatedAt,
+	})
+}
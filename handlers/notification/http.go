@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type NotificationHandler struct {
+	NotificationDatastore datastore.Notification
+}
+
+func NewNotificationHandler(notificationDS datastore.Notification) *NotificationHandler {
+	return &NotificationHandler{
+		NotificationDatastore: notificationDS,
+	}
+}
+
+// @Summary List notifications
+// @Description Notifications of the logged in user, newest first
+// @Tags notifications
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param unread query bool false "Only unread notifications"
+// @Success 200 {array} models.NotificationResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /notifications [get]
+func (n *NotificationHandler) ListNotifications(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	unreadOnly, _ := strconv.ParseBool(c.QueryParam("unread"))
+
+	notifications, err := n.NotificationDatastore.List(c.Request().Context(), user.ID, unreadOnly)
+	if err != nil {
+		return err
+	}
+
+	resp := []models.NotificationResponse{}
+	for _, notif := range notifications {
+		resp = append(resp, models.NotificationResponse{
+			ID:        notif.ID,
+			AdID:      notif.AdsID,
+			Kind:      notif.Kind,
+			Message:   notif.Message,
+			Read:      notif.ReadAt != nil,
+			CreatedAt: notif.CreatedAt,
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Mark a notification as read
+// @Tags notifications
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Notification ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /notifications/{id}/read [put]
+func (n *NotificationHandler) MarkRead(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	if err := n.NotificationDatastore.MarkRead(c.Request().Context(), id, user.ID); err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, models.SuccessResponse{Success: true})
+}
@@ -0,0 +1,307 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	logging_service "Airplane-Divar/service/logging"
+	watch_service "Airplane-Divar/service/watch"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+type OfferHandler struct {
+	OfferDatastore datastore.Offer
+}
+
+func NewOfferHandler(offerDS datastore.Offer) *OfferHandler {
+	return &OfferHandler{
+		OfferDatastore: offerDS,
+	}
+}
+
+// @Summary Make an offer
+// @Description Offer a price for an active ad. The offer expires at expires_at, 72 hours from now by default.
+// @Tags offers
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Ad ID"
+// @Param body body models.OfferRequest true "Offer"
+// @Success 201 {object} models.OfferResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /ads/{id}/offers [post]
+func (o *OfferHandler) CreateOffer(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := c.Get("user").(models.User)
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	if user.Role != consts.ROLE_AIRLINE {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only airline user can make an offer")
+	}
+
+	body, expiresAt, err := bindOffer(c)
+	if err != nil {
+		return err
+	}
+
+	created, err := o.OfferDatastore.Create(ctx, adID, user, body.Price, body.Message, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	reportOffer(user, created.AdsID, consts.LOG_OFFER_CREATE)
+
+	return c.JSON(http.StatusCreated, toOfferResponse(created))
+}
+
+// @Summary List offers of an ad
+// @Description The whole negotiation on an ad. The seller sees every buyer's thread, a buyer only their own.
+// @Tags offers
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Ad ID"
+// @Success 200 {array} models.OfferResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /ads/{id}/offers [get]
+func (o *OfferHandler) ListOffers(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	offers, err := o.OfferDatastore.List(c.Request().Context(), adID, user)
+	if err != nil {
+		return err
+	}
+
+	resp := []models.OfferResponse{}
+	for _, of := range offers {
+		resp = append(resp, toOfferResponse(of))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Get an offer
+// @Tags offers
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Offer ID"
+// @Success 200 {object} models.OfferResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /offers/{id} [get]
+func (o *OfferHandler) GetOffer(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	of, err := o.OfferDatastore.Get(c.Request().Context(), id, user)
+	if err != nil {
+		return err
+	}
+	return c.JSON(http.StatusOK, toOfferResponse(of))
+}
+
+// @Summary Accept an offer
+// @Description Accepting reserves the ad for the buyer and rejects every other pending offer on it
+// @Tags offers
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Offer ID"
+// @Success 200 {object} models.OfferResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /offers/{id}/accept [post]
+func (o *OfferHandler) AcceptOffer(c echo.Context) error {
+	accept := func(ctx context.Context, id int, user models.User) (models.Offer, error) {
+		of, err := o.OfferDatastore.Accept(ctx, id, user)
+		if err != nil {
+			return of, err
+		}
+
+		// ____ Notify Watchers ____
+		watchService := watch_service.GetInstance()
+		if watchService != (*watch_service.Watch)(nil) {
+			err = watchService.StatusChanged(ctx, of.AdsID, consts.ACTIVE, consts.RESERVED)
+			if err != nil {
+				log.Printf("could not notify the watchers of ad %d: %v", of.AdsID, err)
+			}
+		}
+		// ____ Notify Watchers ____
+
+		return of, nil
+	}
+	return o.respond(c, accept, consts.LOG_OFFER_ACCEPT)
+}
+
+// @Summary Reject an offer
+// @Tags offers
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Offer ID"
+// @Success 200 {object} models.OfferResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /offers/{id}/reject [post]
+func (o *OfferHandler) RejectOffer(c echo.Context) error {
+	return o.respond(c, o.OfferDatastore.Reject, consts.LOG_OFFER_REJECT)
+}
+
+// @Summary Complete a deal
+// @Description The seller marks the reserved ad of an accepted offer as sold
+// @Tags offers
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Offer ID"
+// @Success 200 {object} models.OfferResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /offers/{id}/complete [post]
+func (o *OfferHandler) CompleteOffer(c echo.Context) error {
+	complete := func(ctx context.Context, id int, user models.User) (models.Offer, error) {
+		of, err := o.OfferDatastore.Complete(ctx, id, user)
+		if err != nil {
+			return of, err
+		}
+
+		// ____ Notify Watchers ____
+		watchService := watch_service.GetInstance()
+		if watchService != (*watch_service.Watch)(nil) {
+			err = watchService.StatusChanged(ctx, of.AdsID, consts.RESERVED, consts.SOLD)
+			if err != nil {
+				log.Printf("could not notify the watchers of ad %d: %v", of.AdsID, err)
+			}
+		}
+		// ____ Notify Watchers ____
+
+		return of, nil
+	}
+	return o.respond(c, complete, consts.LOG_AD_SOLD)
+}
+
+// @Summary Counter an offer
+// @Description Answer an offer with another price. The answered offer is closed and the counter offer waits for the other party.
+// @Tags offers
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Offer ID"
+// @Param body body models.OfferRequest true "Counter offer"
+// @Success 201 {object} models.OfferResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /offers/{id}/counter [post]
+func (o *OfferHandler) CounterOffer(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	body, expiresAt, err := bindOffer(c)
+	if err != nil {
+		return err
+	}
+
+	counter, err := o.OfferDatastore.Counter(c.Request().Context(), id, user, body.Price, body.Message, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	reportOffer(user, counter.AdsID, consts.LOG_OFFER_COUNTER)
+
+	return c.JSON(http.StatusCreated, toOfferResponse(counter))
+}
+
+func (o *OfferHandler) respond(
+	c echo.Context,
+	action func(ctx context.Context, id int, user models.User) (models.Offer, error),
+	logName string,
+) error {
+	user := c.Get("user").(models.User)
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	of, err := action(c.Request().Context(), id, user)
+	if err != nil {
+		return err
+	}
+
+	reportOffer(user, of.AdsID, logName)
+
+	return c.JSON(http.StatusOK, toOfferResponse(of))
+}
+
+func bindOffer(c echo.Context) (models.OfferRequest, time.Time, error) {
+	var body models.OfferRequest
+	if err := c.Bind(&body); err != nil {
+		return body, time.Time{}, err
+	}
+	if body.Price == 0 {
+		return body, time.Time{}, apperror.InvalidBody("price is required")
+	}
+
+	expiresAt := time.Now().Add(consts.DEFAULT_OFFER_EXPIRY_HOURS * time.Hour)
+	if body.ExpiresAt != nil {
+		if !body.ExpiresAt.After(time.Now()) {
+			return body, time.Time{}, apperror.InvalidBody("expires_at should be in the future")
+		}
+		expiresAt = *body.ExpiresAt
+	}
+	return body, expiresAt, nil
+}
+
+func reportOffer(user models.User, adID uint, logName string) {
+	// ____ Report Log ____
+	logService := logging_service.GetInstance()
+	if logService != (*logging_service.Logging)(nil) {
+		err := logService.ReportActivity(user.Role, user.ID, "Ads", adID, logName, "")
+		if err != nil {
+			_ = fmt.Errorf("cannot log activity %v", logName)
+		}
+	}
+	// ____ Report Log ____
+}
+
+func toOfferResponse(of models.Offer) models.OfferResponse {
+	return models.OfferResponse{
+		ID:         of.ID,
+		AdID:       of.AdsID,
+		BuyerID:    of.BuyerID,
+		SellerID:   of.SellerID,
+		ProposedBy: of.ProposedBy,
+		ParentID:   of.ParentID,
+		Price:      of.Price,
+		Message:    of.Message,
+		Status:     string(of.Status),
+		ExpiresAt:  of.ExpiresAt,
+		CreatedAt:  of.CreatedAt,
+	}
+}
@@ -1,14 +1,19 @@
 package handlers
 
 import (
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/consts"
 	"Airplane-Divar/datastore"
 	"Airplane-Divar/models"
+	assignment_service "Airplane-Divar/service/assignment"
 	logging_service "Airplane-Divar/service/logging"
+	"Airplane-Divar/utils"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -22,6 +27,13 @@ const (
 	zarinpalGateURL = "https://sandbox.banktest.ir/zarinpal/www.zarinpal.com/pg/StartPay/"
 )
 
+var (
+	errAlreadyPaid         = apperror.Conflict("already_paid", "it's already payed!")
+	errServiceNotFound     = apperror.NotFound("service_not_found", "service not found!")
+	errTransactionNotFound = apperror.NotFound("transaction_not_found", "Transaction Not Founded")
+	errPaymentFailed       = apperror.BadRequest("payment_failed", "Failed Payment")
+)
+
 type ZarinpalData struct {
 	Code      int    `json:"code"`
 	Message   string `json:"message"`
@@ -56,8 +68,8 @@ type ZarinpalErrors struct {
 }
 
 type PaymentRequest struct {
-	AdID             int      `json:"adID" binding:"required"`
-	TransactionTypes []string `json:"transactionType" binding:"required"`
+	AdID             int      `json:"adID" validate:"required"`
+	TransactionTypes []string `json:"transactionType" validate:"required"`
 }
 
 type TransactioTypeObject struct {
@@ -75,23 +87,26 @@ type RequestResponse struct {
 }
 
 type PaymentHandler struct {
-	UserDS    datastore.User
-	ExpertDS  datastore.Expert
-	RepairDS  datastore.Repair
-	PaymentDS datastore.Payment
+	UserDS     datastore.User
+	ExpertDS   datastore.Expert
+	RepairDS   datastore.Repair
+	FeaturedDS datastore.Featured
+	PaymentDS  datastore.Payment
 }
 
 func NewPaymentHandler(
 	userDS datastore.User,
 	expertDS datastore.Expert,
 	repairDS datastore.Repair,
+	featuredDS datastore.Featured,
 	paymentDS datastore.Payment,
 ) *PaymentHandler {
 	return &PaymentHandler{
-		ExpertDS:  expertDS,
-		RepairDS:  repairDS,
-		UserDS:    userDS,
-		PaymentDS: paymentDS,
+		ExpertDS:   expertDS,
+		RepairDS:   repairDS,
+		FeaturedDS: featuredDS,
+		UserDS:     userDS,
+		PaymentDS:  paymentDS,
 	}
 }
 
@@ -103,26 +118,24 @@ func NewPaymentHandler(
 // @Param Authorization header string true "User Token"
 // @Param body body PaymentRequest true "Payment request details"
 // @Success 200 {object} RequestResponse
-// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
 // @Failure 422 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
 // @Router /users/payment/request [post]
 func (p PaymentHandler) PaymentRequestHandler(c echo.Context) error {
 	user := c.Get("user").(models.User)
 	ctx := c.Request().Context()
 
 	if user.Role != consts.ROLE_AIRLINE {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "Not allowed!",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "Not allowed!")
 	}
 
 	var requestBody PaymentRequest
-	if err := c.Bind(&requestBody); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	if errs := validatePaymentRequest(c, &requestBody); len(errs) > 0 {
+		return errs.Err()
 	}
 
 	// get service requests
@@ -131,18 +144,10 @@ func (p PaymentHandler) PaymentRequestHandler(c echo.Context) error {
 		if tType == models.ExpertAds.TableName(models.ExpertAds{}) {
 			expertAd, err := p.ExpertDS.GetByAd(ctx, requestBody.AdID, user)
 			if err != nil {
-				return c.JSON(
-					http.StatusNotFound, models.ErrorResponse{
-						Error: "You don't have any expert request",
-					},
-				)
+				return apperror.NotFound(apperror.CodeNotFound, "You don't have any expert request")
 			}
 			if expertAd.Status != consts.WAIT_FOR_PAYMENT_STATUS {
-				return c.JSON(
-					http.StatusNotFound, models.ErrorResponse{
-						Error: "it's already payed!",
-					},
-				)
+				return errAlreadyPaid
 			}
 			requestIds = append(requestIds, TransactioTypeObject{
 				Type: tType, ObjectID: expertAd.ID,
@@ -151,36 +156,36 @@ func (p PaymentHandler) PaymentRequestHandler(c echo.Context) error {
 		} else if tType == models.RepairRequest.TableName(models.RepairRequest{}) {
 			repairRequest, err := p.RepairDS.GetByAd(ctx, requestBody.AdID, user)
 			if err != nil {
-				return c.JSON(
-					http.StatusNotFound, models.ErrorResponse{
-						Error: "You don't have any repair request",
-					},
-				)
+				return apperror.NotFound(apperror.CodeNotFound, "You don't have any repair request")
 			}
 			if repairRequest.Status != consts.WAIT_FOR_PAYMENT_STATUS {
-				return c.JSON(
-					http.StatusNotFound, models.ErrorResponse{
-						Error: "it's already payed!",
-					},
-				)
+				return errAlreadyPaid
 			}
 			requestIds = append(requestIds, TransactioTypeObject{
 				Type: tType, ObjectID: repairRequest.ID,
 			})
 
+		} else if tType == models.FeaturedAd.TableName(models.FeaturedAd{}) {
+			featuredAd, err := p.FeaturedDS.GetByAd(ctx, requestBody.AdID, user)
+			if err != nil {
+				return apperror.NotFound(apperror.CodeNotFound, "You don't have any featured request")
+			}
+			if featuredAd.Status != consts.WAIT_FOR_PAYMENT_STATUS {
+				return errAlreadyPaid
+			}
+			requestIds = append(requestIds, TransactioTypeObject{
+				Type: tType, ObjectID: featuredAd.ID,
+			})
+
 		} else {
-			return c.JSON(
-				http.StatusNotFound, models.ErrorResponse{
-					Error: "service not found!",
-				},
-			)
+			return errServiceNotFound
 		}
 	}
 
 	// calculate price
 	prices, err := p.PaymentDS.GetPriceByServices(ctx, requestBody.TransactionTypes)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, "Error")
+		return err
 	}
 	total_price := p.PaymentDS.GetTotalPriceByServices(prices)
 
@@ -197,35 +202,23 @@ func (p PaymentHandler) PaymentRequestHandler(c echo.Context) error {
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.Response{
-			ResponseCode: 500,
-			Message:      "Failed to marshal JSON data",
-		})
+		return apperror.Internal("Failed to marshal JSON data")
 	}
 
 	resp, err := http.Post(zarinpalRequest, "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return c.JSON(http.StatusBadGateway, models.Response{
-			ResponseCode: 502,
-			Message:      "Failed to send POST request",
-		})
+		return apperror.Upstream("Failed to send POST request")
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.Response{
-			ResponseCode: 500,
-			Message:      "Failed to read body",
-		})
+		return apperror.Internal("Failed to read body")
 	}
 
 	var result ZarinpalResponse
 	if err := json.Unmarshal(body, &result); err != nil {
-		return c.JSON(http.StatusInternalServerError, models.Response{
-			ResponseCode: 500,
-			Message:      "Failed to parse response",
-		})
+		return apperror.Internal("Failed to parse response")
 	}
 
 	//create payment Transaction
@@ -235,7 +228,7 @@ func (p PaymentHandler) PaymentRequestHandler(c echo.Context) error {
 			service.Type, service.ObjectID,
 		)
 		if transactionCreationErr != nil {
-			return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: transactionCreationMsg})
+			return apperror.Internal(transactionCreationMsg)
 		}
 	}
 
@@ -263,9 +256,10 @@ func (p PaymentHandler) PaymentRequestHandler(c echo.Context) error {
 // @Param Authorization header string true "User Token"
 // @Param body body VerifyResponse true "Payment verify details"
 // @Success 200 {string} string
-// @Failure 400 {string} models.ErrorResponse
-// @Failure 422 {string} models.ErrorResponse
-// @Failure 500 {string} models.ErrorResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse
 // @Router /users/payment/verify [get]
 func (p PaymentHandler) PaymentVerifyHandler(c echo.Context) error {
 	ctx := c.Request().Context()
@@ -275,7 +269,7 @@ func (p PaymentHandler) PaymentVerifyHandler(c echo.Context) error {
 	transactions, err := p.PaymentDS.FindByAuthority(authority)
 	if err != nil {
 		// Handle the error (e.g., transaction not found)
-		return c.JSON(http.StatusNotFound, models.Response{ResponseCode: 404, Message: "Transaction Not Founded"})
+		return errTransactionNotFound
 	}
 	transactionsIDS := []uint{}
 	var totoalAmount int64 = 0
@@ -312,7 +306,7 @@ func (p PaymentHandler) PaymentVerifyHandler(c echo.Context) error {
 		p.PaymentDS.Update("Faield", transactionsIDS)
 		log_status_temp = -1
 
-		return c.JSON(http.StatusBadRequest, "Failed Payment")
+		return errPaymentFailed
 	}
 
 	data := map[string]interface{}{
@@ -323,18 +317,12 @@ func (p PaymentHandler) PaymentVerifyHandler(c echo.Context) error {
 
 	jsonData, err := json.Marshal(data)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, models.Response{
-			ResponseCode: 500,
-			Message:      "Failed to marshal JSON data",
-		})
+		return apperror.Internal("Failed to marshal JSON data")
 	}
 
 	resp, err := http.Post(zarinpalVerify, "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return c.JSON(http.StatusBadGateway, models.Response{
-			ResponseCode: 502,
-			Message:      "Failed to send POST request",
-		})
+		return apperror.Upstream("Failed to send POST request")
 	}
 	defer resp.Body.Close()
 
@@ -342,7 +330,7 @@ func (p PaymentHandler) PaymentVerifyHandler(c echo.Context) error {
 	jsonBody := make(map[string]interface{})
 	err = json.NewDecoder(resp.Body).Decode(&jsonBody)
 	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: "Invalid JSON"})
+		return apperror.Upstream("Invalid JSON")
 	}
 
 	if data, ok := jsonBody["data"]; ok {
@@ -358,17 +346,22 @@ func (p PaymentHandler) PaymentVerifyHandler(c echo.Context) error {
 								ctx, int(t.ObjectID),
 								map[string]interface{}{"status": consts.EXPERT_PENDING_STATUS},
 							)
+							if err == nil {
+								assignExpert(ctx, int(t.ObjectID))
+							}
 						} else if t.TransactionType == models.RepairRequest.TableName(models.RepairRequest{}) {
 							err = p.RepairDS.Update(
 								ctx, int(t.ObjectID),
 								map[string]interface{}{"status": consts.MATIN_PENDING_STATUS},
 							)
+						} else if t.TransactionType == models.FeaturedAd.TableName(models.FeaturedAd{}) {
+							err = p.FeaturedDS.Activate(ctx, int(t.ObjectID))
+							if err == nil {
+								reportFeaturedActivated(t)
+							}
 						}
 						if err != nil {
-							return c.JSON(http.StatusInternalServerError, models.Response{
-								ResponseCode: http.StatusInternalServerError,
-								Message:      "Failed to update status",
-							})
+							return apperror.Internal("Failed to update status")
 						}
 					}
 					log_status_temp = 1
@@ -384,5 +377,54 @@ func (p PaymentHandler) PaymentVerifyHandler(c echo.Context) error {
 	p.PaymentDS.Update("Faield", transactionsIDS)
 	log_status_temp = -1
 
-	return c.JSON(http.StatusBadRequest, "Failed Payment")
+	return errPaymentFailed
+}
+
+func validatePaymentRequest(c echo.Context, requestBody *PaymentRequest) utils.ValidationErrors {
+	errs := utils.BindJSON(c.Request().Body, requestBody)
+	if errs.Has("body") {
+		return errs
+	}
+
+	if !errs.Has("adID") && requestBody.AdID <= 0 {
+		errs.Add("adID", consts.VALIDATION_INVALID, "adID should be an ad id !")
+	}
+	if !errs.Has("transactionType") {
+		if len(requestBody.TransactionTypes) == 0 {
+			errs.Add("transactionType", consts.VALIDATION_REQUIRED, "transactionType should name at least one service !")
+		}
+		services := map[string]bool{
+			models.ExpertAds{}.TableName():     true,
+			models.RepairRequest{}.TableName(): true,
+			models.FeaturedAd{}.TableName():    true,
+		}
+		for i, tType := range requestBody.TransactionTypes {
+			if !services[tType] {
+				errs.Add(fmt.Sprintf("transactionType[%d]", i), consts.VALIDATION_UNKNOWN, fmt.Sprintf("%s is not a service !", tType))
+			}
+		}
+	}
+	return errs
+}
+
+func reportFeaturedActivated(t models.Transaction) {
+	logService := logging_service.GetInstance()
+	if logService != (*logging_service.Logging)(nil) {
+		err := logService.ReportActivity("", t.UserID, "Transaction", t.ID, consts.LOG_FEATURED_ACTIVE, "")
+		if err != nil {
+			_ = fmt.Errorf("cannot log activity %v", consts.LOG_FEATURED_ACTIVE)
+		}
+	}
+}
+
+// assignExpert gives a paid expert request to an expert, if that fails the
+// request waits for an expert to claim it.
+func assignExpert(ctx context.Context, requestID int) {
+	assigner := assignment_service.GetInstance()
+	if assigner == (*assignment_service.Assigner)(nil) {
+		return
+	}
+	if _, _, err := assigner.Assign(ctx, requestID); err != nil {
+		log.Printf("could not assign expert request %d: %v", requestID, err)
+	}
 }
@@ -1,12 +1,16 @@
 package handlers
 
 import (
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/consts"
 	"Airplane-Divar/datastore"
 	"Airplane-Divar/datastore/repair"
 	"Airplane-Divar/models"
 	logging_service "Airplane-Divar/service/logging"
+	"Airplane-Divar/utils"
+	"Airplane-Divar/utils/pdf"
 	"fmt"
+	"mime"
 	"net/http"
 	"strconv"
 
@@ -14,6 +18,8 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+var ErrRepairNotDone = apperror.Conflict("repair_not_done", "only finished repair requests can be printed")
+
 type RepairHandler struct {
 	RepairDatastore datastore.Repair
 	UserDatastore   datastore.User
@@ -34,8 +40,9 @@ func NewRepairHandler(repairDS datastore.Repair, userDS datastore.User) *RepairH
 // @Param Authorization header string true "User Token"
 // @Param adID path int true "Ad ID"
 // @Success 200 {object} models.SuccessResponse
-// @Failure 204 {object} models.ErrorResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /repair/ads/{adID}/request [post]
 func (e *RepairHandler) RequestToRepairCheck(c echo.Context) error {
@@ -43,21 +50,16 @@ func (e *RepairHandler) RequestToRepairCheck(c echo.Context) error {
 	user := c.Get("user").(models.User)
 	adID, err := strconv.Atoi(c.Param("adID"))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return apperror.InvalidParameter("adID")
 	}
 
 	if user.Role != consts.ROLE_AIRLINE {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "Only airline user can send request for repairing",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "Only airline user can send request for repairing")
 	}
 
 	err = e.RepairDatastore.RequestToRepairCheck(ctx, adID, user)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return err
 	}
 
 	resp := models.SuccessResponse{
@@ -86,6 +88,7 @@ func (e *RepairHandler) RequestToRepairCheck(c echo.Context) error {
 // @Success 200 {object} models.MessageResponse
 // @Success 200 {object} models.MessageResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /repair/ads/{adID} [get]
 func (e *RepairHandler) GetRepairRequestByAd(c echo.Context) error {
@@ -94,19 +97,12 @@ func (e *RepairHandler) GetRepairRequestByAd(c echo.Context) error {
 	adID, _ := strconv.Atoi(c.Param("adID"))
 
 	if user.Role == consts.ROLE_EXPERT {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "You don't have access to repair requests.",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "You don't have access to repair requests.")
 	}
 
 	repairRequest, err := e.RepairDatastore.GetByAd(ctx, adID, user)
 	if err != nil {
-		return c.JSON(
-			http.StatusNotFound, models.ErrorResponse{Error: "repair request not found!"},
-		)
+		return err
 	}
 
 	resp := models.GetRepairRequestResponse{
@@ -126,8 +122,9 @@ func (e *RepairHandler) GetRepairRequestByAd(c echo.Context) error {
 // @Param Authorization header string true "User Token"
 // @Param requestID path int true "ad ID"
 // @Success 200 {object} models.GetRepairRequestResponse
-// @Failure 204 {object} models.ErrorResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /repair/request/{requestID} [get]
 func (e *RepairHandler) GetRepairRequest(c echo.Context) error {
@@ -136,19 +133,12 @@ func (e *RepairHandler) GetRepairRequest(c echo.Context) error {
 	requestID, _ := strconv.Atoi(c.Param("requestID"))
 
 	if user.Role == consts.ROLE_EXPERT {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "You don't have access to repair requests.",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "You don't have access to repair requests.")
 	}
 
 	repairRequest, err := e.RepairDatastore.Get(ctx, requestID, user)
 	if err != nil {
-		return c.JSON(
-			http.StatusNotFound, models.ErrorResponse{Error: "repair request not found!"},
-		)
+		return err
 	}
 
 	resp := models.GetRepairRequestResponse{
@@ -162,6 +152,44 @@ func (e *RepairHandler) GetRepairRequest(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// @Summary Print a finished repair request
+// @Description The repair summary as a PDF with the airplane and the repair
+// @Tags repair
+// @Produce application/pdf
+// @Param Authorization header string true "User Token"
+// @Param requestID path int true "request ID"
+// @Success 200 {file} file
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /repair/request/{requestID}/pdf [get]
+func (e *RepairHandler) GetRepairRequestPDF(c echo.Context) error {
+	ctx := c.Request().Context()
+	user := c.Get("user").(models.User)
+	requestID, _ := strconv.Atoi(c.Param("requestID"))
+
+	if user.Role == consts.ROLE_EXPERT {
+		return apperror.Forbidden(apperror.CodeForbidden, "You don't have access to repair requests.")
+	}
+
+	repairRequest, err := e.RepairDatastore.Summary(ctx, requestID, user)
+	if err != nil {
+		return err
+	}
+	if repairRequest.Status != consts.DONE_STATUS {
+		return ErrRepairNotDone
+	}
+
+	document, err := pdf.RenderRepairSummary(repairRequest)
+	if err != nil {
+		return err
+	}
+
+	filename := fmt.Sprintf("repair-summary-%d.pdf", repairRequest.ID)
+	c.Response().Header().Set(echo.HeaderContentDisposition, mime.FormatMediaType("inline", map[string]string{"filename": filename}))
+	return c.Blob(http.StatusOK, "application/pdf", document)
+}
+
 // @Summary ListRepairRequest retrieves all repair requests for an repair
 // @Description ListRepairRequest retrieves all repair requests for an repair
 // @Tags repair
@@ -172,6 +200,7 @@ func (e *RepairHandler) GetRepairRequest(c echo.Context) error {
 // @Success 200 {array} models.RepairRequestResponse
 // @Success 200 {object} models.MessageResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /repair/requests [get]
 func (e *RepairHandler) GetAllRepairRequest(c echo.Context) error {
@@ -180,12 +209,7 @@ func (e *RepairHandler) GetAllRepairRequest(c echo.Context) error {
 	user := c.Get("user").(models.User)
 
 	if user.Role == consts.ROLE_EXPERT {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "You don't have access to repair requests.",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "You don't have access to repair requests.")
 	}
 
 	// params
@@ -220,7 +244,7 @@ func (e *RepairHandler) GetAllRepairRequest(c echo.Context) error {
 		ctx, queryAndCondition, queryOrConditions, queryNotCondition, page,
 	)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+		return err
 
 	}
 	if len(repairRequests) == 0 {
@@ -254,8 +278,11 @@ func (e *RepairHandler) GetAllRepairRequest(c echo.Context) error {
 // @Param repairRequestID path int true "repair request ID"
 // @Param repairCheckRequest body models.UpdateRepairRequest true "repair object"
 // @Success 200 {object} models.RepairRequestResponse
-// @Failure 204 {object} models.ErrorResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /repair/request/{repairRequestID} [put]
 func (e *RepairHandler) UpdateRepairRequest(c echo.Context) error {
@@ -263,30 +290,27 @@ func (e *RepairHandler) UpdateRepairRequest(c echo.Context) error {
 	user := c.Get("user").(models.User)
 
 	if user.Role != consts.ROLE_MATIN {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "You don't have access to repair requests.",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "You don't have access to repair requests.")
 	}
 
 	repairRequestID, _ := strconv.Atoi(c.Param("repairRequestID"))
 
 	var updatedRepairRequest models.UpdateRepairRequest
-	if err := c.Bind(&updatedRepairRequest); err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+	errs := utils.BindJSON(c.Request().Body, &updatedRepairRequest)
+	if !errs.Has("body") {
+		errs.Merge(utils.ValidateRepairUpdate(updatedRepairRequest))
+	}
+	if len(errs) > 0 {
+		return errs.Err()
 	}
 
 	repairRequest, err := e.RepairDatastore.UpdateByUser(
 		ctx, repairRequestID, user, updatedRepairRequest,
 	)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, err.Error())
+		return err
 	} else if repairRequest.ID == 0 {
-		return c.JSON(http.StatusNotFound, models.ErrorResponse{
-			Error: "repair request does not exist!",
-		})
+		return repair.ErrRequestNotFound
 	}
 
 	resp := models.RepairRequestResponse{
@@ -315,9 +339,10 @@ func (e *RepairHandler) UpdateRepairRequest(c echo.Context) error {
 // @Tags repair
 // @Param Authorization header string true "User Token"
 // @Param adID path int true "ad ID"
-// @Success 200 {object} models.SuccessResponse
-// @Failure 204 {object} models.ErrorResponse
+// @Success 204
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /repair/ads/{adID} [delete]
 func (e *RepairHandler) DeleteRepairRequest(c echo.Context) error {
@@ -326,21 +351,14 @@ func (e *RepairHandler) DeleteRepairRequest(c echo.Context) error {
 	adID, _ := strconv.Atoi(c.Param("adID"))
 
 	if user.Role != consts.ROLE_AIRLINE {
-		return c.JSON(
-			http.StatusForbidden,
-			models.ErrorResponse{
-				Error: "You can't delete this repair request.",
-			},
-		)
+		return apperror.Forbidden(apperror.CodeForbidden, "You can't delete this repair request.")
 	}
 
 	err := e.RepairDatastore.Delete(ctx, adID, user)
 	if err != nil {
-		return c.JSON(
-			http.StatusNotFound, models.ErrorResponse{Error: "Repair request not found!"},
-		)
+		return err
 	}
 
-	return c.JSON(http.StatusNoContent, models.SuccessResponse{Success: true})
+	return c.NoContent(http.StatusNoContent)
 
 }
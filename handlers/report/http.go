@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	logging_service "Airplane-Divar/service/logging"
+	watch_service "Airplane-Divar/service/watch"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+var reasons = map[string]bool{
+	consts.REPORT_FRAUD:          true,
+	consts.REPORT_DUPLICATE:      true,
+	consts.REPORT_WRONG_CATEGORY: true,
+	consts.REPORT_OFFENSIVE:      true,
+}
+
+type ReportHandler struct {
+	ReportDatastore datastore.Report
+}
+
+func NewReportHandler(reportDS datastore.Report) *ReportHandler {
+	return &ReportHandler{
+		ReportDatastore: reportDS,
+	}
+}
+
+// @Summary Report an ad
+// @Description Reports an ad for abuse. The ad is hidden once enough distinct users have reported it.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Ad ID"
+// @Param body body models.ReportAdRequest true "Report"
+// @Success 201 {object} models.ReportResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /ads/{id}/report [post]
+func (h *ReportHandler) ReportAd(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	adID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	var body models.ReportAdRequest
+	if err := c.Bind(&body); err != nil {
+		return err
+	}
+	if !reasons[body.Reason] {
+		return apperror.InvalidBody("reason should be one of fraud, duplicate, wrong_category, offensive")
+	}
+
+	ctx := c.Request().Context()
+	rep, hidden, err := h.ReportDatastore.Create(ctx, adID, user, body.Reason, strings.TrimSpace(body.Note))
+	if err != nil {
+		return err
+	}
+
+	logActivity(user.Role, user.ID, rep.AdsID, consts.LOG_AD_REPORTED, rep.Reason)
+	if hidden {
+		logActivity("System", 0, rep.AdsID, consts.LOG_AD_AUTO_HIDDEN, "")
+		notifyWatchers(ctx, rep.AdsID, consts.ACTIVE, consts.HIDDEN)
+	}
+
+	return c.JSON(http.StatusCreated, toReportResponse(rep))
+}
+
+// @Summary Reports triage queue
+// @Description Abuse reports, the oldest first
+// @Tags reports
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param status query string false "Report status" Enums(Open, Dismissed, Upheld)
+// @Success 200 {array} models.ReportResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/reports [get]
+func (h *ReportHandler) ListReports(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_ADMIN {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only admin can review reports")
+	}
+
+	reports, err := h.ReportDatastore.List(c.Request().Context(), consts.ReportStatus(c.QueryParam("status")))
+	if err != nil {
+		return err
+	}
+
+	resp := []models.ReportResponse{}
+	for _, rep := range reports {
+		resp = append(resp, toReportResponse(rep))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary Resolve a report
+// @Description Upholding a report deactivates the ad and resolves all of its open reports. Dismissing it restores a hidden ad that is back under the threshold.
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param id path int true "Report ID"
+// @Param body body models.ResolveReportRequest true "Outcome"
+// @Success 200 {object} models.ReportResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Router /admin/reports/{id}/resolve [put]
+func (h *ReportHandler) ResolveReport(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_ADMIN {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only admin can resolve reports")
+	}
+
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return apperror.InvalidParameter("id")
+	}
+
+	var body models.ResolveReportRequest
+	if err := c.Bind(&body); err != nil {
+		return err
+	}
+
+	ctx := c.Request().Context()
+	rep, deactivated, err := h.ReportDatastore.Resolve(ctx, id, user, body.Outcome)
+	if err != nil {
+		return err
+	}
+	if deactivated != "" {
+		notifyWatchers(ctx, rep.AdsID, deactivated, consts.INACTIVE)
+	}
+
+	logName := consts.LOG_REPORT_DISMISSED
+	if rep.Status == consts.REPORT_UPHELD {
+		logName = consts.LOG_REPORT_UPHELD
+	}
+	description := fmt.Sprintf("report %d (%s)", rep.ID, rep.Reason)
+	if note := strings.TrimSpace(body.Note); note != "" {
+		description += ": " + note
+	}
+	logActivity(user.Role, user.ID, rep.AdsID, logName, description)
+
+	return c.JSON(http.StatusOK, toReportResponse(rep))
+}
+
+func logActivity(causerType string, causerID uint, adID uint, logName string, description string) {
+	// ____ Report Log ____
+	logService := logging_service.GetInstance()
+	if logService != (*logging_service.Logging)(nil) {
+		err := logService.ReportActivity(causerType, causerID, "Ads", adID, logName, description)
+		if err != nil {
+			_ = fmt.Errorf("cannot log activity %v", logName)
+		}
+	}
+	// ____ Report Log ____
+}
+
+func notifyWatchers(ctx context.Context, adID uint, from consts.AdStatus, to consts.AdStatus) {
+	// ____ Notify Watchers ____
+	watchService := watch_service.GetInstance()
+	if watchService != (*watch_service.Watch)(nil) {
+		err := watchService.StatusChanged(ctx, adID, from, to)
+		if err != nil {
+			log.Printf("could not notify the watchers of ad %d: %v", adID, err)
+		}
+	}
+	// ____ Notify Watchers ____
+}
+
+func toReportResponse(rep models.Report) models.ReportResponse {
+	return models.ReportResponse{
+		ID:         rep.ID,
+		AdID:       rep.AdsID,
+		UserID:     rep.UserID,
+		Reason:     rep.Reason,
+		Note:       rep.Note,
+		Status:     string(rep.Status),
+		ResolvedBy: rep.ResolvedBy,
+		ResolvedAt: rep.ResolvedAt,
+		CreatedAt:  rep.CreatedAt,
+	}
+}
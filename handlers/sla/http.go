@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	dateLayout       = "2006-01-02"
+	complianceWindow = 30 * 24 * time.Hour
+)
+
+type SLAHandler struct {
+	SLADatastore datastore.SLA
+}
+
+func NewSLAHandler(slaDS datastore.SLA) *SLAHandler {
+	return &SLAHandler{
+		SLADatastore: slaDS,
+	}
+}
+
+// @Summary Overdue requests
+// @Description Expert or repair requests that are past their SLA right now, the longest overdue first
+// @Tags sla
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param kind query string true "Request kind" Enums(expert, repair)
+// @Success 200 {array} models.SLAPeriodResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/sla/overdue [get]
+func (h *SLAHandler) ListOverdue(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_ADMIN {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only admin can see overdue requests")
+	}
+
+	now := time.Now()
+	periods, err := h.SLADatastore.Overdue(c.Request().Context(), c.QueryParam("kind"), now)
+	if err != nil {
+		return err
+	}
+
+	resp := []models.SLAPeriodResponse{}
+	for _, period := range periods {
+		resp = append(resp, toPeriodResponse(period, now))
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// @Summary SLA compliance report
+// @Description How many periods of each status met their SLA per expert or repair provider. Periods are picked by the day they started, the last 30 days by default.
+// @Tags sla
+// @Produce json
+// @Param Authorization header string true "User Token"
+// @Param kind query string true "Request kind" Enums(expert, repair)
+// @Param from query string false "First day, YYYY-MM-DD"
+// @Param to query string false "Last day, YYYY-MM-DD"
+// @Success 200 {array} models.SLAComplianceResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/sla/compliance [get]
+func (h *SLAHandler) Compliance(c echo.Context) error {
+	user := c.Get("user").(models.User)
+	if user.Role != consts.ROLE_ADMIN {
+		return apperror.Forbidden(apperror.CodeForbidden, "Only admin can see SLA compliance")
+	}
+
+	now := time.Now()
+	to := now
+	if param := c.QueryParam("to"); param != "" {
+		day, err := time.Parse(dateLayout, param)
+		if err != nil {
+			return apperror.InvalidParameter("to")
+		}
+		to = day.AddDate(0, 0, 1)
+	}
+	from := to.Add(-complianceWindow)
+	if param := c.QueryParam("from"); param != "" {
+		day, err := time.Parse(dateLayout, param)
+		if err != nil {
+			return apperror.InvalidParameter("from")
+		}
+		from = day
+	}
+	if !from.Before(to) {
+		return apperror.InvalidParameter("from")
+	}
+
+	report, err := h.SLADatastore.Compliance(c.Request().Context(), c.QueryParam("kind"), from, to, now)
+	if err != nil {
+		return err
+	}
+
+	resp := []models.SLAComplianceResponse{}
+	for _, row := range report {
+		rate := 0.0
+		if row.Met+row.Breached > 0 {
+			rate = float64(row.Met) / float64(row.Met+row.Breached)
+		}
+		resp = append(resp, models.SLAComplianceResponse{
+			AssigneeID:     row.AssigneeID,
+			Status:         string(row.Status),
+			Total:          row.Total,
+			Met:            row.Met,
+			Breached:       row.Breached,
+			Open:           row.Open,
+			ComplianceRate: rate,
+			AverageHours:   row.AverageHours,
+		})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+func toPeriodResponse(period models.SLAPeriod, now time.Time) models.SLAPeriodResponse {
+	resp := models.SLAPeriodResponse{
+		ID:          period.ID,
+		Kind:        period.Kind,
+		RequestID:   period.RequestID,
+		AdID:        period.AdsID,
+		Status:      string(period.Status),
+		AssigneeID:  period.AssigneeID,
+		StartedAt:   period.StartedAt,
+		DueAt:       period.DueAt,
+		EscalatedAt: period.EscalatedAt,
+	}
+	if period.DueAt != nil && now.After(*period.DueAt) {
+		resp.OverdueHours = now.Sub(*period.DueAt).Hours()
+	}
+	return resp
+}
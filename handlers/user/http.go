@@ -1,12 +1,11 @@
 package handlers
 
 import (
+	"Airplane-Divar/apperror"
 	"Airplane-Divar/config"
 	"Airplane-Divar/consts"
 	"Airplane-Divar/datastore"
-	"Airplane-Divar/models"
 	"Airplane-Divar/utils"
-	"encoding/json"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
@@ -21,18 +20,15 @@ type UserResponse struct {
 	Token    string `json:"Token"`
 	IsActive bool   `json:"IsActive"`
 }
-type ErrorResponseRegisterLogin struct {
-	ResponseCode int    `json:"responsecode"`
-	Message      string `json:"message"`
-}
 type UserCreateRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
 	Role     string `json:"role"`
+	Code     string `json:"code"`
 }
 type LoginRequest struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
+	Username string `json:"username" validate:"required"`
+	Password string `json:"password" validate:"required"`
 }
 type BudgetAmountResponse struct {
 	Amount int `json:"amount"`
@@ -53,28 +49,21 @@ func NewUserHandler(user datastore.User) *UserHandler {
 // @Produce json
 // @Param body body UserCreateRequest true "User registration details"
 // @Success 201 {object} UserResponse
-// @Failure 400 {object} ErrorResponseRegisterLogin
-// @Failure 422 {object} ErrorResponseRegisterLogin
-// @Failure 500 {object} ErrorResponseRegisterLogin
+// @Failure 422 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
 // @Router /users/register [post]
 func (a UserHandler) RegisterHandler(c echo.Context) error {
-	// Read Request Body
-	jsonBody := make(map[string]interface{})
-	err := json.NewDecoder(c.Request().Body).Decode(&jsonBody)
-	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: "Invalid JSON"})
-	}
-
-	//check json format
-	jsonFormatValidationMsg, jsonFormatErr := utils.ValidateJsonFormat(jsonBody, "username", "password")
-	if jsonFormatErr != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: jsonFormatValidationMsg})
+	var req UserCreateRequest
+	errs := utils.BindJSON(c.Request().Body, &req)
+	if errs.Has("body") {
+		return errs.Err()
 	}
 
 	//check unique User
-	userUniqueMsg, userUniqueErr := a.data_user.CheckUnique(jsonBody["username"].(string))
-	if userUniqueErr != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: userUniqueMsg})
+	if !errs.Has("username") {
+		if userUniqueMsg, userUniqueErr := a.data_user.CheckUnique(req.Username); userUniqueErr != nil {
+			errs.Add("username", consts.VALIDATION_TAKEN, userUniqueMsg)
+		}
 	}
 
 	conf, _ := config.NewConfig()
@@ -83,33 +72,30 @@ func (a UserHandler) RegisterHandler(c echo.Context) error {
 
 	//role
 	role := string(consts.ROLE_AIRLINE)
-	if r, ok := jsonBody["role"]; ok {
-		if r.(string) != string(consts.ROLE_ADMIN) && r.(string) != (consts.ROLE_EXPERT) {
-			return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: "Invalid Role"})
+	if req.Role != "" {
+		if req.Role != string(consts.ROLE_ADMIN) && req.Role != (consts.ROLE_EXPERT) {
+			errs.Add("role", consts.VALIDATION_INVALID, "Invalid Role")
 		} else {
-			role = r.(string)
+			role = req.Role
 		}
 	}
 	if role == string(consts.ROLE_EXPERT) || role == string(consts.ROLE_ADMIN) {
-		if code, ok := jsonBody["code"]; !ok {
-			return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: "You have to enter code!"})
-		} else {
-			if role == string(consts.ROLE_EXPERT) {
-				if code.(string) != expert_code {
-					return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: "WRONG code"})
-				}
-			} else {
-				if code.(string) != admin_code {
-					return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: "WRONG code"})
-				}
-			}
+		if req.Code == "" {
+			errs.Add("code", consts.VALIDATION_REQUIRED, "You have to enter code!")
+		} else if (role == string(consts.ROLE_EXPERT) && req.Code != expert_code) ||
+			(role == string(consts.ROLE_ADMIN) && req.Code != admin_code) {
+			errs.Add("code", consts.VALIDATION_INVALID, "WRONG code")
 		}
 	}
 
+	if len(errs) > 0 {
+		return errs.Err()
+	}
+
 	//create user
-	userCreationMsg, user, userCreationErr := a.data_user.Create(jsonBody["username"].(string), jsonBody["password"].(string), role)
+	userCreationMsg, user, userCreationErr := a.data_user.Create(req.Username, req.Password, role)
 	if userCreationErr != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: userCreationMsg})
+		return apperror.Internal(userCreationMsg)
 	}
 
 	return c.JSON(http.StatusOK, user)
@@ -123,27 +109,19 @@ func (a UserHandler) RegisterHandler(c echo.Context) error {
 // @Produce json
 // @Param body body LoginRequest true "Login request body"
 // @Success 200 {object} UserResponse
-// @Failure 400 {object} ErrorResponseRegisterLogin
-// @Failure 422 {object} ErrorResponseRegisterLogin
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 422 {object} models.ErrorResponse
 // @Router  /users/login [post]
 func (a UserHandler) LoginHandler(c echo.Context) error {
-	// Read Request Body
-	jsonBody := make(map[string]interface{})
-	err := json.NewDecoder(c.Request().Body).Decode(&jsonBody)
-	if err != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: "Invalid JSON"})
-	}
-
-	//check json format
-	jsonFormatValidationMsg, jsonFormatErr := utils.ValidateJsonFormat(jsonBody, "username", "password")
-	if jsonFormatErr != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: jsonFormatValidationMsg})
+	var req LoginRequest
+	if errs := utils.BindJSON(c.Request().Body, &req); len(errs) > 0 {
+		return errs.Err()
 	}
 
 	//find user based on username and check password correction
-	findUserMsg, user, findUserErr := a.data_user.Login(jsonBody["username"].(string), jsonBody["password"].(string))
+	findUserMsg, user, findUserErr := a.data_user.Login(req.Username, req.Password)
 	if findUserErr != nil {
-		return c.JSON(http.StatusUnprocessableEntity, models.Response{ResponseCode: 422, Message: findUserMsg})
+		return apperror.Unauthorized("invalid_credentials", findUserMsg)
 	}
 
 	return c.JSON(http.StatusOK, user)
@@ -0,0 +1,67 @@
+package middlewares
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/models"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	echo "github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+var statuses = map[apperror.Kind]int{
+	apperror.KindInternal:     http.StatusInternalServerError,
+	apperror.KindBadRequest:   http.StatusBadRequest,
+	apperror.KindUnauthorized: http.StatusUnauthorized,
+	apperror.KindForbidden:    http.StatusForbidden,
+	apperror.KindNotFound:     http.StatusNotFound,
+	apperror.KindConflict:     http.StatusConflict,
+	apperror.KindValidation:   http.StatusUnprocessableEntity,
+	apperror.KindUpstream:     http.StatusBadGateway,
+}
+
+// ErrorHandler renders every error returned by a handler in the same
+// envelope, the status comes from the kind of the domain error.
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status, body := resolve(err)
+	if status == http.StatusInternalServerError {
+		c.Logger().Error(err)
+	}
+
+	var respErr error
+	if c.Request().Method == http.MethodHead {
+		respErr = c.NoContent(status)
+	} else {
+		respErr = c.JSON(status, models.ErrorResponse{Error: body})
+	}
+	if respErr != nil {
+		c.Logger().Error(respErr)
+	}
+}
+
+func resolve(err error) (int, models.ErrorBody) {
+	var appErr *apperror.Error
+	if errors.As(err, &appErr) {
+		return statuses[appErr.Kind], models.ErrorBody{Code: appErr.Code, Message: appErr.Message, Details: appErr.Details}
+	}
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusNotFound, models.ErrorBody{Code: apperror.CodeNotFound, Message: "record not found"}
+	}
+
+	// routing, binding and auth errors of echo itself
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		code := strings.ReplaceAll(strings.ToLower(http.StatusText(httpErr.Code)), " ", "_")
+		return httpErr.Code, models.ErrorBody{Code: code, Message: fmt.Sprint(httpErr.Message)}
+	}
+
+	return http.StatusInternalServerError, models.ErrorBody{Code: apperror.CodeInternal, Message: "internal server error"}
+}
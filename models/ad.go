@@ -14,7 +14,11 @@ type Ad struct {
 	RepairCheck   bool   `gorm:"type:boolean"`
 	ExpertCheck   bool   `gorm:"type:boolean"`
 	PlaneAge      uint   `gorm:"type:uint"`
+	AirportCode   string `gorm:"type:varchar(4);index"`
+	ListingMode   string `gorm:"type:varchar(20);default:fixed"`
 	Category      Category
+	Attributes    []AdAttribute `gorm:"foreignKey:AdsID"`
+	Location      *Location     `gorm:"-" json:",omitempty"`
 }
 
 func (Ad) TableName() string {
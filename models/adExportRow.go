@@ -0,0 +1,45 @@
+package models
+
+import "strconv"
+
+type AdExportRow struct {
+	ID            uint   `json:"ID"`
+	UserID        uint   `json:"UserID"`
+	Image         string `json:"Image"`
+	Description   string `json:"Description"`
+	Subject       string `json:"Subject"`
+	Price         uint64 `json:"Price"`
+	CategoryName  string `json:"Category"`
+	Status        string `json:"Status"`
+	FlyTime       uint   `json:"FlyTime"`
+	AirplaneModel string `json:"AirplaneModel"`
+	RepairCheck   bool   `json:"RepairCheck"`
+	ExpertCheck   bool   `json:"ExpertCheck"`
+	PlaneAge      uint   `json:"PlaneAge"`
+}
+
+// CSV header of the ads export, in the same order as AdExportRow.CSVRecord
+func AdExportCSVHeader() []string {
+	return []string{
+		"ID", "UserID", "Image", "Description", "Subject", "Price", "Category",
+		"Status", "FlyTime", "AirplaneModel", "RepairCheck", "ExpertCheck", "PlaneAge",
+	}
+}
+
+func (r AdExportRow) CSVRecord() []string {
+	return []string{
+		strconv.FormatUint(uint64(r.ID), 10),
+		strconv.FormatUint(uint64(r.UserID), 10),
+		r.Image,
+		r.Description,
+		r.Subject,
+		strconv.FormatUint(r.Price, 10),
+		r.CategoryName,
+		r.Status,
+		strconv.FormatUint(uint64(r.FlyTime), 10),
+		r.AirplaneModel,
+		strconv.FormatBool(r.RepairCheck),
+		strconv.FormatBool(r.ExpertCheck),
+		strconv.FormatUint(uint64(r.PlaneAge), 10),
+	}
+}
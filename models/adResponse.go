@@ -14,4 +14,7 @@ type AdResponse struct {
 	RepairCheck   bool
 	ExpertCheck   bool
 	PlaneAge      uint
+	AirportCode   string            `json:",omitempty"`
+	Location      *Location         `json:",omitempty"`
+	Attributes    map[string]string `json:",omitempty"`
 }
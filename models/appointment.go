@@ -0,0 +1,64 @@
+package models
+
+import (
+	"Airplane-Divar/consts"
+	"time"
+)
+
+// ExpertSlot is a time an expert is available for an inspection at a place.
+// Booked tells whether an airline took it, it is only filled when listing.
+type ExpertSlot struct {
+	ID        uint      `gorm:"primary_key"`
+	ExpertID  uint      `gorm:"not null;index"`
+	StartsAt  time.Time `gorm:"not null"`
+	EndsAt    time.Time `gorm:"not null"`
+	Location  string    `gorm:"type:varchar(255);not null"`
+	CreatedAt time.Time `gorm:"not null"`
+	Booked    bool      `gorm:"->;-:migration"`
+}
+
+func (ExpertSlot) TableName() string {
+	return "expert_slots"
+}
+
+// Appointment is the inspection of an expert request in a slot. A request
+// and a slot have at most one scheduled appointment, cancelled ones are kept
+// so calendars can remove them, their slot is nil once the expert deleted
+// it. Sequence grows with every change, it is the
+// revision calendar clients compare.
+type Appointment struct {
+	ID          uint                     `gorm:"primary_key"`
+	ExpertAdsID uint                     `gorm:"not null;uniqueIndex:idx_appointment_request,where:status = 'Scheduled'"`
+	SlotID      *uint                    `gorm:"uniqueIndex:idx_appointment_slot,where:status = 'Scheduled'"`
+	AdsID       uint                     `gorm:"not null"`
+	Ads         Ad                       `gorm:"foreignKey:AdsID"`
+	ExpertID    uint                     `gorm:"not null;index"`
+	Expert      User                     `gorm:"foreignKey:ExpertID"`
+	UserID      uint                     `gorm:"not null;index"`
+	User        User                     `gorm:"foreignKey:UserID"`
+	StartsAt    time.Time                `gorm:"not null"`
+	EndsAt      time.Time                `gorm:"not null"`
+	Location    string                   `gorm:"type:varchar(255);not null"`
+	Status      consts.AppointmentStatus `gorm:"type:varchar(20);not null"`
+	Sequence    int                      `gorm:"not null;default:0"`
+	CancelledBy *uint                    `gorm:"type:bigint"`
+	CancelledAt *time.Time
+	CreatedAt   time.Time `gorm:"not null"`
+	UpdatedAt   time.Time `gorm:"not null"`
+}
+
+func (Appointment) TableName() string {
+	return "appointments"
+}
+
+// CalendarFeed is the secret link a user subscribes to in their calendar.
+// Only the hash of the token is kept, a user has one feed at a time.
+type CalendarFeed struct {
+	UserID    uint      `gorm:"primaryKey"`
+	TokenHash string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	CreatedAt time.Time `gorm:"not null"`
+}
+
+func (CalendarFeed) TableName() string {
+	return "calendar_feeds"
+}
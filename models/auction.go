@@ -0,0 +1,44 @@
+package models
+
+import (
+	"Airplane-Divar/consts"
+	"time"
+)
+
+// Auction is the auction listing of an ad. Version is bumped on every bid so
+// that concurrent bids on a stale read fail instead of both winning.
+type Auction struct {
+	ID               uint                 `gorm:"primary_key"`
+	AdsID            uint                 `gorm:"type:bigint;not null;uniqueIndex"`
+	SellerID         uint                 `gorm:"type:uint;not null"`
+	ReservePrice     uint64               `gorm:"type:uint;not null"`
+	MinIncrement     uint64               `gorm:"type:uint;not null"`
+	StartsAt         time.Time            `gorm:"type:timestamp;not null"`
+	EndsAt           time.Time            `gorm:"type:timestamp;not null"`
+	ExtensionSeconds uint                 `gorm:"type:uint;not null"`
+	HighestBid       uint64               `gorm:"type:uint;not null;default:0"`
+	HighestBidderID  *uint                `gorm:"type:uint"`
+	BidCount         uint                 `gorm:"type:uint;not null;default:0"`
+	Version          uint                 `gorm:"type:uint;not null;default:0"`
+	Status           consts.AuctionStatus `gorm:"type:varchar(20);not null"`
+	WinnerID         *uint                `gorm:"type:uint"`
+	ClosedAt         *time.Time           `gorm:"type:timestamp"`
+	CreatedAt        time.Time            `gorm:"default:current_timestamp"`
+	Ads              Ad
+}
+
+func (Auction) TableName() string {
+	return "auctions"
+}
+
+type Bid struct {
+	ID        uint      `gorm:"primary_key"`
+	AuctionID uint      `gorm:"type:bigint;not null;index"`
+	UserID    uint      `gorm:"type:uint;not null"`
+	Amount    uint64    `gorm:"type:uint;not null"`
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+}
+
+func (Bid) TableName() string {
+	return "bids"
+}
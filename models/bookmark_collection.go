@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// BookmarkCollection is a named shortlist of the bookmarks of a user.
+type BookmarkCollection struct {
+	ID        uint      `gorm:"primary_key"`
+	UserID    uint      `gorm:"type:uint;not null;uniqueIndex:idx_bookmark_collections_user_name"`
+	Name      string    `gorm:"type:varchar(100);not null;uniqueIndex:idx_bookmark_collections_user_name"`
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+
+	// Size is the number of bookmarks in the collection, it is not stored.
+	Size int `gorm:"-"`
+}
+
+func (BookmarkCollection) TableName() string {
+	return "bookmark_collections"
+}
+
+// BookmarkCollectionItem puts a bookmark in a collection, a bookmark can be
+// in any number of collections.
+type BookmarkCollectionItem struct {
+	CollectionID uint      `gorm:"primaryKey"`
+	AdsID        uint      `gorm:"primaryKey"`
+	UserID       uint      `gorm:"type:uint;not null;index"`
+	CreatedAt    time.Time `gorm:"default:current_timestamp"`
+}
+
+func (BookmarkCollectionItem) TableName() string {
+	return "bookmark_collection_items"
+}
+
+// BookmarkShare lets anyone with its token read a collection until it
+// expires or is revoked. Only the SHA-256 of the token is stored.
+type BookmarkShare struct {
+	ID           uint      `gorm:"primary_key"`
+	CollectionID uint      `gorm:"type:uint;not null;index"`
+	UserID       uint      `gorm:"type:uint;not null"`
+	TokenHash    string    `gorm:"type:varchar(64);not null;uniqueIndex"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	RevokedAt    *time.Time
+	CreatedAt    time.Time `gorm:"default:current_timestamp"`
+}
+
+func (BookmarkShare) TableName() string {
+	return "bookmark_shares"
+}
@@ -1,12 +1,30 @@
 package models
 
+import "time"
+
+// Bookmarks is an ad saved by a user. Note and Tags are private to the user,
+// Tags is kept comma separated. Muted bookmarks don't notify about changes
+// to their ad.
 type Bookmarks struct {
-	UserID uint `gorm:"primaryKey"`
-	AdsID  uint `gorm:"primaryKey"`
-	User   User
-	Ads    Ad
+	UserID       uint      `gorm:"primaryKey"`
+	AdsID        uint      `gorm:"primaryKey"`
+	Note         string    `gorm:"type:text;not null;default:''"`
+	Tags         string    `gorm:"type:text;not null;default:''"`
+	Muted        bool      `gorm:"not null;default:false"`
+	BookmarkedAt time.Time `gorm:"autoCreateTime"`
+	User         User
+	Ads          Ad
 }
 
 func (Bookmarks) TableName() string {
 	return "bookmarks"
 }
+
+// BookmarkListQuery narrows and orders a listing of bookmarks. Sort is
+// consts.BOOKMARK_SORT_DATE or consts.BOOKMARK_SORT_PRICE.
+type BookmarkListQuery struct {
+	CollectionID int
+	Page         int
+	Sort         string
+	Desc         bool
+}
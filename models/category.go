@@ -1,8 +1,11 @@
 package models
 
 type Category struct {
-	ID   uint   `gorm:"primaryKey"`
-	Name string `gorm:"unique;not null"`
+	ID       uint   `gorm:"primaryKey"`
+	Name     string `gorm:"unique;not null"`
+	Position int    `gorm:"not null;default:0"`
+	Retired  bool   `gorm:"not null;default:false"`
+	ParentID *uint
 }
 
 func (Category) TableName() string {
@@ -0,0 +1,27 @@
+package models
+
+// CategoryAttribute is an extra typed property that ads of a category and of
+// all its descendants can carry, e.g. cargo door dimensions for freighters.
+type CategoryAttribute struct {
+	ID         uint   `gorm:"primaryKey"`
+	CategoryID uint   `gorm:"not null;uniqueIndex:idx_category_attribute_name"`
+	Name       string `gorm:"type:varchar(100);not null;uniqueIndex:idx_category_attribute_name"`
+	Type       string `gorm:"type:varchar(20);not null"`
+	Unit       string `gorm:"type:varchar(20)"`
+	Required   bool   `gorm:"not null;default:false"`
+}
+
+func (CategoryAttribute) TableName() string {
+	return "category_attributes"
+}
+
+type AdAttribute struct {
+	AdsID       uint              `gorm:"primaryKey"`
+	AttributeID uint              `gorm:"primaryKey"`
+	Value       string            `gorm:"type:varchar(255);not null"`
+	Attribute   CategoryAttribute `gorm:"foreignKey:AttributeID"`
+}
+
+func (AdAttribute) TableName() string {
+	return "ad_attributes"
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Conversation is the message thread between a buyer and the seller of an ad.
+type Conversation struct {
+	ID            uint      `gorm:"primary_key"`
+	AdsID         uint      `gorm:"type:bigint;not null;uniqueIndex:idx_conversations_ads_buyer"`
+	BuyerID       uint      `gorm:"type:uint;not null;uniqueIndex:idx_conversations_ads_buyer"`
+	SellerID      uint      `gorm:"type:uint;not null"`
+	LastMessageAt time.Time `gorm:"type:timestamp"`
+	CreatedAt     time.Time `gorm:"default:current_timestamp"`
+
+	// messages of the other party the user has not read yet, filled by inbox queries
+	Unread int64 `gorm:"->;-:migration"`
+}
+
+func (Conversation) TableName() string {
+	return "conversations"
+}
+
+type Message struct {
+	ID             uint       `gorm:"primary_key"`
+	ConversationID uint       `gorm:"type:bigint;not null;index"`
+	SenderID       uint       `gorm:"type:uint;not null"`
+	Body           string     `gorm:"type:text"`
+	Attachment     string     `gorm:"type:varchar(255)"`
+	ReadAt         *time.Time `gorm:"type:timestamp"`
+	CreatedAt      time.Time  `gorm:"default:current_timestamp"`
+}
+
+func (Message) TableName() string {
+	return "messages"
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// AdDraft is an ad being written. Payload keeps the partial ad request as
+// JSON, it is only validated when the draft is submitted.
+type AdDraft struct {
+	ID        uint      `gorm:"primary_key"`
+	UserID    uint      `gorm:"type:uint;not null;index"`
+	Payload   string    `gorm:"type:text;not null"`
+	CreatedAt time.Time `gorm:"default:current_timestamp"`
+	UpdatedAt time.Time `gorm:"default:current_timestamp"`
+}
+
+func (AdDraft) TableName() string {
+	return "ad_drafts"
+}
@@ -8,6 +8,8 @@ import (
 type ExpertAds struct {
 	ID        uint          `gorm:"primary_key"`
 	Report    string        `gorm:"type:text"`
+	Grade     string        `gorm:"type:varchar(1)"`
+	Score     float64       `gorm:"default:0"`
 	Status    consts.Status `gorm:"type:status_type"`
 	CreatedAt time.Time     `gorm:"default:current_timestamp"`
 	Expert    User          `gorm:"foreignKey:ExpertID"`
@@ -16,6 +18,7 @@ type ExpertAds struct {
 	UserID    uint          `gorm:"type:uint;not null"`
 	User      User          `gorm:"foreignKey:UserID"`
 	Ads       Ad
+	Checklist []InspectionItem `gorm:"foreignKey:ExpertAdsID"`
 }
 
 func (ExpertAds) TableName() string {
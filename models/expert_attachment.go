@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// ExpertAttachment is a file backing an expert report, a photo or a scanned
+// document. TemplateItemID links it to the checklist item it is evidence
+// for, attachments without one are about the whole report.
+type ExpertAttachment struct {
+	ID             uint      `gorm:"primary_key"`
+	ExpertAdsID    uint      `gorm:"not null;index"`
+	TemplateItemID *uint     `gorm:"type:bigint"`
+	UploaderID     uint      `gorm:"not null"`
+	FileName       string    `gorm:"type:varchar(255);not null"`
+	ContentType    string    `gorm:"type:varchar(100);not null"`
+	Size           int64     `gorm:"not null"`
+	StorageKey     string    `gorm:"type:varchar(255);not null;uniqueIndex"`
+	CreatedAt      time.Time `gorm:"default:current_timestamp"`
+}
+
+func (ExpertAttachment) TableName() string {
+	return "expert_attachments"
+}
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+// ExpertCertificate is a signed copy of a finished expert report. Every time
+// an expert finishes a report a new revision is issued, Payload holds the
+// exact bytes that were signed.
+type ExpertCertificate struct {
+	ID          uint      `gorm:"primary_key"`
+	ExpertAdsID uint      `gorm:"not null;uniqueIndex:idx_expert_certificate_revision"`
+	Revision    int       `gorm:"not null;uniqueIndex:idx_expert_certificate_revision"`
+	Payload     string    `gorm:"type:text;not null"`
+	Signature   string    `gorm:"type:varchar(100);not null"`
+	KeyID       string    `gorm:"type:varchar(16);not null"`
+	IssuedAt    time.Time `gorm:"not null"`
+}
+
+func (ExpertCertificate) TableName() string {
+	return "expert_certificates"
+}
+
+// CertificatePayload is what a certificate says about the airplane. Its JSON
+// encoding is the canonical form that gets signed, so fields must only be
+// appended and slices must keep a stable order.
+type CertificatePayload struct {
+	RequestID uint                 `json:"request_id"`
+	Revision  int                  `json:"revision"`
+	Ad        CertificateAd        `json:"ad"`
+	Expert    CertificateExpert    `json:"expert"`
+	Report    string               `json:"report"`
+	Grade     string               `json:"grade"`
+	Score     float64              `json:"score"`
+	Findings  []CertificateFinding `json:"findings"`
+	IssuedAt  time.Time            `json:"issued_at"`
+}
+
+type CertificateAd struct {
+	ID            uint                   `json:"id"`
+	Subject       string                 `json:"subject"`
+	CategoryID    uint                   `json:"category_id"`
+	AirplaneModel string                 `json:"airplane_model"`
+	PlaneAge      uint                   `json:"plane_age"`
+	FlyTime       uint                   `json:"fly_time"`
+	Airframe      []CertificateAttribute `json:"airframe"`
+}
+
+// CertificateAttribute is an attribute of the ad, serial numbers and
+// registrations are kept there.
+type CertificateAttribute struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type CertificateExpert struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+type CertificateFinding struct {
+	Section  string `json:"section"`
+	Label    string `json:"label"`
+	Rating   int    `json:"rating"`
+	Severity string `json:"severity"`
+	Finding  string `json:"finding"`
+}
+
+// CertificateVerification is the result of checking a certificate. Revised
+// is set when a later revision replaced it or the report no longer says what
+// was signed.
+type CertificateVerification struct {
+	Certificate    ExpertCertificate
+	LatestRevision int
+	Valid          bool
+	Revised        bool
+	PublicKey      []byte
+}
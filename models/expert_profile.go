@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// ExpertProfile is what an expert declared about their work. Experts without
+// a profile are available and take requests of every category.
+type ExpertProfile struct {
+	UserID         uint `gorm:"primaryKey"`
+	Available      bool `gorm:"not null"`
+	LastAssignedAt *time.Time
+	Specialties    []ExpertSpecialty `gorm:"foreignKey:ExpertID;references:UserID"`
+}
+
+func (ExpertProfile) TableName() string {
+	return "expert_profiles"
+}
+
+// ExpertSpecialty is a category an expert checks ads of. Experts with
+// specialties are only assigned requests of those categories.
+type ExpertSpecialty struct {
+	ExpertID   uint `gorm:"primaryKey"`
+	CategoryID uint `gorm:"primaryKey"`
+}
+
+func (ExpertSpecialty) TableName() string {
+	return "expert_specialties"
+}
+
+// ExpertCandidate is an expert who can take an expert request.
+// ActiveRequests counts the requests assigned to them that are not done,
+// Specialist tells if the category of the request is one of their
+// specialties, Rating and Reviews sum up their visible reviews.
+type ExpertCandidate struct {
+	ExpertID       uint
+	ActiveRequests int
+	LastAssignedAt *time.Time
+	Specialist     bool
+	Rating         float64
+	Reviews        int
+}
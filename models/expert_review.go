@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// ExpertReview is how the airline that requested an expert check rated the
+// expert, once per request. Hidden reviews are left out of the expert's
+// profile and rating.
+type ExpertReview struct {
+	ID          uint   `gorm:"primary_key"`
+	ExpertAdsID uint   `gorm:"not null;uniqueIndex"`
+	ExpertID    uint   `gorm:"not null;index"`
+	UserID      uint   `gorm:"not null"`
+	User        User   `gorm:"foreignKey:UserID"`
+	Rating      int    `gorm:"not null"`
+	Comment     string `gorm:"type:text"`
+	Hidden      bool   `gorm:"not null;default:false"`
+	HiddenBy    *uint  `gorm:"type:bigint"`
+	HiddenAt    *time.Time
+	CreatedAt   time.Time `gorm:"not null"`
+}
+
+func (ExpertReview) TableName() string {
+	return "expert_reviews"
+}
+
+// ExpertRating sums up the visible reviews of an expert.
+type ExpertRating struct {
+	ExpertID uint
+	Average  float64
+	Reviews  int
+}
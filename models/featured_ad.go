@@ -0,0 +1,22 @@
+package models
+
+import (
+	"Airplane-Divar/consts"
+	"time"
+)
+
+type FeaturedAd struct {
+	ID        uint          `gorm:"primary_key"`
+	Status    consts.Status `gorm:"type:status_type"`
+	StartsAt  *time.Time    `gorm:"type:timestamp"`
+	EndsAt    *time.Time    `gorm:"type:timestamp"`
+	CreatedAt time.Time     `gorm:"default:current_timestamp"`
+	AdsID     uint          `gorm:"type:bigint;not null"`
+	UserID    uint          `gorm:"type:uint;not null"`
+	User      User          `gorm:"foreignKey:UserID"`
+	Ads       Ad
+}
+
+func (FeaturedAd) TableName() string {
+	return "featured_ads"
+}
@@ -0,0 +1,49 @@
+package models
+
+import "time"
+
+// InspectionTemplate is the checklist experts fill for the ads of a
+// category.
+type InspectionTemplate struct {
+	ID         uint                     `gorm:"primary_key"`
+	CategoryID uint                     `gorm:"not null;uniqueIndex"`
+	Name       string                   `gorm:"type:varchar(100);not null"`
+	CreatedAt  time.Time                `gorm:"default:current_timestamp"`
+	Items      []InspectionTemplateItem `gorm:"foreignKey:TemplateID"`
+}
+
+func (InspectionTemplate) TableName() string {
+	return "inspection_templates"
+}
+
+// InspectionTemplateItem is one thing to check, Section is one of
+// consts.INSPECTION_SECTIONS.
+type InspectionTemplateItem struct {
+	ID         uint   `gorm:"primary_key"`
+	TemplateID uint   `gorm:"not null;index"`
+	Section    string `gorm:"type:varchar(20);not null"`
+	Label      string `gorm:"type:varchar(255);not null"`
+	Position   int    `gorm:"not null;default:0"`
+}
+
+func (InspectionTemplateItem) TableName() string {
+	return "inspection_template_items"
+}
+
+// InspectionItem is how an expert found an item of the template on the
+// inspected airplane. Section and Label are copied from the template so a
+// changed template doesn't rewrite finished reports.
+type InspectionItem struct {
+	ExpertAdsID    uint   `gorm:"primaryKey"`
+	TemplateItemID uint   `gorm:"primaryKey"`
+	Section        string `gorm:"type:varchar(20);not null"`
+	Label          string `gorm:"type:varchar(255);not null"`
+	Position       int    `gorm:"not null;default:0"`
+	Rating         int    `gorm:"not null"`
+	Finding        string `gorm:"type:text"`
+	Severity       string `gorm:"type:varchar(20);not null"`
+}
+
+func (InspectionItem) TableName() string {
+	return "inspection_items"
+}
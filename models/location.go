@@ -0,0 +1,10 @@
+package models
+
+// Location is where an aircraft is parked, resolved from the ad's airport code.
+type Location struct {
+	AirportCode string  `json:"airport_code"`
+	AirportName string  `json:"airport_name"`
+	Country     string  `json:"country"`
+	Lat         float64 `json:"lat"`
+	Lon         float64 `json:"lon"`
+}
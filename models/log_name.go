@@ -20,6 +20,32 @@ log list:
 	11. payment_failed
 	12. bookmark
 	13. bookmark_remove
+	14. featured_request
+	15. featured_activated
+	16. offer_created
+	17. offer_countered
+	18. offer_accepted
+	19. offer_rejected
+	20. ad_sold
+	21. auction_created
+	22. auction_bid
+	23. auction_closed
+	24. ad_reported
+	25. ad_auto_hidden
+	26. report_dismissed
+	27. report_upheld
+	28. bookmark_share_created
+	29. bookmark_share_revoked
+	30. bookmark_share_viewed
+	31. expert_assigned
+	32. expert_attachment_added
+	33. expert_attachment_deleted
+	34. sla_escalated
+	35. expert_reviewed
+	36. expert_review_hidden
+	37. appointment_scheduled
+	38. appointment_rescheduled
+	39. appointment_cancelled
 */
 
 func (LogName) TableName() string {
@@ -41,6 +67,32 @@ func LogsList() []LogName {
 		{ID: 11, Title: "payment_failed"},
 		{ID: 12, Title: "bookmark"},
 		{ID: 13, Title: "bookmark_remove"},
+		{ID: 14, Title: "featured_request"},
+		{ID: 15, Title: "featured_activated"},
+		{ID: 16, Title: "offer_created"},
+		{ID: 17, Title: "offer_countered"},
+		{ID: 18, Title: "offer_accepted"},
+		{ID: 19, Title: "offer_rejected"},
+		{ID: 20, Title: "ad_sold"},
+		{ID: 21, Title: "auction_created"},
+		{ID: 22, Title: "auction_bid"},
+		{ID: 23, Title: "auction_closed"},
+		{ID: 24, Title: "ad_reported"},
+		{ID: 25, Title: "ad_auto_hidden"},
+		{ID: 26, Title: "report_dismissed"},
+		{ID: 27, Title: "report_upheld"},
+		{ID: 28, Title: "bookmark_share_created"},
+		{ID: 29, Title: "bookmark_share_revoked"},
+		{ID: 30, Title: "bookmark_share_viewed"},
+		{ID: 31, Title: "expert_assigned"},
+		{ID: 32, Title: "expert_attachment_added"},
+		{ID: 33, Title: "expert_attachment_deleted"},
+		{ID: 34, Title: "sla_escalated"},
+		{ID: 35, Title: "expert_reviewed"},
+		{ID: 36, Title: "expert_review_hidden"},
+		{ID: 37, Title: "appointment_scheduled"},
+		{ID: 38, Title: "appointment_rescheduled"},
+		{ID: 39, Title: "appointment_cancelled"},
 	}
 	return logs
 }
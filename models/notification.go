@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Notification is an in-app message to a user about one of their ads or deals.
+type Notification struct {
+	ID        uint       `gorm:"primary_key"`
+	UserID    uint       `gorm:"type:uint;not null;index"`
+	AdsID     uint       `gorm:"type:bigint"`
+	Kind      string     `gorm:"type:varchar(50);not null"`
+	Message   string     `gorm:"type:text"`
+	ReadAt    *time.Time `gorm:"type:timestamp"`
+	CreatedAt time.Time  `gorm:"default:current_timestamp"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"Airplane-Divar/consts"
+	"time"
+)
+
+// Offer is one step of a price negotiation between a buyer and the seller
+// of an ad. A counter offer points to the offer it answers through ParentID.
+type Offer struct {
+	ID         uint               `gorm:"primary_key"`
+	AdsID      uint               `gorm:"type:bigint;not null"`
+	BuyerID    uint               `gorm:"type:uint;not null"`
+	SellerID   uint               `gorm:"type:uint;not null"`
+	ProposedBy uint               `gorm:"type:uint;not null"`
+	ParentID   *uint              `gorm:"type:bigint"`
+	Price      uint64             `gorm:"type:uint;not null"`
+	Message    string             `gorm:"type:text"`
+	Status     consts.OfferStatus `gorm:"type:varchar(20);not null"`
+	ExpiresAt  time.Time          `gorm:"type:timestamp;not null"`
+	CreatedAt  time.Time          `gorm:"default:current_timestamp"`
+	UpdatedAt  time.Time
+	Ads        Ad
+}
+
+func (Offer) TableName() string {
+	return "offers"
+}
@@ -8,10 +8,11 @@ import (
 type RepairRequest struct {
 	ID        uint          `gorm:"primary_key"`
 	Status    consts.Status `gorm:"type:status_type"`
-	CreatedAt time.Time     `gorm:"default:CURRENT_TIMESTAMP()"`
+	CreatedAt time.Time     `gorm:"default:current_timestamp"`
 	AdsID     uint          `gorm:"type:bigint;not null"`
 	UserID    uint          `gorm:"type:uint;not null"`
 	User      User          `gorm:"foreignKey:UserID"`
+	MatinID   uint          `gorm:"type:bigint"`
 	Ads       Ad
 }
 
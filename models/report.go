@@ -0,0 +1,23 @@
+package models
+
+import (
+	"Airplane-Divar/consts"
+	"time"
+)
+
+// Report is an abuse report of a user on an ad.
+type Report struct {
+	ID         uint                `gorm:"primary_key"`
+	AdsID      uint                `gorm:"type:bigint;not null;uniqueIndex:idx_reports_ads_user"`
+	UserID     uint                `gorm:"type:uint;not null;uniqueIndex:idx_reports_ads_user"`
+	Reason     string              `gorm:"type:varchar(30);not null"`
+	Note       string              `gorm:"type:text"`
+	Status     consts.ReportStatus `gorm:"type:varchar(20);not null"`
+	ResolvedBy *uint               `gorm:"type:uint"`
+	ResolvedAt *time.Time          `gorm:"type:timestamp"`
+	CreatedAt  time.Time           `gorm:"default:current_timestamp"`
+}
+
+func (Report) TableName() string {
+	return "reports"
+}
@@ -2,8 +2,25 @@ package models
 
 import (
 	"Airplane-Divar/consts"
+	"time"
 )
 
+type AdRequest struct {
+	Image         string `json:"Image"`
+	Description   string `json:"Description"`
+	Subject       string `json:"Subject"`
+	Price         uint64 `json:"Price" validate:"required"`
+	Category      string `json:"Category" validate:"required"`
+	FlyTime       uint   `json:"FlyTime" validate:"required"`
+	AirplaneModel string `json:"AirplaneModel" validate:"required"`
+	RepairCheck   bool   `json:"RepairCheck" validate:"required"`
+	ExpertCheck   bool   `json:"ExpertCheck" validate:"required"`
+	PlaneAge      uint   `json:"PlaneAge" validate:"required"`
+	AirportCode   string `json:"AirportCode" example:"OIII"`
+
+	Attributes map[string]interface{} `json:"Attributes"`
+}
+
 type UpdateExpertCheckRequest struct {
 	Status consts.Status `json:"status"`
 	Report string        `json:"report"`
@@ -16,3 +33,124 @@ type UpdateRepairRequest struct {
 type UpdateAdsStatusRequest struct {
 	Status consts.AdStatus `json:"status"`
 }
+
+type CreateCategoryRequest struct {
+	Name     string `json:"name"`
+	Position int    `json:"position"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+type MoveCategoryRequest struct {
+	ParentID *uint `json:"parent_id"`
+}
+
+type CreateCategoryAttributeRequest struct {
+	Name     string `json:"name"`
+	Type     string `json:"type" enums:"string,number,boolean"`
+	Unit     string `json:"unit"`
+	Required bool   `json:"required"`
+}
+
+type OfferRequest struct {
+	Price     uint64     `json:"price"`
+	Message   string     `json:"message"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type CreateAuctionRequest struct {
+	ReservePrice     uint64     `json:"reserve_price"`
+	MinIncrement     uint64     `json:"min_increment"`
+	StartsAt         *time.Time `json:"starts_at"`
+	EndsAt           time.Time  `json:"ends_at"`
+	ExtensionSeconds *uint      `json:"extension_seconds"`
+}
+
+type BidRequest struct {
+	Amount uint64 `json:"amount"`
+}
+
+type SendMessageRequest struct {
+	Body       string `json:"body"`
+	Attachment string `json:"attachment" example:"https://snipboard.io/d5viVR.jpg"`
+}
+
+type ReportAdRequest struct {
+	Reason string `json:"reason" enums:"fraud,duplicate,wrong_category,offensive"`
+	Note   string `json:"note"`
+}
+
+type ResolveReportRequest struct {
+	Outcome consts.ReportStatus `json:"outcome" enums:"Dismissed,Upheld"`
+	Note    string              `json:"note"`
+}
+
+type RenameCategoryRequest struct {
+	Name string `json:"name"`
+}
+
+type ReorderCategoriesRequest struct {
+	IDs []uint `json:"ids"`
+}
+
+type BookmarkCollectionRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type BookmarkNoteRequest struct {
+	Note string   `json:"note"`
+	Tags []string `json:"tags"`
+}
+
+type BookmarkShareRequest struct {
+	ExpiresInHours int `json:"expires_in_hours" example:"168"`
+}
+
+type ExpertProfileRequest struct {
+	Available   *bool  `json:"available"`
+	Specialties []uint `json:"specialties"`
+}
+
+type AssignExpertRequest struct {
+	ExpertID uint `json:"expert_id"`
+}
+
+type ExpertReviewRequest struct {
+	Rating  int    `json:"rating" minimum:"1" maximum:"5"`
+	Comment string `json:"comment"`
+}
+
+type HideReviewRequest struct {
+	Hidden *bool `json:"hidden"`
+}
+
+type ExpertSlotRequest struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Location string    `json:"location"`
+}
+
+type AppointmentRequest struct {
+	SlotID uint `json:"slot_id"`
+}
+
+type InspectionTemplateRequest struct {
+	CategoryID uint                            `json:"category_id"`
+	Name       string                          `json:"name"`
+	Items      []InspectionTemplateItemRequest `json:"items"`
+}
+
+type InspectionTemplateItemRequest struct {
+	Section string `json:"section" enums:"airframe,engines,landing_gear,avionics,cabin,records"`
+	Label   string `json:"label"`
+}
+
+type InspectionChecklistRequest struct {
+	Items []InspectionItemRequest `json:"items"`
+}
+
+type InspectionItemRequest struct {
+	ItemID   uint   `json:"item_id"`
+	Rating   int    `json:"rating" minimum:"1" maximum:"5"`
+	Finding  string `json:"finding"`
+	Severity string `json:"severity" enums:"none,minor,major,critical"`
+}
@@ -9,6 +9,13 @@ type Response struct {
 	Message      string `json:"message"`
 }
 
+// FieldError is one problem of a request body.
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
 type MessageResponse struct {
 	Message string `json:"message"`
 }
@@ -17,18 +24,55 @@ type SuccessResponse struct {
 	Success bool `json:"success"`
 }
 
+// ErrorResponse is the envelope of every error of the API.
 type ErrorResponse struct {
-	Error string
+	Error ErrorBody `json:"error"`
+}
+
+type ErrorBody struct {
+	Code    string       `json:"code" example:"ad_not_found"`
+	Message string       `json:"message" example:"ad not found"`
+	Details []FieldError `json:"details,omitempty"`
 }
 
 type GetExpertRequestResponse struct {
-	ID        int       `json:"id"`
-	UserID    int       `json:"userID"`
-	ExpertID  int       `json:"expertID"`
-	AdSubject string    `json:"adSubject"`
-	Status    string    `json:"status"`
-	Report    string    `json:"report"`
-	CreatedAt time.Time `json:"createdAt"`
+	ID        int                         `json:"id"`
+	UserID    int                         `json:"userID"`
+	ExpertID  int                         `json:"expertID"`
+	AdSubject string                      `json:"adSubject"`
+	Status    string                      `json:"status"`
+	Report    string                      `json:"report"`
+	Grade     string                      `json:"grade,omitempty"`
+	Score     float64                     `json:"score,omitempty"`
+	Checklist []InspectionSectionResponse `json:"checklist,omitempty"`
+	CreatedAt time.Time                   `json:"createdAt"`
+}
+
+type InspectionTemplateResponse struct {
+	ID         uint                             `json:"id"`
+	CategoryID uint                             `json:"categoryID"`
+	Name       string                           `json:"name"`
+	Items      []InspectionTemplateItemResponse `json:"items"`
+}
+
+type InspectionTemplateItemResponse struct {
+	ID      uint   `json:"id"`
+	Section string `json:"section"`
+	Label   string `json:"label"`
+}
+
+// InspectionSectionResponse is a section of a filled checklist.
+type InspectionSectionResponse struct {
+	Section string                   `json:"section"`
+	Items   []InspectionItemResponse `json:"items"`
+}
+
+type InspectionItemResponse struct {
+	ItemID   uint   `json:"itemID"`
+	Label    string `json:"label"`
+	Rating   int    `json:"rating"`
+	Finding  string `json:"finding,omitempty"`
+	Severity string `json:"severity"`
 }
 
 type ExpertRequestResponse struct {
@@ -41,6 +85,98 @@ type ExpertRequestResponse struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type ExpertAttachmentResponse struct {
+	ID          uint      `json:"id"`
+	ItemID      *uint     `json:"itemID,omitempty"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// ExpertCertificateResponse lets anyone check a certificate, Payload holds
+// the exact bytes that were signed.
+type ExpertCertificateResponse struct {
+	RequestID      uint                `json:"requestID"`
+	Revision       int                 `json:"revision"`
+	LatestRevision int                 `json:"latestRevision"`
+	Valid          bool                `json:"valid"`
+	Revised        bool                `json:"revised"`
+	IssuedAt       time.Time           `json:"issuedAt"`
+	Certificate    *CertificatePayload `json:"certificate,omitempty"`
+	Payload        string              `json:"payload"`
+	Signature      string              `json:"signature"`
+	Code           string              `json:"code"`
+	KeyID          string              `json:"keyID"`
+	PublicKey      string              `json:"publicKey"`
+}
+
+type ExpertProfileResponse struct {
+	ExpertID       uint       `json:"expertID"`
+	Available      bool       `json:"available"`
+	Specialties    []uint     `json:"specialties"`
+	LastAssignedAt *time.Time `json:"lastAssignedAt,omitempty"`
+}
+
+type ExpertReviewResponse struct {
+	ID        uint       `json:"id"`
+	RequestID uint       `json:"requestID"`
+	ExpertID  uint       `json:"expertID"`
+	UserID    uint       `json:"userID"`
+	Username  string     `json:"username"`
+	Rating    int        `json:"rating"`
+	Comment   string     `json:"comment"`
+	Hidden    bool       `json:"hidden"`
+	HiddenAt  *time.Time `json:"hiddenAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// ExpertPublicProfileResponse is what anyone can see about an expert, the
+// rating only counts the reviews that aren't hidden.
+type ExpertPublicProfileResponse struct {
+	ExpertID    uint                   `json:"expertID"`
+	Username    string                 `json:"username"`
+	Available   bool                   `json:"available"`
+	Specialties []uint                 `json:"specialties"`
+	Rating      float64                `json:"rating"`
+	Reviews     int                    `json:"reviews"`
+	Latest      []ExpertReviewResponse `json:"latest"`
+}
+
+type ExpertSlotResponse struct {
+	ID       uint      `json:"id"`
+	ExpertID uint      `json:"expertID"`
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+	Location string    `json:"location"`
+	Booked   bool      `json:"booked"`
+}
+
+type AppointmentResponse struct {
+	ID          uint       `json:"id"`
+	RequestID   uint       `json:"requestID"`
+	SlotID      *uint      `json:"slotID"`
+	AdID        uint       `json:"adID"`
+	AdSubject   string     `json:"adSubject"`
+	ExpertID    uint       `json:"expertID"`
+	ExpertName  string     `json:"expertName"`
+	UserID      uint       `json:"userID"`
+	StartsAt    time.Time  `json:"startsAt"`
+	EndsAt      time.Time  `json:"endsAt"`
+	Location    string     `json:"location"`
+	Status      string     `json:"status"`
+	CancelledBy *uint      `json:"cancelledBy,omitempty"`
+	CancelledAt *time.Time `json:"cancelledAt,omitempty"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	UpdatedAt   time.Time  `json:"updatedAt"`
+}
+
+// CalendarFeedResponse is the link to subscribe to in a calendar, it is only
+// shown when the feed is created.
+type CalendarFeedResponse struct {
+	URL string `json:"url"`
+}
+
 type GetRepairRequestResponse struct {
 	ID        int       `json:"id"`
 	UserID    int       `json:"userID"`
@@ -57,6 +193,208 @@ type RepairRequestResponse struct {
 	CreatedAt time.Time `json:"createdAt"`
 }
 
+type CategoryResponse struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	ParentID *uint  `json:"parent_id"`
+	Position int    `json:"position"`
+	Retired  bool   `json:"retired"`
+}
+
+type CategoryAttributeResponse struct {
+	ID         uint   `json:"id"`
+	CategoryID uint   `json:"category_id"`
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Unit       string `json:"unit"`
+	Required   bool   `json:"required"`
+}
+
+type FeaturedAdResponse struct {
+	ID        int        `json:"id"`
+	AdID      int        `json:"adID"`
+	Status    string     `json:"status"`
+	StartsAt  *time.Time `json:"startsAt"`
+	EndsAt    *time.Time `json:"endsAt"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type OfferResponse struct {
+	ID         uint      `json:"id"`
+	AdID       uint      `json:"adID"`
+	BuyerID    uint      `json:"buyerID"`
+	SellerID   uint      `json:"sellerID"`
+	ProposedBy uint      `json:"proposedBy"`
+	ParentID   *uint     `json:"parentID"`
+	Price      uint64    `json:"price"`
+	Message    string    `json:"message"`
+	Status     string    `json:"status"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+type AuctionResponse struct {
+	ID               uint      `json:"id"`
+	AdID             uint      `json:"adID"`
+	MinIncrement     uint64    `json:"minIncrement"`
+	StartsAt         time.Time `json:"startsAt"`
+	EndsAt           time.Time `json:"endsAt"`
+	ExtensionSeconds uint      `json:"extensionSeconds"`
+	HighestBid       uint64    `json:"highestBid"`
+	BidCount         uint      `json:"bidCount"`
+	MinimumBid       uint64    `json:"minimumBid"`
+	ReserveMet       bool      `json:"reserveMet"`
+	Status           string    `json:"status"`
+	WinnerID         *uint     `json:"winnerID"`
+}
+
+type BidResponse struct {
+	ID        uint      `json:"id"`
+	UserID    uint      `json:"userID"`
+	Amount    uint64    `json:"amount"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type NotificationResponse struct {
+	ID        uint      `json:"id"`
+	AdID      uint      `json:"adID"`
+	Kind      string    `json:"kind"`
+	Message   string    `json:"message"`
+	Read      bool      `json:"read"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type ConversationResponse struct {
+	ID            uint      `json:"id"`
+	AdID          uint      `json:"adID"`
+	BuyerID       uint      `json:"buyerID"`
+	SellerID      uint      `json:"sellerID"`
+	LastMessageAt time.Time `json:"lastMessageAt"`
+	Unread        int64     `json:"unread"`
+}
+
+type InboxResponse struct {
+	Unread        int64                  `json:"unread"`
+	Conversations []ConversationResponse `json:"conversations"`
+}
+
+type ChatMessageResponse struct {
+	ID             uint       `json:"id"`
+	ConversationID uint       `json:"conversationID"`
+	SenderID       uint       `json:"senderID"`
+	Body           string     `json:"body"`
+	Attachment     string     `json:"attachment,omitempty"`
+	ReadAt         *time.Time `json:"readAt"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+type AdDraftResponse struct {
+	ID        uint                   `json:"id"`
+	Payload   map[string]interface{} `json:"payload"`
+	CreatedAt time.Time              `json:"createdAt"`
+	UpdatedAt time.Time              `json:"updatedAt"`
+}
+
+type ReportResponse struct {
+	ID         uint       `json:"id"`
+	AdID       uint       `json:"adID"`
+	UserID     uint       `json:"userID"`
+	Reason     string     `json:"reason"`
+	Note       string     `json:"note"`
+	Status     string     `json:"status"`
+	ResolvedBy *uint      `json:"resolvedBy"`
+	ResolvedAt *time.Time `json:"resolvedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+// SLAPeriodResponse is a request in a status with an SLA. OverdueHours is
+// how long it has been past due, 0 when it isn't.
+type SLAPeriodResponse struct {
+	ID           uint       `json:"id"`
+	Kind         string     `json:"kind"`
+	RequestID    uint       `json:"requestID"`
+	AdID         uint       `json:"adID"`
+	Status       string     `json:"status"`
+	AssigneeID   *uint      `json:"assigneeID"`
+	StartedAt    time.Time  `json:"startedAt"`
+	DueAt        *time.Time `json:"dueAt"`
+	EscalatedAt  *time.Time `json:"escalatedAt"`
+	OverdueHours float64    `json:"overdueHours"`
+}
+
+// SLAComplianceResponse is how one expert or repair provider kept the SLA of
+// one status. ComplianceRate only counts the periods that met or breached it,
+// not the open ones that still have time.
+type SLAComplianceResponse struct {
+	AssigneeID     *uint   `json:"assigneeID"`
+	Status         string  `json:"status"`
+	Total          int     `json:"total"`
+	Met            int     `json:"met"`
+	Breached       int     `json:"breached"`
+	Open           int     `json:"open"`
+	ComplianceRate float64 `json:"complianceRate"`
+	AverageHours   float64 `json:"averageHours"`
+}
+
+// BookmarkedAdResponse is a bookmarked ad with what the user noted about it.
+// Availability tells whether the ad can still be bought, ads that were
+// removed only keep their ID.
+type BookmarkedAdResponse struct {
+	AdResponse
+	Note         string
+	Tags         []string
+	Collections  []uint
+	BookmarkedAt time.Time `json:"bookmarked_at"`
+	Availability string    `json:"availability"`
+}
+
+type BookmarkCollectionResponse struct {
+	ID        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Size      int       `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type BookmarkNoteResponse struct {
+	AdID uint     `json:"adID"`
+	Note string   `json:"note"`
+	Tags []string `json:"tags"`
+}
+
+// BookmarkShareResponse is a share of a collection, Token is only known
+// when the share is created.
+type BookmarkShareResponse struct {
+	ID           uint       `json:"id"`
+	CollectionID uint       `json:"collectionID"`
+	Token        string     `json:"token,omitempty"`
+	ExpiresAt    time.Time  `json:"expiresAt"`
+	RevokedAt    *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt    time.Time  `json:"createdAt"`
+}
+
+// SharedAdResponse is an ad of a shared collection, without anything about
+// the seller or what the owner of the collection noted.
+type SharedAdResponse struct {
+	ID            uint   `json:"id"`
+	Image         string `json:"image,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Subject       string `json:"subject,omitempty"`
+	Price         uint64 `json:"price,omitempty"`
+	CategoryID    uint   `json:"categoryID,omitempty"`
+	FlyTime       uint   `json:"flyTime,omitempty"`
+	AirplaneModel string `json:"airplaneModel,omitempty"`
+	RepairCheck   bool   `json:"repairCheck"`
+	ExpertCheck   bool   `json:"expertCheck"`
+	PlaneAge      uint   `json:"planeAge,omitempty"`
+	Availability  string `json:"availability"`
+}
+
+type SharedBookmarksResponse struct {
+	Name      string             `json:"name"`
+	ExpiresAt time.Time          `json:"expiresAt"`
+	Ads       []SharedAdResponse `json:"ads"`
+}
+
 type ActivityLogResponse struct {
 	ID          uint      `json:"ID"`
 	CreatedAt   time.Time `json:"LoggedAt"`
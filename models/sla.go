@@ -0,0 +1,51 @@
+package models
+
+import (
+	"Airplane-Divar/consts"
+	"time"
+)
+
+// SLAPeriod is the time a request spent in one status. DueAt is when the
+// status should have been left by, nil when the status has no SLA, and
+// EndedAt is nil while the request is still in it.
+type SLAPeriod struct {
+	ID          uint          `gorm:"primary_key"`
+	Kind        string        `gorm:"type:varchar(10);not null;index:idx_sla_period_request"`
+	RequestID   uint          `gorm:"not null;index:idx_sla_period_request"`
+	AdsID       uint          `gorm:"not null"`
+	Status      consts.Status `gorm:"type:varchar(50);not null"`
+	AssigneeID  *uint         `gorm:"type:bigint"`
+	StartedAt   time.Time     `gorm:"not null"`
+	DueAt       *time.Time
+	EndedAt     *time.Time
+	EscalatedAt *time.Time
+}
+
+func (SLAPeriod) TableName() string {
+	return "sla_periods"
+}
+
+// Breached tells whether the period ran past its due date, open periods
+// are measured up to now.
+func (p SLAPeriod) Breached(now time.Time) bool {
+	if p.DueAt == nil {
+		return false
+	}
+	end := now
+	if p.EndedAt != nil {
+		end = *p.EndedAt
+	}
+	return end.After(*p.DueAt)
+}
+
+// SLACompliance sums up the periods of one assignee in one status, a nil
+// AssigneeID stands for requests nobody had taken yet.
+type SLACompliance struct {
+	AssigneeID   *uint
+	Status       consts.Status
+	Total        int
+	Met          int
+	Breached     int
+	Open         int
+	AverageHours float64
+}
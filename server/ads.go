@@ -11,5 +11,15 @@ func adsRoutes(e *echo.Echo, handler *ads.AdsHandler) {
 	e.POST("/ads/add", handler.AddAdHandler, middlewares.IsLoggedIn)
 	e.GET("/ads/:id", handler.Get, middlewares.IsLoggedIn)
 	e.GET("/ads", handler.List, middlewares.IsLoggedIn)
+	e.GET("/ads/export", handler.Export, middlewares.IsLoggedIn)
 	e.PUT("/ads/:id/status", handler.Status, middlewares.IsLoggedIn)
 }
+
+func draftsRoutes(e *echo.Echo, handler *ads.DraftsHandler) {
+	e.POST("/ads/drafts", handler.Create, middlewares.IsLoggedIn)
+	e.GET("/ads/drafts", handler.List, middlewares.IsLoggedIn)
+	e.GET("/ads/drafts/:id", handler.Get, middlewares.IsLoggedIn)
+	e.PUT("/ads/drafts/:id", handler.Save, middlewares.IsLoggedIn)
+	e.DELETE("/ads/drafts/:id", handler.Delete, middlewares.IsLoggedIn)
+	e.POST("/ads/drafts/:id/submit", handler.Submit, middlewares.IsLoggedIn)
+}
@@ -0,0 +1,20 @@
+package server
+
+import (
+	"Airplane-Divar/datastore/auction"
+	handlers "Airplane-Divar/handlers/auction"
+	"Airplane-Divar/middlewares"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func auctionRoutes(e *echo.Echo, db *gorm.DB) {
+	auctionDS := auction.NewAuctionStorer(db)
+	auctionHandler := handlers.NewAuctionHandler(auctionDS)
+
+	e.POST("/ads/:id/auction", auctionHandler.CreateAuction, middlewares.IsLoggedIn)
+	e.GET("/ads/:id/auction", auctionHandler.GetAuction, middlewares.IsLoggedIn)
+	e.GET("/auctions/:id/bids", auctionHandler.ListBids, middlewares.IsLoggedIn)
+	e.POST("/auctions/:id/bids", auctionHandler.PlaceBid, middlewares.IsLoggedIn)
+}
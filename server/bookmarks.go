@@ -12,3 +12,25 @@ func bookmarksRoutes(e *echo.Echo, handler *bookmarks.BookmarkssHandler) {
 	e.GET("/bookmarks/list", handler.ListBookmarks, middlewares.IsLoggedIn)
 	e.PUT("/bookmarks/add/:id", handler.AddBookmark, middlewares.IsLoggedIn)
 }
+
+func collectionsRoutes(e *echo.Echo, handler *bookmarks.CollectionsHandler) {
+	e.POST("/bookmarks/collections", handler.Create, middlewares.IsLoggedIn)
+	e.GET("/bookmarks/collections", handler.List, middlewares.IsLoggedIn)
+	e.PUT("/bookmarks/collections/:id", handler.Rename, middlewares.IsLoggedIn)
+	e.DELETE("/bookmarks/collections/:id", handler.Delete, middlewares.IsLoggedIn)
+	e.PUT("/bookmarks/collections/:id/ads/:adID", handler.AddBookmark, middlewares.IsLoggedIn)
+	e.DELETE("/bookmarks/collections/:id/ads/:adID", handler.RemoveBookmark, middlewares.IsLoggedIn)
+	e.PUT("/bookmarks/note/:id", handler.Annotate, middlewares.IsLoggedIn)
+}
+
+func watchRoutes(e *echo.Echo, handler *bookmarks.WatchHandler) {
+	e.PUT("/bookmarks/mute/:id", handler.Mute, middlewares.IsLoggedIn)
+	e.DELETE("/bookmarks/mute/:id", handler.Unmute, middlewares.IsLoggedIn)
+}
+
+func sharesRoutes(e *echo.Echo, handler *bookmarks.SharesHandler) {
+	e.POST("/bookmarks/collections/:id/shares", handler.Create, middlewares.IsLoggedIn)
+	e.GET("/bookmarks/collections/:id/shares", handler.List, middlewares.IsLoggedIn)
+	e.DELETE("/bookmarks/shares/:id", handler.Revoke, middlewares.IsLoggedIn)
+	e.GET("/shared/bookmarks/:token", handler.View)
+}
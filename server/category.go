@@ -0,0 +1,26 @@
+package server
+
+import (
+	"Airplane-Divar/datastore/category"
+	handlers "Airplane-Divar/handlers/category"
+	"Airplane-Divar/middlewares"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func categoryRoutes(e *echo.Echo, db *gorm.DB) {
+	categoryDS := category.NewCategoryStorer(db)
+	categoryHandler := handlers.NewCategoryHandler(categoryDS)
+
+	e.GET("/categories", categoryHandler.ListCategories)
+	e.GET("/categories/:id/attributes", categoryHandler.ListAttributes)
+	e.GET("/admin/categories", categoryHandler.AdminListCategories, middlewares.IsLoggedIn)
+	e.POST("/admin/categories", categoryHandler.CreateCategory, middlewares.IsLoggedIn)
+	e.PUT("/admin/categories/order", categoryHandler.ReorderCategories, middlewares.IsLoggedIn)
+	e.PUT("/admin/categories/:id", categoryHandler.RenameCategory, middlewares.IsLoggedIn)
+	e.DELETE("/admin/categories/:id", categoryHandler.RetireCategory, middlewares.IsLoggedIn)
+	e.PUT("/admin/categories/:id/parent", categoryHandler.MoveCategory, middlewares.IsLoggedIn)
+	e.POST("/admin/categories/:id/attributes", categoryHandler.CreateAttribute, middlewares.IsLoggedIn)
+	e.DELETE("/admin/categories/:id/attributes/:attributeID", categoryHandler.DeleteAttribute, middlewares.IsLoggedIn)
+}
@@ -0,0 +1,22 @@
+package server
+
+import (
+	"Airplane-Divar/datastore/conversation"
+	handlers "Airplane-Divar/handlers/conversation"
+	"Airplane-Divar/middlewares"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func conversationRoutes(e *echo.Echo, db *gorm.DB) {
+	conversationDS := conversation.NewConversationStorer(db)
+	conversationHandler := handlers.NewConversationHandler(conversationDS)
+
+	e.POST("/ads/:id/conversations", conversationHandler.ContactSeller, middlewares.IsLoggedIn)
+	e.GET("/conversations", conversationHandler.Inbox, middlewares.IsLoggedIn)
+	e.GET("/conversations/unread", conversationHandler.UnreadCount, middlewares.IsLoggedIn)
+	e.GET("/conversations/:id/messages", conversationHandler.Messages, middlewares.IsLoggedIn)
+	e.POST("/conversations/:id/messages", conversationHandler.Send, middlewares.IsLoggedIn)
+	e.GET("/admin/conversations", conversationHandler.AdminListConversations, middlewares.IsLoggedIn)
+}
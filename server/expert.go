@@ -5,12 +5,13 @@ import (
 	"Airplane-Divar/datastore/user"
 	handlers "Airplane-Divar/handlers/expert"
 	"Airplane-Divar/middlewares"
+	"Airplane-Divar/service"
 
 	"github.com/labstack/echo/v4"
 	"gorm.io/gorm"
 )
 
-func expertRoutes(e *echo.Echo, db *gorm.DB) {
+func expertRoutes(e *echo.Echo, db *gorm.DB, storage service.Storage) {
 	expertDS := expert.NewExpertStorer(db)
 	userDS := user.New(db)
 	expertHandler := handlers.NewExpertHandler(expertDS, userDS)
@@ -20,5 +21,56 @@ func expertRoutes(e *echo.Echo, db *gorm.DB) {
 	e.GET("/expert/ads/:adID", expertHandler.GetExpertRequestByAd, middlewares.IsLoggedIn)
 	e.GET("/expert/check-request/:requestID", expertHandler.GetExpertRequest, middlewares.IsLoggedIn)
 	e.PUT("/expert/check-request/:expertRequestID", expertHandler.UpdateCheckExpert, middlewares.IsLoggedIn)
+	e.PUT("/expert/check-request/:expertRequestID/claim", expertHandler.ClaimCheckExpert, middlewares.IsLoggedIn)
+	e.DELETE("/expert/check-request/:expertRequestID/claim", expertHandler.ReleaseCheckExpert, middlewares.IsLoggedIn)
 	e.DELETE("/expert/ads/:adID", expertHandler.DeleteExpertRequest, middlewares.IsLoggedIn)
+
+	assignmentHandler := handlers.NewAssignmentHandler(expert.NewAssignmentStorer(db))
+	e.GET("/expert/profile", assignmentHandler.GetProfile, middlewares.IsLoggedIn)
+	e.PUT("/expert/profile", assignmentHandler.UpdateProfile, middlewares.IsLoggedIn)
+	e.PUT("/expert/check-request/:expertRequestID/assign", assignmentHandler.Reassign, middlewares.IsLoggedIn)
+
+	inspectionHandler := handlers.NewInspectionHandler(expert.NewInspectionStorer(db))
+	e.POST("/expert/templates", inspectionHandler.CreateTemplate, middlewares.IsLoggedIn)
+	e.GET("/expert/templates", inspectionHandler.ListTemplates, middlewares.IsLoggedIn)
+	e.GET("/expert/templates/:id", inspectionHandler.GetTemplate, middlewares.IsLoggedIn)
+	e.PUT("/expert/templates/:id", inspectionHandler.UpdateTemplate, middlewares.IsLoggedIn)
+	e.DELETE("/expert/templates/:id", inspectionHandler.DeleteTemplate, middlewares.IsLoggedIn)
+	e.GET("/expert/check-request/:expertRequestID/checklist", inspectionHandler.GetChecklist, middlewares.IsLoggedIn)
+	e.PUT("/expert/check-request/:expertRequestID/checklist", inspectionHandler.SaveChecklist, middlewares.IsLoggedIn)
+
+	attachmentHandler := handlers.NewAttachmentHandler(expert.NewAttachmentStorer(db), storage)
+	e.POST("/expert/check-request/:expertRequestID/attachments", attachmentHandler.Upload, middlewares.IsLoggedIn)
+	e.GET("/expert/check-request/:expertRequestID/attachments", attachmentHandler.List, middlewares.IsLoggedIn)
+	e.GET("/expert/check-request/:expertRequestID/attachments/:id", attachmentHandler.Download, middlewares.IsLoggedIn)
+	e.DELETE("/expert/check-request/:expertRequestID/attachments/:id", attachmentHandler.Delete, middlewares.IsLoggedIn)
+
+	reportHandler := handlers.NewReportHandler(expertDS, expert.NewCertificateStorer(db), expert.NewAttachmentStorer(db), storage)
+	e.GET("/expert/check-request/:requestID/pdf", reportHandler.PDF, middlewares.IsLoggedIn)
+
+	reviewHandler := handlers.NewReviewHandler(expert.NewReviewStorer(db), expert.NewAssignmentStorer(db))
+	e.POST("/expert/check-request/:expertRequestID/review", reviewHandler.Create, middlewares.IsLoggedIn)
+	e.GET("/experts/:id", reviewHandler.Profile)
+	e.GET("/admin/experts/:id/reviews", reviewHandler.List, middlewares.IsLoggedIn)
+	e.PUT("/admin/expert-reviews/:id/hide", reviewHandler.Hide, middlewares.IsLoggedIn)
+
+	appointmentDS := expert.NewAppointmentStorer(db)
+	appointmentHandler := handlers.NewAppointmentHandler(appointmentDS)
+	e.POST("/expert/slots", appointmentHandler.CreateSlot, middlewares.IsLoggedIn)
+	e.GET("/expert/slots", appointmentHandler.ListSlots, middlewares.IsLoggedIn)
+	e.DELETE("/expert/slots/:id", appointmentHandler.DeleteSlot, middlewares.IsLoggedIn)
+	e.GET("/expert/appointments", appointmentHandler.ListAppointments, middlewares.IsLoggedIn)
+	e.GET("/expert/check-request/:expertRequestID/slots", appointmentHandler.FreeSlots, middlewares.IsLoggedIn)
+	e.GET("/expert/check-request/:expertRequestID/appointment", appointmentHandler.GetAppointment, middlewares.IsLoggedIn)
+	e.POST("/expert/check-request/:expertRequestID/appointment", appointmentHandler.Book, middlewares.IsLoggedIn)
+	e.PUT("/expert/check-request/:expertRequestID/appointment", appointmentHandler.Reschedule, middlewares.IsLoggedIn)
+	e.DELETE("/expert/check-request/:expertRequestID/appointment", appointmentHandler.Cancel, middlewares.IsLoggedIn)
+
+	calendarHandler := handlers.NewCalendarHandler(expert.NewCalendarStorer(db), appointmentDS)
+	e.POST("/calendar/feed", calendarHandler.Create, middlewares.IsLoggedIn)
+	e.DELETE("/calendar/feed", calendarHandler.Revoke, middlewares.IsLoggedIn)
+	e.GET("/calendar/:token", calendarHandler.Feed)
+
+	certificateHandler := handlers.NewCertificateHandler()
+	e.GET("/verify/expert-report/:id", certificateHandler.Verify)
 }
@@ -0,0 +1,17 @@
+package server
+
+import (
+	"Airplane-Divar/datastore/featured"
+	handlers "Airplane-Divar/handlers/featured"
+	"Airplane-Divar/middlewares"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func featuredRoutes(e *echo.Echo, db *gorm.DB) {
+	featuredDS := featured.NewFeaturedStorer(db)
+	featuredHandler := handlers.NewFeaturedHandler(featuredDS)
+
+	e.POST("/ads/:id/feature", featuredHandler.RequestToFeature, middlewares.IsLoggedIn)
+}
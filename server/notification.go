@@ -0,0 +1,18 @@
+package server
+
+import (
+	"Airplane-Divar/datastore/notification"
+	handlers "Airplane-Divar/handlers/notification"
+	"Airplane-Divar/middlewares"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func notificationRoutes(e *echo.Echo, db *gorm.DB) {
+	notificationDS := notification.NewNotificationStorer(db)
+	notificationHandler := handlers.NewNotificationHandler(notificationDS)
+
+	e.GET("/notifications", notificationHandler.ListNotifications, middlewares.IsLoggedIn)
+	e.PUT("/notifications/:id/read", notificationHandler.MarkRead, middlewares.IsLoggedIn)
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"Airplane-Divar/datastore/offer"
+	handlers "Airplane-Divar/handlers/offer"
+	"Airplane-Divar/middlewares"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func offerRoutes(e *echo.Echo, db *gorm.DB) {
+	offerDS := offer.NewOfferStorer(db)
+	offerHandler := handlers.NewOfferHandler(offerDS)
+
+	e.POST("/ads/:id/offers", offerHandler.CreateOffer, middlewares.IsLoggedIn)
+	e.GET("/ads/:id/offers", offerHandler.ListOffers, middlewares.IsLoggedIn)
+	e.GET("/offers/:id", offerHandler.GetOffer, middlewares.IsLoggedIn)
+	e.POST("/offers/:id/accept", offerHandler.AcceptOffer, middlewares.IsLoggedIn)
+	e.POST("/offers/:id/reject", offerHandler.RejectOffer, middlewares.IsLoggedIn)
+	e.POST("/offers/:id/counter", offerHandler.CounterOffer, middlewares.IsLoggedIn)
+	e.POST("/offers/:id/complete", offerHandler.CompleteOffer, middlewares.IsLoggedIn)
+}
@@ -2,6 +2,7 @@ package server
 
 import (
 	"Airplane-Divar/datastore/expert"
+	"Airplane-Divar/datastore/featured"
 	"Airplane-Divar/datastore/payment"
 	"Airplane-Divar/datastore/repair"
 	"Airplane-Divar/datastore/user"
@@ -16,8 +17,9 @@ func paymentRoutes(e *echo.Echo, db *gorm.DB) {
 	paymentDS := payment.New(db)
 	repairDS := repair.NewRepairStorer(db)
 	expertDS := expert.NewExpertStorer(db)
+	featuredDS := featured.NewFeaturedStorer(db)
 	userDS := user.New(db)
-	paymentHandler := handlers.NewPaymentHandler(userDS, expertDS, repairDS, paymentDS)
+	paymentHandler := handlers.NewPaymentHandler(userDS, expertDS, repairDS, featuredDS, paymentDS)
 
 	e.POST("/users/payment/request", paymentHandler.PaymentRequestHandler, middlewares.IsLoggedIn)
 	e.GET("/users/payment/verify", paymentHandler.PaymentVerifyHandler)
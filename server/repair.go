@@ -20,5 +20,6 @@ func repairRoutes(e *echo.Echo, db *gorm.DB) {
 	e.GET("/repair/requests", repairHandler.GetAllRepairRequest, middlewares.IsLoggedIn)
 	e.PUT("/repair/request/:repairRequestID", repairHandler.UpdateRepairRequest, middlewares.IsLoggedIn)
 	e.GET("/repair/request/:requestID", repairHandler.GetRepairRequest, middlewares.IsLoggedIn)
+	e.GET("/repair/request/:requestID/pdf", repairHandler.GetRepairRequestPDF, middlewares.IsLoggedIn)
 	e.DELETE("/repair/ads/:adID", repairHandler.DeleteRepairRequest, middlewares.IsLoggedIn)
 }
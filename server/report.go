@@ -0,0 +1,19 @@
+package server
+
+import (
+	"Airplane-Divar/datastore/report"
+	handlers "Airplane-Divar/handlers/report"
+	"Airplane-Divar/middlewares"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func reportRoutes(e *echo.Echo, db *gorm.DB) {
+	reportDS := report.NewReportStorer(db)
+	reportHandler := handlers.NewReportHandler(reportDS)
+
+	e.POST("/ads/:id/report", reportHandler.ReportAd, middlewares.IsLoggedIn)
+	e.GET("/admin/reports", reportHandler.ListReports, middlewares.IsLoggedIn)
+	e.PUT("/admin/reports/:id/resolve", reportHandler.ResolveReport, middlewares.IsLoggedIn)
+}
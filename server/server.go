@@ -1,16 +1,31 @@
 package server
 
 import (
+	"Airplane-Divar/config"
 	database "Airplane-Divar/database"
 	adsDatastore "Airplane-Divar/datastore/ads"
 	"Airplane-Divar/datastore/logging"
 	"Airplane-Divar/datastore/user"
 	adsHandler "Airplane-Divar/handlers/ads"
 	userHandler "Airplane-Divar/handlers/user"
+	"Airplane-Divar/middlewares"
+	assignment_service "Airplane-Divar/service/assignment"
+	auction_service "Airplane-Divar/service/auction"
+	certificate_service "Airplane-Divar/service/certificate"
 	logging_service "Airplane-Divar/service/logging"
+	sla_service "Airplane-Divar/service/sla"
+	storage_service "Airplane-Divar/service/storage"
+	watch_service "Airplane-Divar/service/watch"
+	"context"
 	"log"
+	"time"
 
+	auctionDatastore "Airplane-Divar/datastore/auction"
 	bookmarkDatastore "Airplane-Divar/datastore/bookmarks"
+	draftDatastore "Airplane-Divar/datastore/draft"
+	expertDatastore "Airplane-Divar/datastore/expert"
+	notificationDatastore "Airplane-Divar/datastore/notification"
+	slaDatastore "Airplane-Divar/datastore/sla"
 	bookmarksHanlder "Airplane-Divar/handlers/bookmarks"
 
 	"github.com/labstack/echo/v4"
@@ -21,9 +36,14 @@ var e *echo.Echo
 
 func init() {
 	e = echo.New()
+	e.HTTPErrorHandler = middlewares.ErrorHandler
 }
 
 func StartServer() {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatal(err)
+	}
 	db, err := database.GetConnection()
 	if err != nil {
 		log.Fatal(err)
@@ -32,18 +52,37 @@ func StartServer() {
 	logDatastore := logging.New(db)
 	logging_service.Initialize(logDatastore)
 
+	// Assignment Service
+	assignment_service.Initialize(expertDatastore.NewAssignmentStorer(db))
+
+	// Certificate Service
+	if cfg.Signing.EXPERT_KEY == "" {
+		log.Println("EXPERT_SIGNING_KEY is not set, expert reports won't get certificates")
+	} else {
+		key, err := certificate_service.ParseKey(cfg.Signing.EXPERT_KEY)
+		if err != nil {
+			log.Fatal(err)
+		}
+		certificate_service.Initialize(key, expertDatastore.NewCertificateStorer(db))
+	}
+
+	// Watch Service
+	watch_service.Initialize(bookmarkDatastore.NewWatchStorer(db), notificationDatastore.NewNotificationStorer(db))
+
 	// Swagger
 	e.GET("/swagger/*", echoSwagger.WrapHandler)
 
 	// Expert
-	expertRoutes(e, db)
+	expertRoutes(e, db, storage_service.NewLocalStorage(cfg.Storage.DIR))
 
 	// Repair
 	repairRoutes(e, db)
 	// Ads
 	datastore := adsDatastore.New(db)
+	draftsHandler := adsHandler.NewDraftsHandler(datastore, draftDatastore.NewDraftStorer(db))
 	adsHandler := adsHandler.New(datastore)
 	adsRoutes(e, adsHandler)
+	draftsRoutes(e, draftsHandler)
 
 	// User
 	userDatastore := user.New(db)
@@ -53,10 +92,45 @@ func StartServer() {
 	// Payment
 	paymentRoutes(e, db)
 
+	// Featured
+	featuredRoutes(e, db)
+
+	// Categories
+	categoryRoutes(e, db)
+
+	// Offers
+	offerRoutes(e, db)
+
+	// Auctions
+	auctionRoutes(e, db)
+	auctionScheduler := auction_service.NewScheduler(
+		auctionDatastore.NewAuctionStorer(db), notificationDatastore.NewNotificationStorer(db), time.Minute,
+	)
+	go auctionScheduler.Run(context.Background())
+
+	// Notifications
+	notificationRoutes(e, db)
+
+	// Conversations
+	conversationRoutes(e, db)
+
+	// Reports
+	reportRoutes(e, db)
+
+	// SLA
+	slaRoutes(e, db)
+	slaChecker := sla_service.NewChecker(
+		slaDatastore.NewSLAStorer(db), notificationDatastore.NewNotificationStorer(db), time.Minute,
+	)
+	go slaChecker.Run(context.Background())
+
 	// Bookmarks
 	bmDatastore := bookmarkDatastore.New(db)
 	bmHandlers := bookmarksHanlder.New(bmDatastore)
 	bookmarksRoutes(e, bmHandlers)
+	collectionsRoutes(e, bookmarksHanlder.NewCollectionsHandler(bookmarkDatastore.NewCollectionStorer(db)))
+	watchRoutes(e, bookmarksHanlder.NewWatchHandler(bookmarkDatastore.NewWatchStorer(db)))
+	sharesRoutes(e, bookmarksHanlder.NewSharesHandler(bookmarkDatastore.NewShareStorer(db)))
 
 	log.Fatal(e.Start(":8080"))
 }
@@ -0,0 +1,18 @@
+package server
+
+import (
+	"Airplane-Divar/datastore/sla"
+	handlers "Airplane-Divar/handlers/sla"
+	"Airplane-Divar/middlewares"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+func slaRoutes(e *echo.Echo, db *gorm.DB) {
+	slaDS := sla.NewSLAStorer(db)
+	slaHandler := handlers.NewSLAHandler(slaDS)
+
+	e.GET("/admin/sla/overdue", slaHandler.ListOverdue, middlewares.IsLoggedIn)
+	e.GET("/admin/sla/compliance", slaHandler.Compliance, middlewares.IsLoggedIn)
+}
@@ -0,0 +1,147 @@
+package assignment_service
+
+import (
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	"Airplane-Divar/service"
+	logging_service "Airplane-Divar/service/logging"
+	"context"
+	"fmt"
+	"time"
+)
+
+// Assigner gives newly paid expert requests to an expert, so they don't wait
+// for someone to claim them.
+type Assigner struct {
+	experts datastore.ExpertAssignment
+}
+
+var assigner *Assigner
+
+func Initialize(experts datastore.ExpertAssignment) {
+	if assigner == nil {
+		assigner = &Assigner{experts: experts}
+	}
+}
+
+func GetInstance() service.Assignment {
+	return assigner
+}
+
+// Assign picks an expert for the request with the configured strategy. It
+// returns false when assignment is manual or no expert can take the request,
+// the request then waits to be claimed.
+func (a *Assigner) Assign(ctx context.Context, requestID int) (models.ExpertAds, bool, error) {
+	strategy, err := a.experts.Strategy(ctx)
+	if err != nil || strategy == consts.ASSIGN_MANUAL {
+		return models.ExpertAds{}, false, err
+	}
+
+	candidates, err := a.experts.Candidates(ctx, requestID)
+	if err != nil {
+		return models.ExpertAds{}, false, err
+	}
+	expert, ok := Pick(strategy, candidates)
+	if !ok {
+		return models.ExpertAds{}, false, nil
+	}
+
+	expertAd, err := a.experts.Assign(ctx, requestID, expert.ExpertID, false, time.Now())
+	if err != nil {
+		return models.ExpertAds{}, false, err
+	}
+	LogAssignment("System", 0, expertAd, strategyName(strategy))
+	return expertAd, true, nil
+}
+
+// Pick chooses the expert for a request. Specialists of the category of the
+// ad go first, round robin takes the one who waited the longest for a
+// request, least loaded the one with the fewest requests in hand and top
+// rated the one airlines rated best.
+func Pick(strategy int, candidates []models.ExpertCandidate) (models.ExpertCandidate, bool) {
+	pool := []models.ExpertCandidate{}
+	for _, candidate := range candidates {
+		if candidate.Specialist {
+			pool = append(pool, candidate)
+		}
+	}
+	if len(pool) == 0 {
+		pool = candidates
+	}
+	if len(pool) == 0 {
+		return models.ExpertCandidate{}, false
+	}
+
+	best := pool[0]
+	for _, candidate := range pool[1:] {
+		if strategy == consts.ASSIGN_LEAST_LOADED && candidate.ActiveRequests != best.ActiveRequests {
+			if candidate.ActiveRequests < best.ActiveRequests {
+				best = candidate
+			}
+			continue
+		}
+		if strategy == consts.ASSIGN_TOP_RATED && Rating(candidate) != Rating(best) {
+			if Rating(candidate) > Rating(best) {
+				best = candidate
+			}
+			continue
+		}
+		if waitedLonger(candidate, best) {
+			best = candidate
+		}
+	}
+	return best, true
+}
+
+// waitedLonger tells if a was last assigned before b, experts who were never
+// assigned come first and ties go to the lower ID.
+func waitedLonger(a models.ExpertCandidate, b models.ExpertCandidate) bool {
+	switch {
+	case a.LastAssignedAt == nil && b.LastAssignedAt == nil:
+		return a.ExpertID < b.ExpertID
+	case a.LastAssignedAt == nil:
+		return true
+	case b.LastAssignedAt == nil:
+		return false
+	case a.LastAssignedAt.Equal(*b.LastAssignedAt):
+		return a.ExpertID < b.ExpertID
+	}
+	return a.LastAssignedAt.Before(*b.LastAssignedAt)
+}
+
+// Rating is the average rating of the candidate pulled towards the prior by
+// how few reviews they have, experts without reviews get the prior.
+func Rating(candidate models.ExpertCandidate) float64 {
+	reviews := float64(candidate.Reviews)
+	return (candidate.Rating*reviews + consts.REVIEW_PRIOR_RATING*consts.REVIEW_PRIOR_WEIGHT) /
+		(reviews + consts.REVIEW_PRIOR_WEIGHT)
+}
+
+func strategyName(strategy int) string {
+	switch strategy {
+	case consts.ASSIGN_LEAST_LOADED:
+		return "least loaded"
+	case consts.ASSIGN_TOP_RATED:
+		return "top rated"
+	}
+	return "round robin"
+}
+
+// LogAssignment reports the assignment of an expert request on its ad, or
+// that it lost its expert.
+func LogAssignment(causerType string, causerID uint, expertAd models.ExpertAds, by string) {
+	// ____ Report Log ____
+	logService := logging_service.GetInstance()
+	if logService != (*logging_service.Logging)(nil) {
+		description := fmt.Sprintf("request %d assigned to expert %d by %s", expertAd.ID, expertAd.ExpertID, by)
+		if expertAd.ExpertID == 0 {
+			description = fmt.Sprintf("request %d unassigned by %s", expertAd.ID, by)
+		}
+		err := logService.ReportActivity(causerType, causerID, "Ads", expertAd.AdsID, consts.LOG_EXPERT_ASSIGNED, description)
+		if err != nil {
+			_ = fmt.Errorf("cannot log activity %v", consts.LOG_EXPERT_ASSIGNED)
+		}
+	}
+	// ____ Report Log ____
+}
@@ -0,0 +1,216 @@
+package assignment_service
+
+import (
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPick(t *testing.T) {
+	now := time.Now()
+	at := func(ago time.Duration) *time.Time {
+		assigned := now.Add(-ago)
+		return &assigned
+	}
+
+	testcases := []struct {
+		name       string
+		strategy   int
+		candidates []models.ExpertCandidate
+		want       uint
+		ok         bool
+	}{
+		{
+			name:     "no candidates",
+			strategy: consts.ASSIGN_ROUND_ROBIN,
+			ok:       false,
+		},
+		{
+			name:     "round robin takes who waited the longest",
+			strategy: consts.ASSIGN_ROUND_ROBIN,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, LastAssignedAt: at(time.Hour)},
+				{ExpertID: 2, LastAssignedAt: at(3 * time.Hour)},
+				{ExpertID: 3, LastAssignedAt: at(2 * time.Hour)},
+			},
+			want: 2,
+			ok:   true,
+		},
+		{
+			name:     "never assigned experts go first, lower ID on ties",
+			strategy: consts.ASSIGN_ROUND_ROBIN,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, LastAssignedAt: at(time.Hour)},
+				{ExpertID: 4},
+				{ExpertID: 3},
+			},
+			want: 3,
+			ok:   true,
+		},
+		{
+			name:     "specialists come first",
+			strategy: consts.ASSIGN_ROUND_ROBIN,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1},
+				{ExpertID: 2, LastAssignedAt: at(time.Minute), Specialist: true},
+			},
+			want: 2,
+			ok:   true,
+		},
+		{
+			name:     "specialists come first for least loaded",
+			strategy: consts.ASSIGN_LEAST_LOADED,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, ActiveRequests: 0},
+				{ExpertID: 2, ActiveRequests: 5, Specialist: true},
+				{ExpertID: 3, ActiveRequests: 3, Specialist: true},
+			},
+			want: 3,
+			ok:   true,
+		},
+		{
+			name:     "specialists come first for top rated",
+			strategy: consts.ASSIGN_TOP_RATED,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, Rating: 5, Reviews: 100},
+				{ExpertID: 2, Rating: 2, Reviews: 10, Specialist: true},
+			},
+			want: 2,
+			ok:   true,
+		},
+		{
+			name:     "least loaded takes the fewest requests in hand",
+			strategy: consts.ASSIGN_LEAST_LOADED,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, ActiveRequests: 2},
+				{ExpertID: 2, ActiveRequests: 1, LastAssignedAt: at(time.Minute)},
+				{ExpertID: 3, ActiveRequests: 4},
+			},
+			want: 2,
+			ok:   true,
+		},
+		{
+			name:     "least loaded ties fall back to round robin",
+			strategy: consts.ASSIGN_LEAST_LOADED,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, ActiveRequests: 1, LastAssignedAt: at(time.Minute)},
+				{ExpertID: 2, ActiveRequests: 1, LastAssignedAt: at(time.Hour)},
+				{ExpertID: 3, ActiveRequests: 2},
+			},
+			want: 2,
+			ok:   true,
+		},
+		{
+			name:     "top rated takes the best rating",
+			strategy: consts.ASSIGN_TOP_RATED,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, Rating: 3.5, Reviews: 20},
+				{ExpertID: 2, Rating: 4.5, Reviews: 20, LastAssignedAt: at(time.Minute)},
+				{ExpertID: 3, Rating: 4, Reviews: 20},
+			},
+			want: 2,
+			ok:   true,
+		},
+		{
+			name:     "one perfect review does not beat a long good record",
+			strategy: consts.ASSIGN_TOP_RATED,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, Rating: 4.2, Reviews: 30, LastAssignedAt: at(time.Minute)},
+				{ExpertID: 2, Rating: 5, Reviews: 1},
+			},
+			want: 1,
+			ok:   true,
+		},
+		{
+			name:     "enough perfect reviews beat a long good record",
+			strategy: consts.ASSIGN_TOP_RATED,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, Rating: 4.2, Reviews: 30},
+				{ExpertID: 2, Rating: 5, Reviews: 10, LastAssignedAt: at(time.Minute)},
+			},
+			want: 2,
+			ok:   true,
+		},
+		{
+			name:     "an unreviewed expert beats a poorly reviewed one",
+			strategy: consts.ASSIGN_TOP_RATED,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, Rating: 2, Reviews: 3},
+				{ExpertID: 2, LastAssignedAt: at(time.Minute)},
+			},
+			want: 2,
+			ok:   true,
+		},
+		{
+			name:     "a well reviewed expert beats an unreviewed one",
+			strategy: consts.ASSIGN_TOP_RATED,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1},
+				{ExpertID: 2, Rating: 4, Reviews: 5, LastAssignedAt: at(time.Minute)},
+			},
+			want: 2,
+			ok:   true,
+		},
+		{
+			name:     "top rated ties fall back to round robin",
+			strategy: consts.ASSIGN_TOP_RATED,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, Rating: 4, Reviews: 10, LastAssignedAt: at(time.Minute)},
+				{ExpertID: 2, Rating: 4, Reviews: 10, LastAssignedAt: at(time.Hour)},
+				{ExpertID: 3, Rating: 3, Reviews: 10},
+			},
+			want: 2,
+			ok:   true,
+		},
+		{
+			name:     "unreviewed experts tie and fall back to round robin",
+			strategy: consts.ASSIGN_TOP_RATED,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, LastAssignedAt: at(time.Minute)},
+				{ExpertID: 2},
+			},
+			want: 2,
+			ok:   true,
+		},
+		{
+			name:     "round robin ignores ratings and load",
+			strategy: consts.ASSIGN_ROUND_ROBIN,
+			candidates: []models.ExpertCandidate{
+				{ExpertID: 1, Rating: 5, Reviews: 50, LastAssignedAt: at(time.Minute)},
+				{ExpertID: 2, ActiveRequests: 9, Rating: 1, Reviews: 50, LastAssignedAt: at(time.Hour)},
+			},
+			want: 2,
+			ok:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := Pick(tc.strategy, tc.candidates)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got.ExpertID)
+		})
+	}
+}
+
+func TestRating(t *testing.T) {
+	testcases := []struct {
+		name      string
+		candidate models.ExpertCandidate
+		want      float64
+	}{
+		{"no reviews get the prior", models.ExpertCandidate{}, consts.REVIEW_PRIOR_RATING},
+		{"one review moves a little", models.ExpertCandidate{Rating: 5, Reviews: 1}, (5 + 3.0*5) / 6},
+		{"many reviews approach the average", models.ExpertCandidate{Rating: 5, Reviews: 995}, (5*995 + 3.0*5) / 1000},
+		{"a low average pulls down", models.ExpertCandidate{Rating: 1, Reviews: 5}, 2},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.want, Rating(tc.candidate), 1e-9)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package auction_service
+
+import (
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	logging_service "Airplane-Divar/service/logging"
+	watch_service "Airplane-Divar/service/watch"
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Scheduler periodically closes the auctions that ended and notifies the
+// winners, sellers and the users who bookmarked a reserved ad.
+type Scheduler struct {
+	auctions      datastore.Auction
+	notifications datastore.Notification
+	interval      time.Duration
+}
+
+func NewScheduler(auctions datastore.Auction, notifications datastore.Notification, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		auctions:      auctions,
+		notifications: notifications,
+		interval:      interval,
+	}
+}
+
+// Run closes due auctions every interval until ctx is done.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.CloseDue(ctx, now); err != nil {
+				log.Printf("could not close auctions: %v", err)
+			}
+		}
+	}
+}
+
+func (s *Scheduler) CloseDue(ctx context.Context, now time.Time) error {
+	closed, err := s.auctions.CloseDue(ctx, now)
+	for _, auction := range closed {
+		s.notify(ctx, auction)
+	}
+	return err
+}
+
+func (s *Scheduler) notify(ctx context.Context, auction models.Auction) {
+	sellerMsg := fmt.Sprintf("Your auction on ad %d ended without meeting the reserve price", auction.AdsID)
+	if auction.WinnerID == nil && auction.HighestBidderID != nil && auction.HighestBid >= auction.ReservePrice {
+		sellerMsg = fmt.Sprintf("Your auction on ad %d ended without a winner, the ad was no longer available", auction.AdsID)
+	}
+	if auction.WinnerID != nil {
+		sellerMsg = fmt.Sprintf("Your auction on ad %d was won with a bid of %d", auction.AdsID, auction.HighestBid)
+
+		err := s.notifications.Create(ctx, models.Notification{
+			UserID:  *auction.WinnerID,
+			AdsID:   auction.AdsID,
+			Kind:    consts.NOTIFICATION_AUCTION_WON,
+			Message: fmt.Sprintf("You won the auction on ad %d with a bid of %d", auction.AdsID, auction.HighestBid),
+		})
+		if err != nil {
+			log.Printf("could not notify auction %d winner: %v", auction.ID, err)
+		}
+
+		// ____ Notify Watchers ____
+		watchService := watch_service.GetInstance()
+		if watchService != (*watch_service.Watch)(nil) {
+			err = watchService.StatusChanged(ctx, auction.AdsID, consts.ACTIVE, consts.RESERVED)
+			if err != nil {
+				log.Printf("could not notify the watchers of ad %d: %v", auction.AdsID, err)
+			}
+		}
+		// ____ Notify Watchers ____
+	}
+
+	err := s.notifications.Create(ctx, models.Notification{
+		UserID:  auction.SellerID,
+		AdsID:   auction.AdsID,
+		Kind:    consts.NOTIFICATION_AUCTION_CLOSED,
+		Message: sellerMsg,
+	})
+	if err != nil {
+		log.Printf("could not notify auction %d seller: %v", auction.ID, err)
+	}
+
+	// ____ Report Log ____
+	logService := logging_service.GetInstance()
+	if logService != (*logging_service.Logging)(nil) {
+		err = logService.ReportActivity("System", 0, "Ads", auction.AdsID, consts.LOG_AUCTION_CLOSED, "")
+		if err != nil {
+			_ = fmt.Errorf("cannot log activity %v", consts.LOG_AUCTION_CLOSED)
+		}
+	}
+	// ____ Report Log ____
+}
@@ -0,0 +1,201 @@
+package certificate_service
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	"Airplane-Divar/service"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var ErrRevisionNotFound = apperror.NotFound("certificate_not_found", "the expert report has no such certificate revision")
+
+// Signer issues tamper evident certificates for finished expert reports, so
+// buyers can trust a report they received outside the platform.
+type Signer struct {
+	key          ed25519.PrivateKey
+	keyID        string
+	certificates datastore.ExpertCertificate
+}
+
+var signer *Signer
+
+func Initialize(key ed25519.PrivateKey, certificates datastore.ExpertCertificate) {
+	if signer == nil {
+		signer = &Signer{key: key, keyID: KeyID(key.Public().(ed25519.PublicKey)), certificates: certificates}
+	}
+}
+
+func GetInstance() service.Certificate {
+	return signer
+}
+
+// ParseKey reads a base64 encoded ed25519 key, either the 32 byte seed or
+// the 64 byte private key.
+func ParseKey(encoded string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("signing key is not base64: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	}
+	return nil, errors.New("signing key must be a 32 byte seed or a 64 byte private key")
+}
+
+// KeyID names a public key, certificates keep it so a rotated key doesn't
+// pass for the one that signed them.
+func KeyID(public ed25519.PublicKey) string {
+	sum := sha256.Sum256(public)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Code is a short fingerprint of a signature, printed on reports so readers
+// can match them with what the verify endpoint shows.
+func Code(signature string) string {
+	sum := sha256.Sum256([]byte(signature))
+	code := strings.ToUpper(hex.EncodeToString(sum[:8]))
+	return code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16]
+}
+
+// Issue signs the current state of a finished report as its next revision.
+func (s *Signer) Issue(ctx context.Context, requestID int) (models.ExpertCertificate, error) {
+	now := time.Now()
+	return s.certificates.Issue(ctx, requestID, func(report models.ExpertAds, revision int) (models.ExpertCertificate, error) {
+		payload, err := Canonical(Payload(report, revision, now))
+		if err != nil {
+			return models.ExpertCertificate{}, err
+		}
+		return models.ExpertCertificate{
+			Payload:   string(payload),
+			Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, payload)),
+			KeyID:     s.keyID,
+			IssuedAt:  now,
+		}, nil
+	})
+}
+
+// Verify checks a revision of the certificate of a report, the latest one
+// when revision is 0.
+func (s *Signer) Verify(ctx context.Context, requestID int, revision int) (models.CertificateVerification, error) {
+	certificates, err := s.certificates.List(ctx, requestID)
+	if err != nil {
+		return models.CertificateVerification{}, err
+	}
+	latest := certificates[len(certificates)-1]
+	certificate := latest
+	if revision != 0 {
+		found := false
+		for _, c := range certificates {
+			if c.Revision == revision {
+				certificate, found = c, true
+			}
+		}
+		if !found {
+			return models.CertificateVerification{}, ErrRevisionNotFound
+		}
+	}
+
+	public := s.key.Public().(ed25519.PublicKey)
+	verification := models.CertificateVerification{
+		Certificate:    certificate,
+		LatestRevision: latest.Revision,
+		Revised:        certificate.Revision < latest.Revision,
+		PublicKey:      public,
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(certificate.Signature)
+	verification.Valid = err == nil &&
+		certificate.KeyID == s.keyID &&
+		ed25519.Verify(public, []byte(certificate.Payload), signature)
+
+	// the report is signed again from what the platform has now, any
+	// difference means it was changed after the certificate was issued
+	var signed models.CertificatePayload
+	if err := json.Unmarshal([]byte(certificate.Payload), &signed); err != nil {
+		verification.Valid = false
+		return verification, nil
+	}
+	report, err := s.certificates.Report(ctx, requestID)
+	if err != nil {
+		return models.CertificateVerification{}, err
+	}
+	current, err := Canonical(Payload(report, signed.Revision, signed.IssuedAt))
+	if err != nil {
+		return models.CertificateVerification{}, err
+	}
+	verification.Revised = verification.Revised || !bytes.Equal(current, []byte(certificate.Payload))
+
+	return verification, nil
+}
+
+// Payload is what the certificate of a report says, in canonical order.
+func Payload(report models.ExpertAds, revision int, issuedAt time.Time) models.CertificatePayload {
+	airframe := make([]models.CertificateAttribute, 0, len(report.Ads.Attributes))
+	for _, attribute := range report.Ads.Attributes {
+		airframe = append(airframe, models.CertificateAttribute{Name: attribute.Attribute.Name, Value: attribute.Value})
+	}
+	sort.Slice(airframe, func(i, j int) bool {
+		if airframe[i].Name != airframe[j].Name {
+			return airframe[i].Name < airframe[j].Name
+		}
+		return airframe[i].Value < airframe[j].Value
+	})
+
+	items := append([]models.InspectionItem(nil), report.Checklist...)
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].Position != items[j].Position {
+			return items[i].Position < items[j].Position
+		}
+		return items[i].TemplateItemID < items[j].TemplateItemID
+	})
+	findings := make([]models.CertificateFinding, 0, len(items))
+	for _, item := range items {
+		findings = append(findings, models.CertificateFinding{
+			Section:  item.Section,
+			Label:    item.Label,
+			Rating:   item.Rating,
+			Severity: item.Severity,
+			Finding:  item.Finding,
+		})
+	}
+
+	return models.CertificatePayload{
+		RequestID: report.ID,
+		Revision:  revision,
+		Ad: models.CertificateAd{
+			ID:            report.Ads.ID,
+			Subject:       report.Ads.Subject,
+			CategoryID:    report.Ads.CategoryID,
+			AirplaneModel: report.Ads.AirplaneModel,
+			PlaneAge:      report.Ads.PlaneAge,
+			FlyTime:       report.Ads.FlyTime,
+			Airframe:      airframe,
+		},
+		Expert:   models.CertificateExpert{ID: report.Expert.ID, Username: report.Expert.Username},
+		Report:   report.Report,
+		Grade:    report.Grade,
+		Score:    report.Score,
+		Findings: findings,
+		IssuedAt: issuedAt.UTC().Truncate(time.Second),
+	}
+}
+
+// Canonical is the encoding of a payload that gets signed.
+func Canonical(payload models.CertificatePayload) ([]byte, error) {
+	return json.Marshal(payload)
+}
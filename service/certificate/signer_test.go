@@ -0,0 +1,234 @@
+package certificate_service
+
+import (
+	"Airplane-Divar/datastore/expert"
+	"Airplane-Divar/models"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCertificates keeps the report and its certificates in memory, it
+// numbers revisions like the datastore does.
+type fakeCertificates struct {
+	report       models.ExpertAds
+	certificates []models.ExpertCertificate
+}
+
+func (f *fakeCertificates) Issue(
+	ctx context.Context,
+	requestID int,
+	sign func(report models.ExpertAds, revision int) (models.ExpertCertificate, error),
+) (models.ExpertCertificate, error) {
+	certificate, err := sign(f.report, len(f.certificates)+1)
+	if err != nil {
+		return models.ExpertCertificate{}, err
+	}
+	certificate.ID = uint(len(f.certificates) + 1)
+	certificate.ExpertAdsID = f.report.ID
+	certificate.Revision = len(f.certificates) + 1
+	f.certificates = append(f.certificates, certificate)
+	return certificate, nil
+}
+
+func (f *fakeCertificates) Report(ctx context.Context, requestID int) (models.ExpertAds, error) {
+	return f.report, nil
+}
+
+func (f *fakeCertificates) List(ctx context.Context, requestID int) ([]models.ExpertCertificate, error) {
+	if len(f.certificates) == 0 {
+		return nil, expert.ErrCertificateNotFound
+	}
+	return append([]models.ExpertCertificate(nil), f.certificates...), nil
+}
+
+func newSigner(t *testing.T, certificates *fakeCertificates) *Signer {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Signer{key: key, keyID: KeyID(key.Public().(ed25519.PublicKey)), certificates: certificates}
+}
+
+func report() models.ExpertAds {
+	return models.ExpertAds{
+		ID:     7,
+		Report: "Minor corrosion on the left wing root.",
+		Grade:  "B",
+		Score:  82.5,
+		Ads: models.Ad{
+			ID:            3,
+			Subject:       "Retired A320",
+			AirplaneModel: "A320",
+			PlaneAge:      19,
+			FlyTime:       42000,
+		},
+		Expert: models.User{ID: 2, Username: "expert"},
+		Checklist: []models.InspectionItem{
+			{TemplateItemID: 2, Section: "engines", Label: "Fan blades", Position: 2, Rating: 5, Severity: "none"},
+			{TemplateItemID: 1, Section: "airframe", Label: "Wing root", Position: 1, Rating: 3, Severity: "minor", Finding: "corrosion"},
+		},
+	}
+}
+
+func TestSigner_Verify(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("issued certificate verifies", func(t *testing.T) {
+		store := &fakeCertificates{report: report()}
+		s := newSigner(t, store)
+
+		issued, err := s.Issue(ctx, 7)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, issued.Revision)
+		assert.Equal(t, s.keyID, issued.KeyID)
+
+		verification, err := s.Verify(ctx, 7, 0)
+		assert.NoError(t, err)
+		assert.True(t, verification.Valid)
+		assert.False(t, verification.Revised)
+		assert.Equal(t, 1, verification.LatestRevision)
+		assert.Equal(t, []byte(s.key.Public().(ed25519.PublicKey)), []byte(verification.PublicKey))
+	})
+
+	t.Run("tampered payload is rejected", func(t *testing.T) {
+		store := &fakeCertificates{report: report()}
+		s := newSigner(t, store)
+		_, err := s.Issue(ctx, 7)
+		assert.NoError(t, err)
+
+		payload := store.certificates[0].Payload
+		assert.Contains(t, payload, `"grade":"B"`)
+		store.certificates[0].Payload = strings.Replace(payload, `"grade":"B"`, `"grade":"A"`, 1)
+		store.report.Grade = "A"
+
+		verification, err := s.Verify(ctx, 7, 0)
+		assert.NoError(t, err)
+		assert.False(t, verification.Valid)
+	})
+
+	t.Run("forged signature is rejected", func(t *testing.T) {
+		store := &fakeCertificates{report: report()}
+		s := newSigner(t, store)
+		_, err := s.Issue(ctx, 7)
+		assert.NoError(t, err)
+
+		store.certificates[0].Signature = "not base64 !"
+		verification, err := s.Verify(ctx, 7, 0)
+		assert.NoError(t, err)
+		assert.False(t, verification.Valid)
+
+		_, other, _ := ed25519.GenerateKey(rand.Reader)
+		signature := ed25519.Sign(other, []byte(store.certificates[0].Payload))
+		store.certificates[0].Signature = base64.StdEncoding.EncodeToString(signature)
+		verification, err = s.Verify(ctx, 7, 0)
+		assert.NoError(t, err)
+		assert.False(t, verification.Valid)
+	})
+
+	t.Run("wrong key id is rejected", func(t *testing.T) {
+		store := &fakeCertificates{report: report()}
+		s := newSigner(t, store)
+		_, err := s.Issue(ctx, 7)
+		assert.NoError(t, err)
+
+		store.certificates[0].KeyID = "0000000000000000"
+		verification, err := s.Verify(ctx, 7, 0)
+		assert.NoError(t, err)
+		assert.False(t, verification.Valid)
+	})
+
+	t.Run("rotated key does not verify old certificates", func(t *testing.T) {
+		store := &fakeCertificates{report: report()}
+		_, err := newSigner(t, store).Issue(ctx, 7)
+		assert.NoError(t, err)
+
+		verification, err := newSigner(t, store).Verify(ctx, 7, 0)
+		assert.NoError(t, err)
+		assert.False(t, verification.Valid)
+	})
+
+	t.Run("edited report is revised", func(t *testing.T) {
+		store := &fakeCertificates{report: report()}
+		s := newSigner(t, store)
+		_, err := s.Issue(ctx, 7)
+		assert.NoError(t, err)
+
+		store.report.Report = "No findings."
+		verification, err := s.Verify(ctx, 7, 0)
+		assert.NoError(t, err)
+		assert.True(t, verification.Valid)
+		assert.True(t, verification.Revised)
+
+		store.report = report()
+		store.report.Checklist[1].Rating = 5
+		verification, err = s.Verify(ctx, 7, 0)
+		assert.NoError(t, err)
+		assert.True(t, verification.Revised)
+
+		// the order the checklist is loaded in does not matter
+		store.report = report()
+		store.report.Checklist[0], store.report.Checklist[1] = store.report.Checklist[1], store.report.Checklist[0]
+		verification, err = s.Verify(ctx, 7, 0)
+		assert.NoError(t, err)
+		assert.False(t, verification.Revised)
+	})
+
+	t.Run("older revisions are revised", func(t *testing.T) {
+		store := &fakeCertificates{report: report()}
+		s := newSigner(t, store)
+		_, err := s.Issue(ctx, 7)
+		assert.NoError(t, err)
+		store.report.Report = "Wing root repaired."
+		_, err = s.Issue(ctx, 7)
+		assert.NoError(t, err)
+
+		verification, err := s.Verify(ctx, 7, 1)
+		assert.NoError(t, err)
+		assert.True(t, verification.Valid)
+		assert.True(t, verification.Revised)
+		assert.Equal(t, 1, verification.Certificate.Revision)
+		assert.Equal(t, 2, verification.LatestRevision)
+
+		verification, err = s.Verify(ctx, 7, 0)
+		assert.NoError(t, err)
+		assert.True(t, verification.Valid)
+		assert.False(t, verification.Revised)
+		assert.Equal(t, 2, verification.Certificate.Revision)
+
+		_, err = s.Verify(ctx, 7, 3)
+		assert.ErrorIs(t, err, ErrRevisionNotFound)
+	})
+
+	t.Run("unsigned report", func(t *testing.T) {
+		s := newSigner(t, &fakeCertificates{report: report()})
+		_, err := s.Verify(ctx, 7, 0)
+		assert.ErrorIs(t, err, expert.ErrCertificateNotFound)
+	})
+}
+
+func TestParseKey(t *testing.T) {
+	seed := make([]byte, ed25519.SeedSize)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+	key := ed25519.NewKeyFromSeed(seed)
+
+	fromSeed, err := ParseKey(base64.StdEncoding.EncodeToString(seed))
+	assert.NoError(t, err)
+	assert.Equal(t, key, fromSeed)
+
+	fromKey, err := ParseKey(base64.StdEncoding.EncodeToString(key))
+	assert.NoError(t, err)
+	assert.Equal(t, key, fromKey)
+
+	_, err = ParseKey(base64.StdEncoding.EncodeToString(seed[:16]))
+	assert.Error(t, err)
+	_, err = ParseKey("not base64 !")
+	assert.Error(t, err)
+}
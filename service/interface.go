@@ -1,5 +1,12 @@
 package service
 
+import (
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"context"
+	"io"
+)
+
 type (
 	Logging interface {
 		GetAdsActivity(ID int) ([]byte, error)
@@ -12,4 +19,26 @@ type (
 			description string,
 		) error
 	}
+
+	Assignment interface {
+		Assign(ctx context.Context, requestID int) (models.ExpertAds, bool, error)
+	}
+
+	Watch interface {
+		AdChanged(ctx context.Context, before models.Ad, after models.Ad) error
+		StatusChanged(ctx context.Context, adID uint, from consts.AdStatus, to consts.AdStatus) error
+		ExpertReportCompleted(ctx context.Context, adID uint) error
+	}
+
+	Certificate interface {
+		Issue(ctx context.Context, requestID int) (models.ExpertCertificate, error)
+		Verify(ctx context.Context, requestID int, revision int) (models.CertificateVerification, error)
+	}
+
+	// Storage keeps uploaded files, keys are slash separated paths.
+	Storage interface {
+		Save(ctx context.Context, key string, r io.Reader) (int64, error)
+		Open(ctx context.Context, key string) (io.ReadCloser, error)
+		Delete(ctx context.Context, key string) error
+	}
 )
@@ -0,0 +1,91 @@
+package sla_service
+
+import (
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	logging_service "Airplane-Divar/service/logging"
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Checker periodically escalates the expert and repair requests that went
+// past their SLA to the admins.
+type Checker struct {
+	periods       datastore.SLA
+	notifications datastore.Notification
+	interval      time.Duration
+}
+
+func NewChecker(periods datastore.SLA, notifications datastore.Notification, interval time.Duration) *Checker {
+	return &Checker{
+		periods:       periods,
+		notifications: notifications,
+		interval:      interval,
+	}
+}
+
+// Run checks for overdue requests every interval until ctx is done.
+func (c *Checker) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := c.Check(ctx, now); err != nil {
+				log.Printf("could not check SLAs: %v", err)
+			}
+		}
+	}
+}
+
+// Check escalates the periods that are overdue at now. Periods are only
+// escalated once, so the admins are loaded first and a failure leaves them
+// for the next check.
+func (c *Checker) Check(ctx context.Context, now time.Time) error {
+	admins, err := c.periods.Admins(ctx)
+	if err != nil {
+		return err
+	}
+	escalated, err := c.periods.Escalate(ctx, now)
+	if err != nil {
+		return err
+	}
+	for _, period := range escalated {
+		c.escalate(ctx, period, admins)
+	}
+	return nil
+}
+
+func (c *Checker) escalate(ctx context.Context, period models.SLAPeriod, admins []uint) {
+	msg := fmt.Sprintf(
+		"The %s request %d on ad %d is overdue, it has been %s since %s",
+		period.Kind, period.RequestID, period.AdsID, period.Status, period.StartedAt.Format(time.RFC3339),
+	)
+	for _, admin := range admins {
+		err := c.notifications.Create(ctx, models.Notification{
+			UserID:  admin,
+			AdsID:   period.AdsID,
+			Kind:    consts.NOTIFICATION_SLA_ESCALATED,
+			Message: msg,
+		})
+		if err != nil {
+			log.Printf("could not notify admin %d of SLA period %d: %v", admin, period.ID, err)
+		}
+	}
+
+	// ____ Report Log ____
+	logService := logging_service.GetInstance()
+	if logService != (*logging_service.Logging)(nil) {
+		err := logService.ReportActivity("System", 0, "Ads", period.AdsID, consts.LOG_SLA_ESCALATED, msg)
+		if err != nil {
+			_ = fmt.Errorf("cannot log activity %v", consts.LOG_SLA_ESCALATED)
+		}
+	}
+	// ____ Report Log ____
+}
@@ -0,0 +1,93 @@
+package storage_service
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/service"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrObjectNotFound = apperror.NotFound("file_not_found", "file does not exist")
+	ErrInvalidKey     = apperror.Internal("invalid storage key")
+)
+
+// LocalStorage keeps files on the local disk under dir, the key of a file is
+// its path relative to dir.
+type LocalStorage struct {
+	dir string
+}
+
+var _ service.Storage = LocalStorage{}
+
+func NewLocalStorage(dir string) LocalStorage {
+	return LocalStorage{dir: dir}
+}
+
+// Save writes the file to a temporary file first, so readers never see a
+// partially written one.
+func (s LocalStorage) Save(ctx context.Context, key string, r io.Reader) (int64, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return 0, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, r)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err != nil {
+		return 0, err
+	}
+	return size, os.Rename(tmp.Name(), path)
+}
+
+func (s LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	return file, err
+}
+
+func (s LocalStorage) Delete(ctx context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path resolves a key inside dir, keys can't climb out of it.
+func (s LocalStorage) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", ErrInvalidKey
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(clean)), nil
+}
@@ -0,0 +1,131 @@
+package watch_service
+
+import (
+	"Airplane-Divar/consts"
+	"Airplane-Divar/datastore"
+	"Airplane-Divar/models"
+	"Airplane-Divar/service"
+	"context"
+	"fmt"
+)
+
+// Watch tells the users who bookmarked an ad when something they care about
+// changes on it.
+type Watch struct {
+	bookmarks     datastore.BookmarkWatch
+	notifications datastore.Notification
+}
+
+// Change is a change of an ad worth a notification.
+type Change struct {
+	Kind    string
+	Message string
+}
+
+var watchService *Watch
+
+func Initialize(bookmarks datastore.BookmarkWatch, notifications datastore.Notification) {
+	if watchService == nil {
+		watchService = &Watch{
+			bookmarks:     bookmarks,
+			notifications: notifications,
+		}
+	}
+}
+
+func GetInstance() service.Watch {
+	return watchService
+}
+
+// Changes compares two versions of an ad. Only the price and the ad leaving
+// the market are interesting to the ones who bookmarked it.
+func Changes(before models.Ad, after models.Ad) []Change {
+	var changes []Change
+	if before.Price != after.Price {
+		changes = append(changes, Change{
+			Kind:    consts.NOTIFICATION_BOOKMARK_PRICE,
+			Message: fmt.Sprintf("The price of bookmarked ad %d changed from %d to %d", after.ID, before.Price, after.Price),
+		})
+	}
+	if change, ok := statusChange(after.ID, consts.AdStatus(before.Status), consts.AdStatus(after.Status)); ok {
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func statusChange(adID uint, from consts.AdStatus, to consts.AdStatus) (Change, bool) {
+	if from == to {
+		return Change{}, false
+	}
+	switch to {
+	case consts.SOLD:
+		return Change{
+			Kind:    consts.NOTIFICATION_BOOKMARK_SOLD,
+			Message: fmt.Sprintf("Bookmarked ad %d was sold", adID),
+		}, true
+	case consts.RESERVED:
+		return Change{
+			Kind:    consts.NOTIFICATION_BOOKMARK_RESERVED,
+			Message: fmt.Sprintf("Bookmarked ad %d was reserved for a buyer", adID),
+		}, true
+	case consts.INACTIVE, consts.HIDDEN:
+		// a hidden ad that is then deactivated already left the market
+		if from == consts.HIDDEN {
+			return Change{}, false
+		}
+		return Change{
+			Kind:    consts.NOTIFICATION_BOOKMARK_DEACTIVATED,
+			Message: fmt.Sprintf("Bookmarked ad %d was deactivated", adID),
+		}, true
+	}
+	return Change{}, false
+}
+
+// AdChanged notifies about every change between the two versions of the ad.
+func (w *Watch) AdChanged(ctx context.Context, before models.Ad, after models.Ad) error {
+	return w.notify(ctx, after.ID, Changes(before, after))
+}
+
+func (w *Watch) StatusChanged(ctx context.Context, adID uint, from consts.AdStatus, to consts.AdStatus) error {
+	change, ok := statusChange(adID, from, to)
+	if !ok {
+		return nil
+	}
+	return w.notify(ctx, adID, []Change{change})
+}
+
+func (w *Watch) ExpertReportCompleted(ctx context.Context, adID uint) error {
+	return w.notify(ctx, adID, []Change{{
+		Kind:    consts.NOTIFICATION_BOOKMARK_EXPERT_REPORT,
+		Message: fmt.Sprintf("The expert report of bookmarked ad %d is ready", adID),
+	}})
+}
+
+// notify sends every change to every watcher of the ad, a failed
+// notification doesn't stop the others.
+func (w *Watch) notify(ctx context.Context, adID uint, changes []Change) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	watchers, err := w.bookmarks.Watchers(ctx, adID)
+	if err != nil {
+		return err
+	}
+
+	var failed error
+	for _, userID := range watchers {
+		for _, change := range changes {
+			err := w.notifications.Create(ctx, models.Notification{
+				UserID:  userID,
+				AdsID:   adID,
+				Kind:    change.Kind,
+				Message: change.Message,
+			})
+			if err != nil {
+				failed = err
+			}
+		}
+	}
+	return failed
+}
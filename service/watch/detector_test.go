@@ -0,0 +1,33 @@
+package watch_service
+
+import (
+	"Airplane-Divar/consts"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusChange(t *testing.T) {
+	testcases := []struct {
+		name string
+		from consts.AdStatus
+		to   consts.AdStatus
+		kind string
+	}{
+		{"sold", consts.RESERVED, consts.SOLD, consts.NOTIFICATION_BOOKMARK_SOLD},
+		{"reserved for a buyer", consts.ACTIVE, consts.RESERVED, consts.NOTIFICATION_BOOKMARK_RESERVED},
+		{"deactivated", consts.ACTIVE, consts.INACTIVE, consts.NOTIFICATION_BOOKMARK_DEACTIVATED},
+		{"hidden by reports", consts.ACTIVE, consts.HIDDEN, consts.NOTIFICATION_BOOKMARK_DEACTIVATED},
+		{"hidden then deactivated", consts.HIDDEN, consts.INACTIVE, ""},
+		{"back on the market", consts.HIDDEN, consts.ACTIVE, ""},
+		{"unchanged", consts.INACTIVE, consts.INACTIVE, ""},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			change, ok := statusChange(7, tc.from, tc.to)
+			assert.Equal(t, tc.kind != "", ok)
+			assert.Equal(t, tc.kind, change.Kind)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+// Package ical writes iCalendar (RFC 5545) feeds calendar clients like
+// Outlook subscribe to. Times are written in UTC.
+package ical
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	timeFormat = "20060102T150405Z"
+	lineLength = 75
+)
+
+// Event is an entry of a calendar. UID stays the same for the life of the
+// event and Sequence grows with every change, clients use both to update
+// the entry they already have. Cancelled events are kept in the feed so
+// clients remove them.
+type Event struct {
+	UID         string
+	Sequence    int
+	Stamp       time.Time
+	Start       time.Time
+	End         time.Time
+	Summary     string
+	Description string
+	Location    string
+	Cancelled   bool
+}
+
+// Render writes the calendar with its events.
+func Render(name string, events []Event) []byte {
+	var b bytes.Buffer
+	line(&b, "BEGIN:VCALENDAR")
+	line(&b, "VERSION:2.0")
+	line(&b, "PRODID:-//Airplane-Divar//Inspections//EN")
+	line(&b, "CALSCALE:GREGORIAN")
+	line(&b, "METHOD:PUBLISH")
+	line(&b, "X-WR-CALNAME:"+escape(name))
+	for _, event := range events {
+		status := "CONFIRMED"
+		if event.Cancelled {
+			status = "CANCELLED"
+		}
+		line(&b, "BEGIN:VEVENT")
+		line(&b, "UID:"+escape(event.UID))
+		line(&b, fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+		line(&b, "DTSTAMP:"+event.Stamp.UTC().Format(timeFormat))
+		line(&b, "DTSTART:"+event.Start.UTC().Format(timeFormat))
+		line(&b, "DTEND:"+event.End.UTC().Format(timeFormat))
+		line(&b, "SUMMARY:"+escape(event.Summary))
+		if event.Description != "" {
+			line(&b, "DESCRIPTION:"+escape(event.Description))
+		}
+		if event.Location != "" {
+			line(&b, "LOCATION:"+escape(event.Location))
+		}
+		line(&b, "STATUS:"+status)
+		line(&b, "END:VEVENT")
+	}
+	line(&b, "END:VCALENDAR")
+	return b.Bytes()
+}
+
+// line writes a content line ending with CRLF, folding it into lines of at
+// most 75 octets without splitting a character.
+func line(b *bytes.Buffer, text string) {
+	limit := lineLength
+	for len(text) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		b.WriteString(text[:cut])
+		b.WriteString("\r\n ")
+		text = text[cut:]
+		// the leading space of a continuation counts
+		limit = lineLength - 1
+	}
+	b.WriteString(text)
+	b.WriteString("\r\n")
+}
+
+var escaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+func escape(text string) string {
+	return escaper.Replace(text)
+}
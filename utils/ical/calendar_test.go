@@ -0,0 +1,126 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var golden = strings.Join([]string{
+	`BEGIN:VCALENDAR`,
+	`VERSION:2.0`,
+	`PRODID:-//Airplane-Divar//Inspections//EN`,
+	`CALSCALE:GREGORIAN`,
+	`METHOD:PUBLISH`,
+	`X-WR-CALNAME:airline\, inspections`,
+	`BEGIN:VEVENT`,
+	`UID:appointment-1@airplane-divar`,
+	`SEQUENCE:2`,
+	`DTSTAMP:20240301T093000Z`,
+	`DTSTART:20240305T063000Z`,
+	`DTEND:20240305T083000Z`,
+	`SUMMARY:Inspection of A320\, low hours\; fresh paint`,
+	`DESCRIPTION:Expert check request #12 with expert ali\nBring the logbooks\\m`,
+	` aintenance records\, and the keys\; the hangar closes at 18:00`,
+	`LOCATION:IKA hangar 4\, Tehran`,
+	`STATUS:CONFIRMED`,
+	`END:VEVENT`,
+	`BEGIN:VEVENT`,
+	`UID:appointment-2@airplane-divar`,
+	`SEQUENCE:1`,
+	`DTSTAMP:20240301T093000Z`,
+	`DTSTART:20240306T080000Z`,
+	`DTEND:20240306T090000Z`,
+	`SUMMARY:Inspection of Boeing 737 – پرواز تهران به مشهد ب`,
+	` ا هواپیمای بوئینگ ۷۳۷`,
+	`STATUS:CANCELLED`,
+	`END:VEVENT`,
+	`END:VCALENDAR`,
+	``,
+}, "\r\n")
+
+func events() []Event {
+	stamp := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	tehran := time.FixedZone("IRST", 3*3600+1800)
+	return []Event{
+		{
+			// rescheduled twice, in local time
+			UID:         "appointment-1@airplane-divar",
+			Sequence:    2,
+			Stamp:       stamp,
+			Start:       time.Date(2024, 3, 5, 10, 0, 0, 0, tehran),
+			End:         time.Date(2024, 3, 5, 12, 0, 0, 0, tehran),
+			Summary:     "Inspection of A320, low hours; fresh paint",
+			Description: "Expert check request #12 with expert ali\r\nBring the logbooks\\maintenance records, and the keys; the hangar closes at 18:00",
+			Location:    "IKA hangar 4, Tehran",
+		},
+		{
+			// cancelled after it was moved once
+			UID:       "appointment-2@airplane-divar",
+			Sequence:  1,
+			Stamp:     stamp,
+			Start:     time.Date(2024, 3, 6, 8, 0, 0, 0, time.UTC),
+			End:       time.Date(2024, 3, 6, 9, 0, 0, 0, time.UTC),
+			Summary:   "Inspection of Boeing 737 – پرواز تهران به مشهد با هواپیمای بوئینگ ۷۳۷",
+			Cancelled: true,
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	out := string(Render("airline, inspections", events()))
+	assert.Equal(t, golden, out)
+
+	assert.True(t, strings.HasSuffix(out, "\r\n"))
+	assert.NotContains(t, strings.ReplaceAll(out, "\r\n", ""), "\n")
+	assert.NotContains(t, strings.ReplaceAll(out, "\r\n", ""), "\r")
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, "line %q is longer than 75 octets", line)
+		assert.True(t, utf8.ValidString(line), "line %q splits a character", line)
+	}
+
+	// unfolding gives back the escaped values
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	assert.Contains(t, unfolded, "\r\nSUMMARY:Inspection of Boeing 737 – پرواز تهران به مشهد با هواپیمای بوئینگ ۷۳۷\r\n")
+	assert.Contains(t, unfolded, `\nBring the logbooks\\maintenance records\, and the keys\; the hangar`)
+}
+
+func TestRender_Empty(t *testing.T) {
+	out := string(Render("empty", nil))
+	assert.Equal(t, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\nPRODID:-//Airplane-Divar//Inspections//EN\r\n"+
+		"CALSCALE:GREGORIAN\r\nMETHOD:PUBLISH\r\nX-WR-CALNAME:empty\r\nEND:VCALENDAR\r\n", out)
+}
+
+func TestLine(t *testing.T) {
+	testcases := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"short", "SUMMARY:short", []string{"SUMMARY:short"}},
+		{"exactly 75 octets", strings.Repeat("a", 75), []string{strings.Repeat("a", 75)}},
+		{"76 octets", strings.Repeat("a", 76), []string{strings.Repeat("a", 75), " a"}},
+		{
+			"continuations hold 74 octets after the space",
+			strings.Repeat("a", 75+74+1),
+			[]string{strings.Repeat("a", 75), " " + strings.Repeat("a", 74), " a"},
+		},
+		{
+			"characters are not split",
+			strings.Repeat("a", 74) + "é",
+			[]string{strings.Repeat("a", 74), " é"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var b bytes.Buffer
+			line(&b, tc.text)
+			assert.Equal(t, strings.Join(tc.want, "\r\n")+"\r\n", b.String())
+		})
+	}
+}
@@ -23,6 +23,14 @@ func Int64(param string) int64 {
 	return val
 }
 
+func Float64(param string) float64 {
+	val, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return 0
+	}
+	return val
+}
+
 func Uint(param string) uint {
 	val, err := strconv.ParseUint(param, 10, 32)
 	if err != nil {
@@ -0,0 +1,304 @@
+// Package pdf writes simple printable documents, headings, fields, wrapped
+// text, tables and image thumbnails on A4 pages. It only uses the standard
+// Helvetica fonts, so text is limited to Latin-1 and other characters are
+// printed as '?'.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"strings"
+)
+
+const (
+	pageWidth    = 595.28
+	pageHeight   = 841.89
+	margin       = 50.0
+	contentWidth = pageWidth - 2*margin
+	footerHeight = 30.0
+	lineSpacing  = 1.35
+)
+
+type font int
+
+const (
+	regular font = iota
+	bold
+)
+
+// Document is a PDF being written, top to bottom. Content that doesn't fit
+// on the current page moves to a new one.
+type Document struct {
+	title  string
+	pages  []*bytes.Buffer
+	images [][]byte
+	sizes  []image.Point
+	y      float64
+}
+
+// Figure is an image with a caption under it.
+type Figure struct {
+	Image   image.Image
+	Caption string
+}
+
+func New(title string) *Document {
+	d := &Document{title: title}
+	d.newPage()
+	return d
+}
+
+// Title is the big heading at the top of the document.
+func (d *Document) Title(text string) {
+	d.lines(bold, 18, margin, contentWidth, text)
+	d.y -= 4
+	d.rule()
+	d.y -= 10
+}
+
+func (d *Document) Heading(text string) {
+	d.space(13*lineSpacing + 30)
+	d.y -= 12
+	d.lines(bold, 13, margin, contentWidth, text)
+	d.y -= 4
+}
+
+func (d *Document) Paragraph(text string) {
+	d.lines(regular, 10, margin, contentWidth, text)
+	d.y -= 4
+}
+
+// Field prints a label and its value on the same line.
+func (d *Document) Field(label string, value string) {
+	const labelWidth = 130.0
+	d.space(10 * lineSpacing)
+	d.text(bold, 10, margin, d.y-10, encode(label))
+	d.lines(regular, 10, margin+labelWidth, contentWidth-labelWidth, value)
+}
+
+// Table prints rows under a header, widths are the share of the page width
+// each column gets. Cells wrap, a row is as tall as its tallest cell.
+func (d *Document) Table(widths []float64, header []string, rows [][]string) {
+	columns := make([]float64, len(widths))
+	var total float64
+	for _, w := range widths {
+		total += w
+	}
+	for i, w := range widths {
+		columns[i] = contentWidth * w / total
+	}
+
+	d.row(bold, columns, header)
+	d.rule()
+	for _, row := range rows {
+		d.row(regular, columns, row)
+	}
+	d.y -= 6
+}
+
+// Figures prints images in a grid of three columns, each scaled to fit its
+// cell.
+func (d *Document) Figures(figures []Figure) error {
+	const (
+		perRow  = 3
+		gap     = 12.0
+		boxH    = 110.0
+		caption = 8.0
+	)
+	boxW := (contentWidth - gap*(perRow-1)) / perRow
+
+	for start := 0; start < len(figures); start += perRow {
+		end := start + perRow
+		if end > len(figures) {
+			end = len(figures)
+		}
+		d.space(boxH + 3*caption*lineSpacing)
+		top := d.y
+		bottom := top
+		for i, figure := range figures[start:end] {
+			x := margin + float64(i)*(boxW+gap)
+			name, err := d.addImage(figure.Image)
+			if err != nil {
+				return err
+			}
+			size := figure.Image.Bounds().Size()
+			w, h := fit(float64(size.X), float64(size.Y), boxW, boxH)
+			fmt.Fprintf(d.page(), "q %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q\n", w, h, x+(boxW-w)/2, top-boxH+(boxH-h)/2, name)
+
+			y := top - boxH - 2
+			lines := wrap(encode(figure.Caption), regular, caption, boxW)
+			if len(lines) > 2 {
+				lines = lines[:2]
+			}
+			for _, line := range lines {
+				y -= caption * lineSpacing
+				d.text(regular, caption, x, y+2, line)
+			}
+			if y < bottom {
+				bottom = y
+			}
+		}
+		d.y = bottom - gap
+	}
+	return nil
+}
+
+// Bytes renders the document, every page gets a footer with the title and
+// the page number.
+func (d *Document) Bytes() ([]byte, error) {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body []byte) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n", len(offsets))
+		out.Write(body)
+		out.WriteString("\nendobj\n")
+	}
+	stream := func(dict string, data []byte) []byte {
+		return []byte(fmt.Sprintf("<< %s /Length %d >>\nstream\n%s\nendstream", dict, len(data), data))
+	}
+
+	const fixed = 5 // catalog, pages, two fonts and info
+	firstPage := fixed + 1 + len(d.images)
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	object([]byte("<< /Type /Catalog /Pages 2 0 R >>"))
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object([]byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages))))
+	object([]byte("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"))
+	object([]byte("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>"))
+	object([]byte(fmt.Sprintf("<< /Title (%s) /Producer (Airplane-Divar) >>", escape(encode(d.title)))))
+
+	xobjects := make([]string, len(d.images))
+	for i, data := range d.images {
+		xobjects[i] = fmt.Sprintf("/Im%d %d 0 R", i+1, fixed+1+i)
+		dict := fmt.Sprintf(
+			"/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /DCTDecode",
+			d.sizes[i].X, d.sizes[i].Y,
+		)
+		object(stream(dict, data))
+	}
+	resources := fmt.Sprintf("<< /Font << /F1 3 0 R /F2 4 0 R >> /XObject << %s >> >>", strings.Join(xobjects, " "))
+
+	for i, page := range d.pages {
+		var content bytes.Buffer
+		content.Write(page.Bytes())
+		footer := encode(fmt.Sprintf("%s - Page %d of %d", d.title, i+1, len(d.pages)))
+		fmt.Fprintf(&content, "BT /F1 8 Tf %.2f %.2f Td (%s) Tj ET\n", margin, footerHeight-10, escape(footer))
+
+		var compressed bytes.Buffer
+		w := zlib.NewWriter(&compressed)
+		if _, err := w.Write(content.Bytes()); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+
+		object([]byte(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources %s /Contents %d 0 R >>",
+			pageWidth, pageHeight, resources, firstPage+2*i+1,
+		)))
+		object(stream("/Filter /FlateDecode", compressed.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes(), nil
+}
+
+func (d *Document) newPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+	d.y = pageHeight - margin
+}
+
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// space starts a new page unless height still fits on this one.
+func (d *Document) space(height float64) {
+	if d.y-height < margin+footerHeight {
+		d.newPage()
+	}
+}
+
+// lines prints wrapped text in a column and moves down past it.
+func (d *Document) lines(f font, size float64, x float64, width float64, text string) {
+	for _, line := range wrap(encode(text), f, size, width) {
+		d.space(size * lineSpacing)
+		d.y -= size * lineSpacing
+		d.text(f, size, x, d.y+size*(lineSpacing-1), line)
+	}
+}
+
+func (d *Document) row(f font, columns []float64, cells []string) {
+	const size, padding = 9.0, 4.0
+	wrapped := make([][]string, len(columns))
+	height := 0
+	for i := range columns {
+		if i < len(cells) {
+			wrapped[i] = wrap(encode(cells[i]), f, size, columns[i]-padding)
+		}
+		if len(wrapped[i]) > height {
+			height = len(wrapped[i])
+		}
+	}
+	if height == 0 {
+		height = 1
+	}
+
+	d.space(float64(height)*size*lineSpacing + padding)
+	x := margin
+	for i, lines := range wrapped {
+		for j, line := range lines {
+			d.text(f, size, x, d.y-float64(j+1)*size*lineSpacing+size*(lineSpacing-1), line)
+		}
+		x += columns[i]
+	}
+	d.y -= float64(height)*size*lineSpacing + padding
+}
+
+func (d *Document) rule() {
+	fmt.Fprintf(d.page(), "0.6 G 0.5 w %.2f %.2f m %.2f %.2f l S 0 G\n", margin, d.y, pageWidth-margin, d.y)
+	d.y -= 2
+}
+
+// text prints an encoded line with its baseline at y.
+func (d *Document) text(f font, size float64, x float64, y float64, line string) {
+	fmt.Fprintf(d.page(), "BT /F%d %.2f Tf %.2f %.2f Td (%s) Tj ET\n", f+1, size, x, y, escape(line))
+}
+
+func (d *Document) addImage(img image.Image) (string, error) {
+	var data bytes.Buffer
+	if err := jpeg.Encode(&data, img, &jpeg.Options{Quality: 80}); err != nil {
+		return "", err
+	}
+	d.images = append(d.images, data.Bytes())
+	d.sizes = append(d.sizes, img.Bounds().Size())
+	return fmt.Sprintf("Im%d", len(d.images)), nil
+}
+
+// fit scales a w by h box into maxW by maxH keeping its aspect ratio.
+func fit(w float64, h float64, maxW float64, maxH float64) (float64, float64) {
+	if w <= 0 || h <= 0 {
+		return maxW, maxH
+	}
+	scale := maxW / w
+	if h*scale > maxH {
+		scale = maxH / h
+	}
+	return w * scale, h * scale
+}
@@ -0,0 +1,272 @@
+package pdf
+
+import (
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func expertReport(items int) ExpertReport {
+	checklist := make([]models.InspectionItem, 0, items)
+	for i := 0; i < items; i++ {
+		checklist = append(checklist, models.InspectionItem{
+			TemplateItemID: uint(i + 1),
+			Section:        consts.INSPECTION_SECTIONS[i%len(consts.INSPECTION_SECTIONS)],
+			Label:          fmt.Sprintf("Item %d", i+1),
+			Position:       i,
+			Rating:         4,
+			Severity:       "minor",
+			Finding:        "Paint chipped around the access panel, no structural damage found.",
+		})
+	}
+	itemID := uint(1)
+	return ExpertReport{
+		Report: models.ExpertAds{
+			ID:     12,
+			Report: `Wing root (left) checked \ spar intact`,
+			Grade:  "B",
+			Score:  81.5,
+			Status: consts.DONE_STATUS,
+			Expert: models.User{ID: 2, Username: "expert"},
+			Ads: models.Ad{
+				ID:            3,
+				Subject:       "Café ✈ تهران",
+				AirplaneModel: "A320",
+				PlaneAge:      19,
+				FlyTime:       42000,
+				Category:      models.Category{Name: "big-passenger"},
+			},
+			Checklist: checklist,
+		},
+		Evidence: []Evidence{
+			{
+				Attachment: models.ExpertAttachment{FileName: "wing.png", ContentType: "image/png", Size: 2048, TemplateItemID: &itemID},
+				Item:       "Item 1",
+				Thumbnail:  image.NewRGBA(image.Rect(0, 0, 40, 20)),
+			},
+			{
+				Attachment: models.ExpertAttachment{FileName: "logbook.pdf", ContentType: "application/pdf", Size: 3 << 20},
+			},
+		},
+		Certificate: &models.ExpertCertificate{Revision: 2, KeyID: "0123456789abcdef", IssuedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		Code:        "ABCD-EF01-2345-6789",
+		VerifyURL:   "https://example.com/verify/expert-report/12?revision=2",
+	}
+}
+
+func TestRenderExpertReport(t *testing.T) {
+	document, err := RenderExpertReport(expertReport(3))
+	assert.NoError(t, err)
+
+	assertStructure(t, document)
+	assert.Equal(t, 2, pages(t, document))
+
+	text := contents(t, document)
+	assert.Contains(t, text, "(Expert Report #12)")
+	assert.Contains(t, text, `(Wing root \(left\) checked \\ spar intact)`)
+	assert.Contains(t, text, "(#3 Caf\xe9 ? ?????)")
+	assert.Contains(t, text, "(Item 1: wing.png)")
+	assert.Contains(t, text, "(logbook.pdf \\(application/pdf, 3.0 MB\\))")
+	assert.Contains(t, text, "(ABCD-EF01-2345-6789)")
+	assert.Contains(t, text, "(Expert Report #12 - Page 1 of 2)")
+	assert.Contains(t, text, "(Expert Report #12 - Page 2 of 2)")
+	assert.Contains(t, string(document), "/Subtype /Image /Width 40 /Height 20")
+}
+
+func TestRenderExpertReport_Pages(t *testing.T) {
+	document, err := RenderExpertReport(expertReport(80))
+	assert.NoError(t, err)
+
+	assertStructure(t, document)
+	count := pages(t, document)
+	assert.Greater(t, count, 1)
+
+	text := contents(t, document)
+	for page := 1; page <= count; page++ {
+		assert.Contains(t, text, fmt.Sprintf("(Expert Report #12 - Page %d of %d)", page, count))
+	}
+	assert.Contains(t, text, "(Item 80)")
+}
+
+func TestRenderExpertReport_Unsigned(t *testing.T) {
+	data := expertReport(0)
+	data.Certificate = nil
+	data.Evidence = nil
+
+	document, err := RenderExpertReport(data)
+	assert.NoError(t, err)
+
+	assertStructure(t, document)
+	assert.NotContains(t, string(document), "/Subtype /Image")
+	assert.Contains(t, contents(t, document), "(This report has no certificate, it can't be verified.)")
+}
+
+func TestRenderRepairSummary(t *testing.T) {
+	document, err := RenderRepairSummary(models.RepairRequest{
+		ID:        5,
+		Status:    consts.DONE_STATUS,
+		CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		User:      models.User{Username: "airline (main)"},
+		Ads:       models.Ad{ID: 3, Subject: "Retired 737"},
+	})
+	assert.NoError(t, err)
+
+	assertStructure(t, document)
+	assert.Equal(t, 1, pages(t, document))
+
+	text := contents(t, document)
+	assert.Contains(t, text, "(Repair Summary #5)")
+	assert.Contains(t, text, `(airline \(main\))`)
+	assert.Contains(t, text, "(2026-01-02 03:04 UTC)")
+	assert.Contains(t, text, "(Repair Summary #5 - Page 1 of 1)")
+}
+
+func TestEncode(t *testing.T) {
+	assert.Equal(t, "Caf\xe9 na\xefve", encode("Café naïve"))
+	assert.Equal(t, "? ??? ?", encode("✈ شیر 日"))
+	assert.Equal(t, "a b\nc", encode("a\tb\r\nc"))
+	assert.Equal(t, `\(a\) \\ b`, escape(`(a) \ b`))
+}
+
+func TestWrap(t *testing.T) {
+	lines := wrap("the quick brown fox jumps over the lazy dog", regular, 10, 60)
+	assert.Greater(t, len(lines), 1)
+	for _, line := range lines {
+		assert.LessOrEqual(t, textWidth(line, regular, 10), 60.0)
+	}
+	assert.Equal(t, "the quick brown fox jumps over the lazy dog", strings.Join(lines, " "))
+
+	long := strings.Repeat("W", 40)
+	lines = wrap(long, bold, 10, 50)
+	assert.Equal(t, long, strings.Join(lines, ""))
+	for _, line := range lines {
+		assert.LessOrEqual(t, textWidth(line, bold, 10), 50.0)
+	}
+
+	assert.Equal(t, []string{""}, wrap("", regular, 10, 50))
+	assert.Equal(t, []string{"a", "", "b"}, wrap("a\n\nb", regular, 10, 50))
+}
+
+func TestThumbnail(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			src.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	assert.NoError(t, png.Encode(&encoded, src))
+
+	thumbnail, err := Thumbnail(bytes.NewReader(encoded.Bytes()), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Pt(100, 50), thumbnail.Bounds().Size())
+	r, g, b, _ := thumbnail.At(50, 25).RGBA()
+	assert.Equal(t, []uint32{0xffff, 0, 0}, []uint32{r, g, b})
+
+	// transparent pixels turn white
+	clear := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	encoded.Reset()
+	assert.NoError(t, png.Encode(&encoded, clear))
+	thumbnail, err = Thumbnail(bytes.NewReader(encoded.Bytes()), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, image.Pt(10, 10), thumbnail.Bounds().Size())
+	r, g, b, _ = thumbnail.At(5, 5).RGBA()
+	assert.Equal(t, []uint32{0xffff, 0xffff, 0xffff}, []uint32{r, g, b})
+
+	_, err = Thumbnail(strings.NewReader("not an image"), 100)
+	assert.Error(t, err)
+}
+
+// assertStructure checks the header, that every xref entry points at the
+// object it numbers and that the trailer points at the xref table.
+func assertStructure(t *testing.T, document []byte) {
+	t.Helper()
+	assert.True(t, bytes.HasPrefix(document, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(document, []byte("%%EOF\n")))
+
+	match := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(document)
+	if !assert.NotNil(t, match, "no startxref") {
+		return
+	}
+	xref, _ := strconv.Atoi(string(match[1]))
+	if !assert.True(t, bytes.HasPrefix(document[xref:], []byte("xref\n")), "startxref does not point at the xref table") {
+		return
+	}
+
+	table := string(document[xref:])
+	lines := strings.Split(table, "\n")
+	var first, size int
+	_, err := fmt.Sscanf(lines[1], "%d %d", &first, &size)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, first)
+	assert.Equal(t, "0000000000 65535 f ", lines[2])
+	assert.Contains(t, table, fmt.Sprintf("/Size %d ", size))
+
+	for object := 1; object < size; object++ {
+		entry := lines[2+object]
+		if !assert.Len(t, entry, 19) {
+			continue
+		}
+		offset, err := strconv.Atoi(entry[:10])
+		assert.NoError(t, err)
+		assert.Equal(t, " 00000 n ", entry[10:])
+		assert.True(t,
+			bytes.HasPrefix(document[offset:], []byte(fmt.Sprintf("%d 0 obj\n", object))),
+			"xref entry of object %d points at %q", object, document[offset:min(offset+12, len(document))],
+		)
+	}
+}
+
+// pages checks the page tree counts every page object and returns the count.
+func pages(t *testing.T, document []byte) int {
+	t.Helper()
+	match := regexp.MustCompile(`/Type /Pages /Kids \[([^\]]*)\] /Count (\d+)`).FindSubmatch(document)
+	if !assert.NotNil(t, match) {
+		return 0
+	}
+	count, _ := strconv.Atoi(string(match[2]))
+	assert.Equal(t, count, bytes.Count(document, []byte("/Type /Page /Parent 2 0 R")))
+	assert.Equal(t, count, len(strings.Fields(string(match[1])))/3)
+	return count
+}
+
+// contents inflates the content streams of all pages.
+func contents(t *testing.T, document []byte) string {
+	t.Helper()
+	var text strings.Builder
+	header := regexp.MustCompile(`<< /Filter /FlateDecode /Length (\d+) >>\nstream\n`)
+	for _, loc := range header.FindAllSubmatchIndex(document, -1) {
+		length, _ := strconv.Atoi(string(document[loc[2]:loc[3]]))
+		data := document[loc[1] : loc[1]+length]
+		assert.True(t, bytes.HasPrefix(document[loc[1]+length:], []byte("\nendstream")), "stream length is off")
+
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if !assert.NoError(t, err) {
+			continue
+		}
+		inflated, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		text.Write(inflated)
+	}
+	return text.String()
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
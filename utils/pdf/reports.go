@@ -0,0 +1,156 @@
+package pdf
+
+import (
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"fmt"
+	"image"
+	"strings"
+	"time"
+)
+
+const dateFormat = "2006-01-02 15:04 MST"
+
+// Evidence is an attachment of an expert report. Item is the label of the
+// checklist item it backs, Thumbnail is nil for documents and for images
+// that can't be previewed.
+type Evidence struct {
+	Attachment models.ExpertAttachment
+	Item       string
+	Thumbnail  image.Image
+}
+
+// ExpertReport is everything printed on an expert report. Certificate is nil
+// when the report wasn't signed.
+type ExpertReport struct {
+	Report      models.ExpertAds
+	Evidence    []Evidence
+	Certificate *models.ExpertCertificate
+	Code        string
+	VerifyURL   string
+}
+
+func RenderExpertReport(data ExpertReport) ([]byte, error) {
+	report := data.Report
+	d := New(fmt.Sprintf("Expert Report #%d", report.ID))
+	d.Title(fmt.Sprintf("Expert Report #%d", report.ID))
+
+	airplane(d, report.Ads)
+
+	d.Heading("Inspection")
+	if report.Grade != "" {
+		d.Field("Grade", report.Grade)
+		d.Field("Score", fmt.Sprintf("%.1f / 100", report.Score))
+	}
+	d.Field("Status", string(report.Status))
+	if report.Report != "" {
+		d.Field("Report", report.Report)
+	}
+
+	for _, section := range consts.INSPECTION_SECTIONS {
+		var rows [][]string
+		for _, item := range report.Checklist {
+			if item.Section == section {
+				rows = append(rows, []string{
+					item.Label,
+					fmt.Sprintf("%d / %d", item.Rating, consts.INSPECTION_MAX_RATING),
+					item.Severity,
+					item.Finding,
+				})
+			}
+		}
+		if len(rows) > 0 {
+			d.Heading(humanize(section))
+			d.Table([]float64{3, 1, 1, 4}, []string{"Item", "Rating", "Severity", "Finding"}, rows)
+		}
+	}
+
+	if len(data.Evidence) > 0 {
+		d.Heading("Evidence")
+		var figures []Figure
+		for _, evidence := range data.Evidence {
+			caption := evidence.Attachment.FileName
+			if evidence.Item != "" {
+				caption = evidence.Item + ": " + caption
+			}
+			if evidence.Thumbnail != nil {
+				figures = append(figures, Figure{Image: evidence.Thumbnail, Caption: caption})
+			} else {
+				d.Field("Document", fmt.Sprintf("%s (%s, %s)", caption, evidence.Attachment.ContentType, fileSize(evidence.Attachment.Size)))
+			}
+		}
+		if err := d.Figures(figures); err != nil {
+			return nil, err
+		}
+	}
+
+	d.Heading("Expert")
+	d.Field("Name", report.Expert.Username)
+	d.Field("Expert ID", fmt.Sprint(report.Expert.ID))
+
+	d.Heading("Verification")
+	if data.Certificate == nil {
+		d.Paragraph("This report has no certificate, it can't be verified.")
+	} else {
+		d.Field("Verification code", data.Code)
+		d.Field("Revision", fmt.Sprint(data.Certificate.Revision))
+		d.Field("Issued at", data.Certificate.IssuedAt.UTC().Format(dateFormat))
+		d.Field("Signing key", data.Certificate.KeyID)
+		d.Field("Verify at", data.VerifyURL)
+	}
+
+	return d.Bytes()
+}
+
+func RenderRepairSummary(request models.RepairRequest) ([]byte, error) {
+	d := New(fmt.Sprintf("Repair Summary #%d", request.ID))
+	d.Title(fmt.Sprintf("Repair Summary #%d", request.ID))
+
+	airplane(d, request.Ads)
+
+	d.Heading("Repair")
+	d.Field("Status", string(request.Status))
+	d.Field("Requested by", request.User.Username)
+	d.Field("Requested at", request.CreatedAt.UTC().Format(dateFormat))
+	d.Field("Printed at", time.Now().UTC().Format(dateFormat))
+
+	return d.Bytes()
+}
+
+func airplane(d *Document, ad models.Ad) {
+	d.Heading("Airplane")
+	d.Field("Ad", fmt.Sprintf("#%d %s", ad.ID, ad.Subject))
+	if ad.Category.Name != "" {
+		d.Field("Category", ad.Category.Name)
+	}
+	if ad.AirplaneModel != "" {
+		d.Field("Model", ad.AirplaneModel)
+	}
+	d.Field("Age", fmt.Sprintf("%d years", ad.PlaneAge))
+	d.Field("Flight time", fmt.Sprintf("%d hours", ad.FlyTime))
+	if ad.AirportCode != "" {
+		d.Field("Airport", ad.AirportCode)
+	}
+	for _, attribute := range ad.Attributes {
+		value := attribute.Value
+		if attribute.Attribute.Unit != "" {
+			value += " " + attribute.Attribute.Unit
+		}
+		d.Field(humanize(attribute.Attribute.Name), value)
+	}
+}
+
+func humanize(name string) string {
+	name = strings.ReplaceAll(name, "_", " ")
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func fileSize(size int64) string {
+	if size < 1<<20 {
+		return fmt.Sprintf("%d KB", (size+1023)>>10)
+	}
+	return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+}
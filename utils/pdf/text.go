@@ -0,0 +1,97 @@
+package pdf
+
+import "strings"
+
+// encode turns text into the WinAnsi bytes the standard fonts print,
+// characters outside Latin-1 become '?'.
+func encode(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			b.WriteByte('\n')
+		case r == '\t':
+			b.WriteByte(' ')
+		case r >= 32 && r <= 126, r >= 160 && r <= 255:
+			b.WriteByte(byte(r))
+		case r == '\r':
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+func escape(line string) string {
+	return strings.NewReplacer(`\`, `\\`, `(`, `\(`, `)`, `\)`).Replace(line)
+}
+
+// wrap breaks encoded text into lines no wider than width, words longer
+// than a line are cut. There is always at least one line.
+func wrap(text string, f font, size float64, width float64) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			candidate := word
+			if line != "" {
+				candidate = line + " " + word
+			}
+			if textWidth(candidate, f, size) <= width {
+				line = candidate
+				continue
+			}
+			if line != "" {
+				lines = append(lines, line)
+			}
+			for textWidth(word, f, size) > width && len(word) > 1 {
+				cut := len(word) - 1
+				for cut > 1 && textWidth(word[:cut], f, size) > width {
+					cut--
+				}
+				lines = append(lines, word[:cut])
+				word = word[cut:]
+			}
+			line = word
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func textWidth(text string, f font, size float64) float64 {
+	table := helvetica
+	if f == bold {
+		table = helveticaBold
+	}
+	var units int
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		if c >= 32 && c <= 126 {
+			units += table[c-32]
+		} else {
+			units += 556
+		}
+	}
+	return float64(units) * size / 1000
+}
+
+// Glyph widths of the printable ASCII characters in thousandths of the font
+// size, from the Adobe font metrics.
+var helvetica = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+var helveticaBold = [95]int{
+	278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 333, 333, 584, 584, 584, 611,
+	975, 722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 333, 278, 333, 584, 556,
+	333, 556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889, 611, 611,
+	611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500, 389, 280, 389, 584,
+}
@@ -0,0 +1,70 @@
+package pdf
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+)
+
+// maxPixels keeps a small file claiming huge dimensions from being decoded.
+const maxPixels = 50 << 20
+
+var ErrImageTooLarge = errors.New("image is too large to preview")
+
+// Thumbnail decodes a JPEG or PNG image and scales it down to fit a size by
+// size square, each pixel averages a few samples of the original.
+func Thumbnail(r io.Reader, size int) (image.Image, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, ErrImageTooLarge
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	w, h := fit(float64(bounds.Dx()), float64(bounds.Dy()), float64(size), float64(size))
+	if bounds.Dx() <= size && bounds.Dy() <= size {
+		w, h = float64(bounds.Dx()), float64(bounds.Dy())
+	}
+	dw, dh := max(int(w), 1), max(int(h), 1)
+
+	const samples = 4
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var r, g, b, n uint32
+			for sy := 0; sy < samples; sy++ {
+				for sx := 0; sx < samples; sx++ {
+					px := bounds.Min.X + (x*samples+sx)*bounds.Dx()/(dw*samples)
+					py := bounds.Min.Y + (y*samples+sy)*bounds.Dy()/(dh*samples)
+					// transparent pixels are printed over white paper
+					cr, cg, cb, ca := src.At(px, py).RGBA()
+					white := 0xffff - ca
+					r, g, b, n = r+cr+white, g+cg+white, b+cb+white, n+1
+				}
+			}
+			dst.Set(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: 0xffff})
+		}
+	}
+	return dst, nil
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
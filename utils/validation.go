@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"Airplane-Divar/apperror"
+	"Airplane-Divar/consts"
+	"Airplane-Divar/models"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ValidationErrors collects every problem of a request body, so clients can
+// show them all at once.
+type ValidationErrors []models.FieldError
+
+func (v *ValidationErrors) Add(field string, code string, message string) {
+	*v = append(*v, models.FieldError{Field: field, Code: code, Message: message})
+}
+
+// Merge adds the errors of other, except for fields that already failed.
+func (v *ValidationErrors) Merge(other ValidationErrors) {
+	for _, err := range other {
+		if !v.Has(err.Field) {
+			*v = append(*v, err)
+		}
+	}
+}
+
+func (v ValidationErrors) Has(field string) bool {
+	for _, err := range v {
+		if err.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func (v ValidationErrors) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, err := range v {
+		messages = append(messages, err.Message)
+	}
+	return strings.Join(messages, " ")
+}
+
+// Err is the validation error of the API, nil when nothing failed.
+func (v ValidationErrors) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return apperror.Validation(v)
+}
+
+// BindJSON decodes a JSON object into the struct v points to one field at a
+// time, so a mistyped field doesn't hide the others. Fields tagged
+// `validate:"required"` must be given, not null and, for strings, not empty.
+func BindJSON(r io.Reader, v interface{}) ValidationErrors {
+	var errs ValidationErrors
+
+	raw := map[string]json.RawMessage{}
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		errs.Add("body", consts.VALIDATION_INVALID_JSON, "Invalid JSON")
+		return errs
+	}
+
+	rv := reflect.ValueOf(v).Elem()
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		value, ok := raw[name]
+		if !ok || string(value) == "null" {
+			if field.Tag.Get("validate") == "required" {
+				errs.Add(name, consts.VALIDATION_REQUIRED, fmt.Sprintf("%s is required !", name))
+			}
+			continue
+		}
+
+		if err := json.Unmarshal(value, rv.Field(i).Addr().Interface()); err != nil {
+			errs.Add(name, consts.VALIDATION_TYPE, fmt.Sprintf("%s should be %s !", name, typeName(field.Type)))
+		} else if field.Tag.Get("validate") == "required" && field.Type.Kind() == reflect.String && rv.Field(i).String() == "" {
+			errs.Add(name, consts.VALIDATION_REQUIRED, fmt.Sprintf("%s is required !", name))
+		}
+	}
+
+	return errs
+}
+
+func typeName(t reflect.Type) string {
+	if t == reflect.TypeOf(time.Time{}) {
+		return "an RFC 3339 time"
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeName(t.Elem())
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a positive integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	default:
+		return "an object"
+	}
+}
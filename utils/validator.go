@@ -1,133 +1,341 @@
 package utils
 
 import (
+	"Airplane-Divar/airports"
 	"Airplane-Divar/consts"
 	"Airplane-Divar/models"
-	"errors"
 	"fmt"
-	"math"
 	"net/mail"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 )
 
-func ValidateJsonFormat(jsonBody map[string]interface{}, fields ...string) (string, error) {
-	msg := "OK"
-	for _, field := range fields {
-		if _, ok := jsonBody[field]; !ok {
-			msg = "Input Json doesn't include " + field
-			break
+// ValidateAd checks a new ad against its category and builds it, filling in
+// the optional properties that were left out.
+func ValidateAd(req models.AdRequest, cat models.Category, attributes ...models.CategoryAttribute) (models.Ad, ValidationErrors) {
+	var errs ValidationErrors
+
+	ad := models.Ad{
+		CategoryID:    cat.ID,
+		AirplaneModel: req.AirplaneModel,
+		Price:         req.Price,
+		FlyTime:       req.FlyTime,
+		RepairCheck:   req.RepairCheck,
+		ExpertCheck:   req.ExpertCheck,
+		PlaneAge:      req.PlaneAge,
+		Image:         req.Image,
+		Subject:       req.Subject,
+		Description:   req.Description,
+	}
+
+	if req.PlaneAge > uint(time.Now().Year()-1903) {
+		errs.Add("PlaneAge", consts.VALIDATION_OUT_OF_RANGE, "The year of the invention of the airplane was 1903 !")
+	}
+
+	//fill in optional properties
+	if ad.Image == "" {
+		ad.Image = "https://snipboard.io/d5viVR.jpg"
+	}
+	if ad.Subject == "" {
+		ad.Subject = fmt.Sprintf("%d years old airplane : %s in the %s category", ad.PlaneAge, ad.AirplaneModel, cat.Name)
+	}
+	if ad.Description == "" {
+		ad.Description = fmt.Sprintf("Model : %s | Age : %d | Category : %s | Price : %d | Fly Time : %d | Has Expert Check : %v | Has Repair Check : %v", ad.AirplaneModel, ad.PlaneAge, cat.Name, ad.Price, ad.FlyTime, ad.ExpertCheck, ad.RepairCheck)
+	}
+
+	if req.AirportCode != "" {
+		if airport, ok := airports.Lookup(req.AirportCode); ok {
+			ad.AirportCode = airport.Code
+		} else {
+			errs.Add("AirportCode", consts.VALIDATION_INVALID, "Unknown Airport Code !")
 		}
 	}
-	if msg != "OK" {
-		return msg, errors.New("")
+
+	adAttributes, attrErrs := validateAdAttributes(req.Attributes, cat, attributes)
+	errs = append(errs, attrErrs...)
+	ad.Attributes = adAttributes
+
+	if len(errs) > 0 {
+		return models.Ad{}, errs
 	}
-	return msg, nil
+	return ad, nil
 }
 
-func ValidateAd(jsonBody map[string]interface{}, cat models.Category) (string, models.Ad, error) {
-	msg := "OK"
+// validateAdAttributes checks the optional "Attributes" object of an ad
+// against the attributes its category declares.
+func validateAdAttributes(values map[string]interface{}, cat models.Category, attributes []models.CategoryAttribute) ([]models.AdAttribute, ValidationErrors) {
+	var errs ValidationErrors
 
-	var ad models.Ad
+	declared := map[string]bool{}
+	var adAttributes []models.AdAttribute
+	for _, attr := range attributes {
+		declared[attr.Name] = true
+		field := "Attributes." + attr.Name
 
-	ad.CategoryID = cat.ID
+		raw, ok := values[attr.Name]
+		if !ok {
+			if attr.Required {
+				errs.Add(field, consts.VALIDATION_REQUIRED, fmt.Sprintf("%s attribute is required for %s !", attr.Name, cat.Name))
+			}
+			continue
+		}
 
-	if model, ok := jsonBody["AirplaneModel"].(string); ok {
-		ad.AirplaneModel = model
+		var value string
+		switch attr.Type {
+		case consts.ATTRIBUTE_NUMBER:
+			number, ok := raw.(float64)
+			if !ok {
+				errs.Add(field, consts.VALIDATION_TYPE, fmt.Sprintf("%s attribute should be a number !", attr.Name))
+				continue
+			}
+			value = strconv.FormatFloat(number, 'f', -1, 64)
+		case consts.ATTRIBUTE_BOOLEAN:
+			b, ok := raw.(bool)
+			if !ok {
+				errs.Add(field, consts.VALIDATION_TYPE, fmt.Sprintf("%s attribute should be boolean !", attr.Name))
+				continue
+			}
+			value = strconv.FormatBool(b)
+		default:
+			str, ok := raw.(string)
+			if !ok {
+				errs.Add(field, consts.VALIDATION_TYPE, fmt.Sprintf("%s attribute should be string !", attr.Name))
+				continue
+			}
+			value = str
+		}
+
+		adAttributes = append(adAttributes, models.AdAttribute{AttributeID: attr.ID, Value: value})
+	}
+
+	var unknown []string
+	for name := range values {
+		if !declared[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	for _, name := range unknown {
+		errs.Add("Attributes."+name, consts.VALIDATION_UNKNOWN, fmt.Sprintf("%s is not an attribute of %s !", name, cat.Name))
+	}
+
+	return adAttributes, errs
+}
+
+// ValidateExpertCheck checks an expert's update of a check request, it has to
+// change the status or give the report.
+func ValidateExpertCheck(req models.UpdateExpertCheckRequest) ValidationErrors {
+	var errs ValidationErrors
+	if req.Status == "" && req.Report == "" {
+		errs.Add("status", consts.VALIDATION_REQUIRED, "status or report is required !")
+	} else if req.Status != "" {
+		validateStatus(&errs, req.Status, consts.EXPERT_PENDING_STATUS, consts.IN_PROGRESS_STATUS, consts.DONE_STATUS)
+	}
+	return errs
+}
+
+// ValidateRepairUpdate checks a repair shop's update of a repair request.
+func ValidateRepairUpdate(req models.UpdateRepairRequest) ValidationErrors {
+	var errs ValidationErrors
+	if req.Status == "" {
+		errs.Add("status", consts.VALIDATION_REQUIRED, "status is required !")
 	} else {
-		msg = "Plane Model should be string !"
-		return msg, models.Ad{}, errors.New("")
+		validateStatus(&errs, req.Status, consts.MATIN_PENDING_STATUS, consts.IN_PROGRESS_STATUS, consts.DONE_STATUS)
 	}
+	return errs
+}
 
-	price, ok := jsonBody["Price"].(float64)
-	if !ok {
-		msg = "Price should be a number !"
-		return msg, models.Ad{}, errors.New("")
+// ValidateAdStatus checks an admin's change of an ad status. Reserved, sold
+// and hidden ads are only set by offers, auctions and reports.
+func ValidateAdStatus(req models.UpdateAdsStatusRequest) ValidationErrors {
+	var errs ValidationErrors
+	if req.Status == "" {
+		errs.Add("status", consts.VALIDATION_REQUIRED, "status is required !")
+		return errs
 	}
-	if math.Mod(price, 1) != 0 {
-		msg = "Price should be an integer !"
-		return msg, models.Ad{}, errors.New("")
+	switch req.Status {
+	case consts.ACTIVE, consts.INACTIVE:
+	case consts.RESERVED, consts.SOLD, consts.HIDDEN:
+		errs.Add("status", consts.VALIDATION_NOT_ALLOWED, fmt.Sprintf("%s can't be set by hand !", req.Status))
+	default:
+		errs.Add("status", consts.VALIDATION_INVALID, fmt.Sprintf("%s is not a status !", req.Status))
 	}
-	ad.Price = uint64(price)
+	return errs
+}
 
-	fly, ok := jsonBody["FlyTime"].(float64)
-	if !ok {
-		msg = "fly_time should be a number !"
-		return msg, models.Ad{}, errors.New("")
+// ValidateExpertReview checks an airline's review of an expert.
+func ValidateExpertReview(req models.ExpertReviewRequest) ValidationErrors {
+	var errs ValidationErrors
+	if req.Rating == 0 {
+		errs.Add("rating", consts.VALIDATION_REQUIRED, "rating is required !")
+	} else if req.Rating < consts.REVIEW_MIN_RATING || req.Rating > consts.REVIEW_MAX_RATING {
+		errs.Add("rating", consts.VALIDATION_OUT_OF_RANGE,
+			fmt.Sprintf("rating should be between %d and %d !", consts.REVIEW_MIN_RATING, consts.REVIEW_MAX_RATING))
 	}
-	if math.Mod(fly, 1) != 0 {
-		msg = "fly_time should be an integer !"
-		return msg, models.Ad{}, errors.New("")
+	if len([]rune(req.Comment)) > consts.REVIEW_MAX_COMMENT_LENGTH {
+		errs.Add("comment", consts.VALIDATION_OUT_OF_RANGE,
+			fmt.Sprintf("comment should be at most %d characters !", consts.REVIEW_MAX_COMMENT_LENGTH))
 	}
-	ad.FlyTime = uint(fly)
+	return errs
+}
 
-	if rc, ok := jsonBody["RepairCheck"].(bool); ok {
-		ad.RepairCheck = rc
-	} else {
-		msg = "Repair Check should be boolean !"
-		return msg, models.Ad{}, errors.New("")
+// ValidateSlot checks an availability slot an expert publishes and turns it
+// into a slot in UTC.
+func ValidateSlot(req models.ExpertSlotRequest, now time.Time) (models.ExpertSlot, ValidationErrors) {
+	var errs ValidationErrors
+	location := strings.TrimSpace(req.Location)
+	if location == "" {
+		errs.Add("location", consts.VALIDATION_REQUIRED, "location is required !")
+	} else if len([]rune(location)) > consts.SLOT_LOCATION_MAX_LEN {
+		errs.Add("location", consts.VALIDATION_OUT_OF_RANGE,
+			fmt.Sprintf("location should be at most %d characters !", consts.SLOT_LOCATION_MAX_LEN))
 	}
 
-	if ec, ok := jsonBody["ExpertCheck"].(bool); ok {
-		ad.ExpertCheck = ec
-	} else {
-		msg = "Expert Check should be boolean !"
-		return msg, models.Ad{}, errors.New("")
+	if req.StartsAt.IsZero() {
+		errs.Add("starts_at", consts.VALIDATION_REQUIRED, "starts_at is required !")
+	} else if !req.StartsAt.After(now) {
+		errs.Add("starts_at", consts.VALIDATION_OUT_OF_RANGE, "starts_at should be in the future !")
+	} else if req.StartsAt.After(now.AddDate(0, 0, consts.SLOT_MAX_DAYS_AHEAD)) {
+		errs.Add("starts_at", consts.VALIDATION_OUT_OF_RANGE,
+			fmt.Sprintf("starts_at should be within %d days !", consts.SLOT_MAX_DAYS_AHEAD))
+	}
+	if req.EndsAt.IsZero() {
+		errs.Add("ends_at", consts.VALIDATION_REQUIRED, "ends_at is required !")
+	} else if !req.StartsAt.IsZero() {
+		length := req.EndsAt.Sub(req.StartsAt)
+		if length < consts.SLOT_MIN_MINUTES*time.Minute || length > consts.SLOT_MAX_MINUTES*time.Minute {
+			errs.Add("ends_at", consts.VALIDATION_OUT_OF_RANGE,
+				fmt.Sprintf("a slot should last between %d and %d minutes !", consts.SLOT_MIN_MINUTES, consts.SLOT_MAX_MINUTES))
+		}
 	}
 
-	age, ok := jsonBody["PlaneAge"].(float64)
-	if !ok {
-		msg = "Age should be a number !"
-		return msg, models.Ad{}, errors.New("")
+	return models.ExpertSlot{
+		StartsAt:  req.StartsAt.UTC(),
+		EndsAt:    req.EndsAt.UTC(),
+		Location:  location,
+		CreatedAt: now,
+	}, errs
+}
+
+// ValidateInspectionTemplate checks the items of an inspection template and
+// turns them into template items in the order they were given.
+func ValidateInspectionTemplate(req models.InspectionTemplateRequest) ([]models.InspectionTemplateItem, ValidationErrors) {
+	var errs ValidationErrors
+	if strings.TrimSpace(req.Name) == "" {
+		errs.Add("name", consts.VALIDATION_REQUIRED, "name is required !")
 	}
-	if math.Mod(age, 1) != 0 {
-		msg = "Age should be an integer !"
-		return msg, models.Ad{}, errors.New("")
+	if len(req.Items) == 0 {
+		errs.Add("items", consts.VALIDATION_REQUIRED, "a template needs at least one item !")
 	}
-	if uint(time.Now().Year())-uint(age) < 1903 {
-		msg = "The year of the invention of the airplane was 1903 !"
-		return msg, models.Ad{}, errors.New("")
+
+	items := []models.InspectionTemplateItem{}
+	for i, item := range req.Items {
+		if !isInspectionSection(item.Section) {
+			errs.Add(fmt.Sprintf("items[%d].section", i), consts.VALIDATION_INVALID,
+				fmt.Sprintf("section should be one of %s !", strings.Join(consts.INSPECTION_SECTIONS, ", ")))
+		}
+		label := strings.TrimSpace(item.Label)
+		if label == "" {
+			errs.Add(fmt.Sprintf("items[%d].label", i), consts.VALIDATION_REQUIRED, "label is required !")
+		}
+		items = append(items, models.InspectionTemplateItem{Section: item.Section, Label: label, Position: i})
 	}
-	ad.PlaneAge = uint(age)
+	return items, errs
+}
 
-	//check for optional properties
-	if _, ok := jsonBody["Image"]; ok {
-		if image, ok := jsonBody["Image"].(string); ok {
-			ad.Image = image
-		} else {
-			msg = "Image should be an url !"
-			return msg, models.Ad{}, errors.New("")
+// ValidateChecklist checks a filled checklist against its template, every
+// item of the template has to be rated once. Findings are required for
+// anything worse than no issue.
+func ValidateChecklist(template models.InspectionTemplate, req models.InspectionChecklistRequest) ([]models.InspectionItem, ValidationErrors) {
+	var errs ValidationErrors
+
+	templateItems := map[uint]models.InspectionTemplateItem{}
+	for _, item := range template.Items {
+		templateItems[item.ID] = item
+	}
+
+	items := []models.InspectionItem{}
+	filled := map[uint]bool{}
+	for i, item := range req.Items {
+		field := fmt.Sprintf("items[%d]", i)
+		templateItem, ok := templateItems[item.ItemID]
+		if !ok {
+			errs.Add(field+".item_id", consts.VALIDATION_UNKNOWN, fmt.Sprintf("%d is not an item of the template !", item.ItemID))
+			continue
 		}
-	} else {
-		ad.Image = "https://snipboard.io/d5viVR.jpg"
+		if filled[item.ItemID] {
+			errs.Add(field+".item_id", consts.VALIDATION_INVALID, fmt.Sprintf("item %d is filled more than once !", item.ItemID))
+			continue
+		}
+		filled[item.ItemID] = true
+
+		if item.Rating < consts.INSPECTION_MIN_RATING || item.Rating > consts.INSPECTION_MAX_RATING {
+			errs.Add(field+".rating", consts.VALIDATION_OUT_OF_RANGE,
+				fmt.Sprintf("rating should be between %d and %d !", consts.INSPECTION_MIN_RATING, consts.INSPECTION_MAX_RATING))
+		}
+		finding := strings.TrimSpace(item.Finding)
+		switch item.Severity {
+		case consts.SEVERITY_NONE:
+		case consts.SEVERITY_MINOR, consts.SEVERITY_MAJOR, consts.SEVERITY_CRITICAL:
+			if finding == "" {
+				errs.Add(field+".finding", consts.VALIDATION_REQUIRED, "a finding is required when there is an issue !")
+			}
+		default:
+			errs.Add(field+".severity", consts.VALIDATION_INVALID, "severity should be one of none, minor, major, critical !")
+		}
+
+		items = append(items, models.InspectionItem{
+			TemplateItemID: templateItem.ID,
+			Section:        templateItem.Section,
+			Label:          templateItem.Label,
+			Position:       templateItem.Position,
+			Rating:         item.Rating,
+			Finding:        finding,
+			Severity:       item.Severity,
+		})
 	}
 
-	if _, ok := jsonBody["Subject"]; ok {
-		if sub, ok := jsonBody["Subject"].(string); ok {
-			ad.Subject = sub
-		} else {
-			msg = "subject should be string !"
-			return msg, models.Ad{}, errors.New("")
+	for _, item := range template.Items {
+		if !filled[item.ID] {
+			errs.Add("items", consts.VALIDATION_REQUIRED, fmt.Sprintf("item %d (%s) is not filled !", item.ID, item.Label))
 		}
-	} else {
-		ad.Subject = fmt.Sprintf("%d years old airplane : %s in the %s category", ad.PlaneAge, ad.AirplaneModel, cat.Name)
 	}
+	return items, errs
+}
 
-	if _, ok := jsonBody["Description"]; ok {
-		if desc, ok := jsonBody["Description"].(string); ok {
-			ad.Description = desc
-		} else {
-			msg = "description should be string !"
-			return msg, models.Ad{}, errors.New("")
+func isInspectionSection(section string) bool {
+	for _, s := range consts.INSPECTION_SECTIONS {
+		if section == s {
+			return true
 		}
-	} else {
-		ad.Description = fmt.Sprintf("Model : %s | Age : %d | Category : %s | Price : %d | Fly Time : %d | Has Expert Check : %v | Has Repair Check : %v", ad.AirplaneModel, ad.PlaneAge, cat.Name, ad.Price, ad.FlyTime, ad.ExpertCheck, ad.RepairCheck)
 	}
+	return false
+}
 
-	return msg, ad, nil
+func validateStatus(errs *ValidationErrors, status consts.Status, allowed ...consts.Status) {
+	for _, s := range allowed {
+		if status == s {
+			return
+		}
+	}
+	if status == consts.WAIT_FOR_PAYMENT_STATUS {
+		errs.Add("status", consts.VALIDATION_NOT_ALLOWED, "only the payment can change this status !")
+		return
+	}
+	errs.Add("status", consts.VALIDATION_INVALID, fmt.Sprintf("%s is not a status !", status))
+}
 
+// ValidateImageURL checks an image reference the same way ad images are
+// stored: an absolute http(s) URL.
+func ValidateImageURL(image string) bool {
+	u, err := url.ParseRequestURI(image)
+	if err != nil {
+		return false
+	}
+	return (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
 }
 
 // This Function Validates Input Email.